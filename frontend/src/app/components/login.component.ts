@@ -120,9 +120,11 @@ export class LoginComponent implements DoCheck {
         if (err.status === 0 || err.status === undefined) {
           this.error = 'No se puede conectar con el servidor. Verifica que las APIs estén levantadas y que la configuración del frontend sea correcta.';
         } else if (err.status === 401) {
-          this.error = err.error?.message || 'Credenciales incorrectas';
+          this.error = err.error?.detail || err.error?.message || 'Credenciales incorrectas';
         } else if (err.status === 400) {
-          this.error = err.error?.error || err.error?.message || 'Datos inválidos';
+          this.error = err.error?.detail || err.error?.error || err.error?.message || 'Datos inválidos';
+        } else if (err.error?.detail) {
+          this.error = err.error.detail;
         } else if (err.error?.error) {
           this.error = err.error.error;
         } else if (err.error?.message) {
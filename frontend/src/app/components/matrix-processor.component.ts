@@ -126,7 +126,7 @@ export class MatrixProcessorComponent {
    * Maneja errores del procesamiento
    */
   private handleError(err: any): void {
-    this.error = err.error?.error || 'Error al procesar la matriz';
+    this.error = err.error?.detail || err.error?.error || 'Error al procesar la matriz';
     this.loading = false;
   }
 }
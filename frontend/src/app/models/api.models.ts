@@ -28,5 +28,21 @@ export interface MatrixProcessResponse {
   r: number[][];
   nodeStats?: MatrixStats;
   error?: string;
+  errorCode?: string;
+}
+
+/**
+ * Error de la API en formato RFC 7807 (application/problem+json)
+ * `code` es estable; `detail` viene localizado según Accept-Language
+ */
+export interface ProblemDetails {
+  type: string;
+  title: string;
+  status: number;
+  detail: string;
+  instance?: string;
+  code: string;
+  details?: Record<string, unknown>;
+  requestId?: string;
 }
 
@@ -27,14 +27,14 @@ export class ApiService {
   }
 
   login(credentials: LoginRequest): Observable<LoginResponse> {
-    console.log('API Service - Login URL:', `${this.GO_API_URL}/auth/login`);
+    console.log('API Service - Login URL:', `${this.GO_API_URL}/v1/auth/login`);
     console.log('API Service - Credentials:', credentials);
     
     // Para login no necesitamos el token, así que creamos headers sin Authorization
     const headers = new HttpHeaders().set('Content-Type', 'application/json');
     
     return this.http.post<LoginResponse>(
-      `${this.GO_API_URL}/auth/login`,
+      `${this.GO_API_URL}/v1/auth/login`,
       credentials,
       { headers }
     );
@@ -43,7 +43,7 @@ export class ApiService {
   processMatrix(matrix: number[][]): Observable<MatrixProcessResponse> {
     const request: MatrixRequest = { matrix };
     return this.http.post<MatrixProcessResponse>(
-      `${this.GO_API_URL}/matrix/process`,
+      `${this.GO_API_URL}/v1/matrix/process`,
       request,
       { headers: this.getHeaders() }
     );
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"go-api/internal/cache"
+	"go-api/internal/docs"
+	"go-api/internal/grpcapi"
+	"go-api/internal/handlers"
+	"go-api/internal/idempotency"
+	"go-api/internal/jobs"
+	"go-api/internal/metrics"
+	"go-api/internal/middleware"
+	"go-api/internal/services"
+	"go-api/internal/workspaces"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"google.golang.org/grpc"
+)
+
+var (
+	startTime = time.Now()
+	version   = "1.0.0"
+)
+
+// newApp crea la app Fiber con todos los middlewares y rutas y el servidor gRPC, que
+// comparte con ella los procesadores (main lo escucha en GRPC_PORT).
+// Separado de main para poder inspeccionar las rutas registradas en tests.
+// El pool de jobs asíncronos arranca aquí y se detiene al apagar la app, junto con el servidor gRPC.
+func newApp(nodeURL string) (*fiber.App, *grpc.Server, error) {
+	// Crear cliente para Node.js
+	nodeClient := services.NewNodeClient(nodeURL)
+
+	// Crear procesador y handler
+	processor := services.NewMatrixProcessor(nodeClient)
+	// Las matrices dispersas se densifican para calcular QR (y se rotan) hasta SPARSE_MAX_ELEMENTS elementos
+	processor.SparseMaxElements = getEnvInt("SPARSE_MAX_ELEMENTS", services.DefaultSparseMaxElements)
+	// Modo de precisión (big.Float): hasta PRECISION_MAX_BITS bits y PRECISION_MAX_ELEMENTS elementos
+	processor.PrecisionMaxBits = uint(getEnvInt("PRECISION_MAX_BITS", services.DefaultPrecisionMaxBits))
+	processor.PrecisionMaxElements = getEnvInt("PRECISION_MAX_ELEMENTS", services.DefaultPrecisionMaxElements)
+	// Modo explicación (traza de Gram-Schmidt): hasta EXPLAIN_MAX_ELEMENTS elementos
+	processor.ExplainMaxElements = getEnvInt("EXPLAIN_MAX_ELEMENTS", services.DefaultExplainMaxElements)
+	matrixHandler := handlers.NewMatrixHandler(processor)
+
+	// Cache de resultados por contenido: LRU en memoria con respaldo opcional en SQLite
+	resultCache, err := newResultCache()
+	if err != nil {
+		return nil, nil, err
+	}
+	matrixHandler.Cache = resultCache
+	cacheHandler := handlers.NewCacheHandler(resultCache)
+
+	// Lotes: pool acotado de workers y estadísticas de Node.js agrupadas
+	batchProcessor := services.NewBatchProcessor(processor, getEnvInt("BATCH_WORKERS", runtime.NumCPU()))
+	batchProcessor.NodeBatchSize = getEnvInt("BATCH_NODE_CHUNK_SIZE", batchProcessor.NodeBatchSize)
+	batchHandler := handlers.NewBatchHandler(batchProcessor, services.BatchLimits{
+		MaxItems:    getEnvInt("BATCH_MAX_ITEMS", 1000),
+		MaxElements: getEnvInt("BATCH_MAX_ELEMENTS", 250_000),
+	})
+	// Los lotes NDJSON se procesan en streaming con memoria constante: admiten más elementos
+	batchHandler.StreamLimits = services.BatchLimits{
+		MaxItems:    getEnvInt("BATCH_STREAM_MAX_ITEMS", 100_000),
+		MaxElements: getEnvInt("BATCH_STREAM_MAX_ELEMENTS", 25_000_000),
+	}
+
+	// gRPC: mismas operaciones y lotes en streaming bidireccional, con los límites de los lotes NDJSON
+	grpcServer := grpcapi.NewGRPCServer(grpcapi.NewServer(processor, batchProcessor, batchHandler.StreamLimits))
+
+	// GraphQL: process calcula solo los campos seleccionados
+	graphqlHandler, err := handlers.NewGraphQLHandler(processor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Modo exacto: fracciones con math/big, matrices de hasta EXACT_MAX_DIMENSION filas y columnas
+	exactHandler := handlers.NewExactHandler()
+	exactHandler.MaxDimension = getEnvInt("EXACT_MAX_DIMENSION", exactHandler.MaxDimension)
+
+	// Sesiones interactivas por WebSocket: recalculan la matriz tras WS_DEBOUNCE sin cambios
+	sessionHandler := handlers.NewSessionHandler(processor)
+	sessionHandler.Debounce = getEnvDuration("WS_DEBOUNCE", sessionHandler.Debounce)
+
+	// Jobs asíncronos: pool acotado de workers sobre un store en memoria o SQLite
+	jobStore, err := newJobStore()
+	if err != nil {
+		resultCache.Close()
+		return nil, nil, err
+	}
+	// Los webhooks de fin de job se firman con WEBHOOK_SECRET (sin él se rechazan los callbackUrl)
+	notifier := jobs.NewNotifier([]byte(os.Getenv("WEBHOOK_SECRET")))
+	notifier.MaxAttempts = getEnvInt("WEBHOOK_MAX_ATTEMPTS", notifier.MaxAttempts)
+	notifier.Backoff = getEnvDuration("WEBHOOK_BACKOFF", notifier.Backoff)
+	// Solo se entregan webhooks a IPs públicas, salvo las redes de WEBHOOK_ALLOWED_NETWORKS (desarrollo)
+	notifier.AllowedNetworks = getEnvPrefixes("WEBHOOK_ALLOWED_NETWORKS")
+	jobManager := jobs.NewManager(jobStore, processor, jobs.Config{
+		Workers:   getEnvInt("JOBS_WORKERS", runtime.NumCPU()),
+		QueueSize: getEnvInt("JOBS_QUEUE_SIZE", 100),
+		ResultTTL: getEnvDuration("JOBS_RESULT_TTL", time.Hour),
+		Notifier:  notifier,
+	})
+	if err := jobManager.Start(context.Background()); err != nil {
+		jobStore.Close()
+		resultCache.Close()
+		return nil, nil, fmt.Errorf("failed to start job manager: %w", err)
+	}
+	jobHandler := handlers.NewJobHandler(jobManager)
+
+	// Workspaces: factorizaciones QR guardadas en memoria que se actualizan por operaciones
+	workspaceManager := workspaces.NewManager(workspaces.Config{
+		MaxPerUser:    getEnvInt("WORKSPACE_MAX_PER_USER", 10),
+		MaxElements:   getEnvInt("WORKSPACE_MAX_ELEMENTS", 1_000_000),
+		IdleTTL:       getEnvDuration("WORKSPACE_TTL", 30*time.Minute),
+		RefactorEvery: getEnvInt("WORKSPACE_REFACTOR_EVERY", 1000),
+	})
+	workspaceManager.Start()
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceManager)
+
+	// Idempotency-Key: la primera respuesta de cada clave se reproduce en los reintentos
+	idempotencyStore := idempotency.NewStore(idempotency.Config{
+		TTL:        getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		MaxEntries: getEnvInt("IDEMPOTENCY_MAX_ENTRIES", 10000),
+		MaxBytes:   int64(getEnvInt("IDEMPOTENCY_MAX_BYTES", 64<<20)),
+	})
+	idempotencyStore.Start()
+
+	// Verificaciones de readiness: configuración requerida y disponibilidad de Node.js
+	// El sondeo a Node.js se cachea para no generar una petición por cada probe del orquestador
+	readiness := services.NewReadinessChecker(2 * time.Second)
+	readiness.Register("config", services.EnvCheck("NODE_API_URL", "JWT_SECRET"))
+	readiness.Register("node", services.CachedCheck(nodeClient.Ping, getEnvDuration("READINESS_CACHE_TTL", 5*time.Second)))
+	readiness.Register("jobStore", jobStore.Ping)
+	if backend := resultCache.Backend(); backend != nil {
+		readiness.Register("cache", backend.Ping)
+	}
+	healthHandler := handlers.NewHealthHandler(readiness)
+
+	// Crear app Fiber
+	app := fiber.New(fiber.Config{
+		// Todos los errores no manejados se responden como application/problem+json
+		ErrorHandler: middleware.WriteProblem,
+		// Los lotes NDJSON se leen en streaming; middleware.BodyLimit mantiene el límite en el resto
+		StreamRequestBody: true,
+	})
+
+	// Al apagar: esperar los jobs en ejecución (o marcarlos interrumpidos) y cerrar el store
+	app.Hooks().OnShutdown(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), getEnvDuration("JOBS_SHUTDOWN_TIMEOUT", 30*time.Second))
+		defer cancel()
+		stopGRPC(ctx, grpcServer)
+		if err := jobManager.Stop(ctx); err != nil {
+			slog.Warn("jobs interrupted on shutdown", "error", err)
+		}
+		workspaceManager.Stop()
+		idempotencyStore.Stop()
+		if err := resultCache.Close(); err != nil {
+			slog.Warn("failed to close result cache", "error", err)
+		}
+		return jobStore.Close()
+	})
+
+	// Middlewares
+	// RequestID va primero para que el ID esté disponible en logs, trazas y errores
+	app.Use(middleware.RequestID)
+	app.Use(middleware.AccessLog)
+	// Tracing envuelve el resto para que el span de la petición cubra todo el procesamiento
+	app.Use(middleware.Tracing)
+	// Metrics va antes de recover para registrar también las peticiones que terminan en panic
+	app.Use(middleware.Metrics)
+	app.Use(recover.New())
+	app.Use(middleware.BodyLimit(fiber.DefaultBodyLimit, func(c *fiber.Ctx) bool {
+		return c.Path() == "/v1/matrix/batch" && middleware.IsNDJSON(c)
+	}))
+
+	// CORS para permitir requests desde el frontend
+	// IMPORTANTE: Debe estar ANTES de las rutas para manejar OPTIONS
+	app.Use(cors.New(cors.Config{
+		AllowOrigins:     "*",
+		AllowMethods:     "GET, POST, PUT, DELETE, OPTIONS, HEAD",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Request-ID, If-None-Match, Idempotency-Key",
+		AllowCredentials: false,
+		ExposeHeaders:    "Content-Length, X-Request-ID, Deprecation, Sunset, Link, ETag, X-Cache, Idempotent-Replayed, Content-Disposition",
+		MaxAge:           86400, // 24 horas
+	}))
+
+	// Handler manual para OPTIONS (preflight requests)
+	app.Options("*", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// Health checks: /health y /livez son liveness puro, /readyz verifica dependencias
+	app.Get("/health", healthHandler.Liveness)
+	app.Get("/livez", healthHandler.Liveness)
+	app.Get("/readyz", healthHandler.Readiness)
+
+	// Métricas Prometheus
+	app.Get("/metrics", adaptor.HTTPHandler(metrics.Handler()))
+
+	// Documentación OpenAPI 3 y Swagger UI
+	app.Get("/openapi.json", docs.Spec)
+	app.Get("/docs", docs.RedirectToUI)
+	app.Get("/docs/swagger-initializer.js", docs.Initializer)
+	app.Use("/docs", docs.UI())
+
+	// Endpoint informativo del backend (público)
+	app.Get("/", func(c *fiber.Ctx) error {
+		uptime := time.Since(startTime)
+		return c.JSON(fiber.Map{
+			"service":       "Go API Backend",
+			"version":       version,
+			"technology":    "Go (Golang)",
+			"framework":     "Fiber v2",
+			"goVersion":     runtime.Version(),
+			"startTime":     startTime.Format(time.RFC3339),
+			"uptime":        uptime.String(),
+			"uptimeSeconds": int(uptime.Seconds()),
+			"os":            runtime.GOOS,
+			"arch":          runtime.GOARCH,
+			"nodeApiUrl":    nodeURL,
+			"endpoints": fiber.Map{
+				"health":        "GET /health",
+				"liveness":      "GET /livez",
+				"readiness":     "GET /readyz",
+				"metrics":       "GET /metrics",
+				"openapi":       "GET /openapi.json",
+				"docs":          "GET /docs",
+				"login":         "POST /v1/auth/login",
+				"processMatrix": "POST /v1/matrix/process (requiere JWT)",
+				"batch":         "POST /v1/matrix/batch (requiere JWT)",
+				"exact":         "POST /v1/matrix/exact (requiere JWT)",
+				"graphql":       "POST /v1/graphql (requiere JWT)",
+				"session":       "GET /v1/matrix/session (WebSocket, requiere JWT)",
+				"jobs":          "POST /v1/jobs, GET /v1/jobs/:id, GET /v1/jobs/:id/deliveries, DELETE /v1/jobs/:id (requiere JWT)",
+				"workspaces":    "POST /v1/workspaces, GET /v1/workspaces/:id, POST /v1/workspaces/:id/updates, DELETE /v1/workspaces/:id (requiere JWT)",
+				"adminCache":    "GET /v1/admin/cache, DELETE /v1/admin/cache, DELETE /v1/admin/cache/:key (requiere JWT con rol admin)",
+				"info":          "GET /",
+				"deprecated":    "POST /auth/login y POST /matrix/process (alias de /v1, ver headers Deprecation/Sunset)",
+				"grpc":          "matrix.v1.MatrixService en GRPC_PORT (requiere JWT en la metadata authorization)",
+			},
+		})
+	})
+
+	// Rutas de la API versionada (/v1) y alias obsoletos sin versión
+	registerAPIRoutes(app, apiHandlers{
+		matrix:     matrixHandler,
+		jobs:       jobHandler,
+		batch:      batchHandler,
+		session:    sessionHandler,
+		workspaces: workspaceHandler,
+		cache:      cacheHandler,
+		graphql:    graphqlHandler,
+		exact:      exactHandler,
+		idempotent: middleware.Idempotency(idempotencyStore),
+	})
+
+	return app, grpcServer, nil
+}
+
+// stopGRPC espera a que terminen las llamadas gRPC en curso; si ctx vence antes, las corta
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// newResultCache crea el cache de resultados según CACHE_BACKEND: memory (por defecto)
+// o sqlite (los resultados sobreviven a reinicios)
+func newResultCache() (*cache.Cache, error) {
+	cfg := cache.Config{
+		MaxEntries: getEnvInt("CACHE_MAX_ENTRIES", 1000),
+		MaxBytes:   int64(getEnvInt("CACHE_MAX_BYTES", 64<<20)),
+		TTL:        getEnvDuration("CACHE_TTL", 10*time.Minute),
+	}
+	switch kind := os.Getenv("CACHE_BACKEND"); kind {
+	case "", "memory":
+		return cache.New(cfg, nil), nil
+	case "sqlite":
+		path := os.Getenv("CACHE_SQLITE_PATH")
+		if path == "" {
+			path = "cache.db"
+		}
+		backend, err := cache.NewSQLiteBackend(path)
+		if err != nil {
+			return nil, err
+		}
+		return cache.New(cfg, backend), nil
+	default:
+		return nil, fmt.Errorf("invalid CACHE_BACKEND %q (use memory or sqlite)", kind)
+	}
+}
+
+// newJobStore crea el store de jobs según JOBS_STORE: memory (por defecto) o sqlite
+func newJobStore() (jobs.Store, error) {
+	switch kind := os.Getenv("JOBS_STORE"); kind {
+	case "", "memory":
+		return jobs.NewMemoryStore(), nil
+	case "sqlite":
+		path := os.Getenv("JOBS_SQLITE_PATH")
+		if path == "" {
+			path = "jobs.db"
+		}
+		return jobs.NewSQLiteStore(path)
+	default:
+		return nil, fmt.Errorf("invalid JOBS_STORE %q (use memory or sqlite)", kind)
+	}
+}
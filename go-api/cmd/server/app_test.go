@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-api/internal/docs"
+)
+
+// undocumentedPrefixes rutas que no forman parte de la API (archivos estáticos de Swagger UI)
+var undocumentedPrefixes = []string{"/docs/"}
+
+// pathParam convierte parámetros de Fiber (:id) al formato de OpenAPI ({id})
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// TestRoutesAreDocumented falla si una ruta registrada en newApp no está en openapi.json
+// (o si la especificación documenta una ruta que ya no existe)
+func TestRoutesAreDocumented(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(docs.OpenAPI, &spec); err != nil {
+		t.Fatalf("openapi.json no es JSON válido: %v", err)
+	}
+
+	app, _, err := newApp("http://localhost:9999")
+	if err != nil {
+		t.Fatalf("newApp: %v", err)
+	}
+
+	registered := make(map[string]bool)
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == http.MethodHead || route.Method == http.MethodOptions || route.Path == "*" {
+			continue
+		}
+		if hasAnyPrefix(route.Path, undocumentedPrefixes) {
+			continue
+		}
+
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		method := strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("La ruta %s %s no está documentada en openapi.json", route.Method, path)
+		}
+	}
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			if !registered[method+" "+path] {
+				t.Errorf("openapi.json documenta %s %s pero la ruta no está registrada", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestDocsEndpoints(t *testing.T) {
+	app, _, err := newApp("http://localhost:9999")
+	if err != nil {
+		t.Fatalf("newApp: %v", err)
+	}
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		contains       string
+	}{
+		{path: "/openapi.json", expectedStatus: http.StatusOK, contains: `"openapi": "3.0.3"`},
+		{path: "/docs", expectedStatus: http.StatusMovedPermanently},
+		{path: "/docs/", expectedStatus: http.StatusMovedPermanently},
+		{path: "/docs/index.html", expectedStatus: http.StatusOK, contains: "swagger-ui"},
+		{path: "/docs/swagger-initializer.js", expectedStatus: http.StatusOK, contains: "/openapi.json"},
+		{path: "/docs/swagger-ui-bundle.js", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if tt.contains != "" {
+				body, _ := io.ReadAll(resp.Body)
+				if !strings.Contains(string(body), tt.contains) {
+					t.Errorf("La respuesta no contiene %q", tt.contains)
+				}
+			}
+		})
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
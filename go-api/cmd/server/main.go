@@ -1,28 +1,23 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"net"
+	"net/netip"
 	"os"
-	"runtime"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
-	"go-api/internal/controllers"
-	"go-api/internal/handlers"
-	"go-api/internal/middleware"
-	"go-api/internal/services"
+	"go-api/internal/logging"
+	"go-api/internal/tracing"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/joho/godotenv"
 )
 
-var (
-	startTime = time.Now()
-	version   = "1.0.0"
-)
-
 func main() {
 	// Cargar variables de entorno desde .env (solo en desarrollo local)
 	// En producción/Docker, las variables vienen del sistema
@@ -30,88 +25,37 @@ func main() {
 		godotenv.Load()
 	}
 
+	// Logging estructurado en JSON (LOG_LEVEL: debug, info, warn, error)
+	level, err := logging.ParseLevel(os.Getenv("LOG_LEVEL"))
+	slog.SetDefault(logging.New(os.Stdout, level))
+	if err != nil {
+		slog.Warn("invalid LOG_LEVEL, using info", "error", err)
+	}
+
 	// Obtener URL de Node.js desde variable de entorno (OBLIGATORIO)
 	nodeURL := os.Getenv("NODE_API_URL")
 	if nodeURL == "" {
-		log.Fatal("NODE_API_URL no está configurado (defínelo en el .env/variables de entorno)")
+		fatal("NODE_API_URL is not set (define it in .env or the environment)")
 	}
 
-	// Crear cliente para Node.js
-	nodeClient := services.NewNodeClient(nodeURL)
-
-	// Crear handler
-	matrixHandler := handlers.NewMatrixHandler(nodeClient)
-
-	// Crear app Fiber
-	app := fiber.New(fiber.Config{
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
-			return c.Status(code).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		},
-	})
-
-	// Middlewares
-	app.Use(recover.New())
-	app.Use(logger.New())
-
-	// CORS para permitir requests desde el frontend
-	// IMPORTANTE: Debe estar ANTES de las rutas para manejar OPTIONS
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     "*",
-		AllowMethods:     "GET, POST, PUT, DELETE, OPTIONS, HEAD",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
-		AllowCredentials: false,
-		ExposeHeaders:    "Content-Length",
-		MaxAge:           86400, // 24 horas
-	}))
-
-	// Handler manual para OPTIONS (preflight requests)
-	app.Options("*", func(c *fiber.Ctx) error {
-		return c.SendStatus(fiber.StatusOK)
-	})
-
-	// Health check
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":  "ok",
-			"service": "go-api",
-		})
-	})
-
-	// Rutas públicas (sin autenticación)
-	app.Post("/auth/login", controllers.Login)
-
-	// Endpoint informativo del backend (público)
-	app.Get("/", func(c *fiber.Ctx) error {
-		uptime := time.Since(startTime)
-		return c.JSON(fiber.Map{
-			"service":       "Go API Backend",
-			"version":       version,
-			"technology":    "Go (Golang)",
-			"framework":     "Fiber v2",
-			"goVersion":     runtime.Version(),
-			"startTime":     startTime.Format(time.RFC3339),
-			"uptime":        uptime.String(),
-			"uptimeSeconds": int(uptime.Seconds()),
-			"os":            runtime.GOOS,
-			"arch":          runtime.GOARCH,
-			"nodeApiUrl":    nodeURL,
-			"endpoints": fiber.Map{
-				"health":        "GET /health",
-				"login":         "POST /auth/login",
-				"processMatrix": "POST /matrix/process (requiere JWT)",
-				"info":          "GET /",
-			},
-		})
-	})
-
-	// Rutas protegidas (requieren JWT)
-	app.Post("/matrix/process", middleware.AuthenticateToken, matrixHandler.ProcessMatrix)
+	// Configurar tracing OpenTelemetry (OTEL_TRACES_EXPORTER: otlp, stdout o none)
+	shutdownTracing, err := tracing.Setup(context.Background(), os.Getenv("OTEL_TRACES_EXPORTER"), version)
+	if err != nil {
+		fatal("failed to configure tracing", "error", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("failed to shut down trace exporter", "error", err)
+		}
+	}()
+
+	// Crear app Fiber con middlewares y rutas, y el servidor gRPC
+	app, grpcServer, err := newApp(nodeURL)
+	if err != nil {
+		fatal("failed to create app", "error", err)
+	}
 
 	// Iniciar servidor
 	port := os.Getenv("PORT")
@@ -119,10 +63,87 @@ func main() {
 		port = "3000"
 	}
 
-	log.Printf("Servidor Go iniciado en puerto %s", port)
-	log.Printf("Conectado a Node.js API en: %s", nodeURL)
+	// gRPC escucha en su propio puerto; se detiene junto con la app (ver newApp)
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "50051"
+	}
+	listener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		fatal("failed to listen for grpc", "port", grpcPort, "error", err)
+	}
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			fatal("failed to start grpc server", "error", err)
+		}
+	}()
+
+	slog.Info("go server started", "port", port, "grpcPort", grpcPort, "nodeApiUrl", nodeURL)
+
+	// Apagado ordenado: al recibir SIGINT/SIGTERM se cierran las conexiones
+	// y se ejecutan los defers (ej: vaciar las trazas pendientes)
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		slog.Info("shutting down server")
+		app.ShutdownWithTimeout(10 * time.Second)
+	}()
 
 	if err := app.Listen(":" + port); err != nil {
-		log.Fatalf("Error al iniciar servidor: %v", err)
+		fatal("failed to start server", "error", err)
+	}
+}
+
+// getEnvDuration lee una duración (ej: "5s") desde una variable de entorno o retorna el valor por defecto
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
 	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("invalid duration in environment, using default", "key", key, "value", value, "default", def.String())
+		return def
+	}
+	return d
+}
+
+// getEnvInt lee un entero positivo desde una variable de entorno o retorna el valor por defecto
+func getEnvInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		slog.Warn("invalid integer in environment, using default", "key", key, "value", value, "default", def)
+		return def
+	}
+	return n
+}
+
+// getEnvPrefixes lee una lista de redes CIDR separadas por comas (ej: "127.0.0.0/8,::1/128");
+// las entradas inválidas se ignoran con un aviso
+func getEnvPrefixes(key string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			slog.Warn("invalid network in environment, ignoring it", "key", key, "value", value)
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
+
+// fatal registra el error y termina el proceso (equivalente a log.Fatal con slog)
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
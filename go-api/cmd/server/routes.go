@@ -0,0 +1,93 @@
+package main
+
+import (
+	"time"
+
+	"go-api/internal/controllers"
+	"go-api/internal/handlers"
+	"go-api/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Fechas de obsolescencia de las rutas sin versión (/auth/login, /matrix/process)
+var (
+	legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	legacySunset       = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// route describe una ruta de la API versionada
+type route struct {
+	method   string
+	path     string
+	handlers []fiber.Handler
+}
+
+// apiHandlers agrupa los handlers que usan las distintas versiones de la API
+type apiHandlers struct {
+	matrix     *handlers.MatrixHandler
+	jobs       *handlers.JobHandler
+	batch      *handlers.BatchHandler
+	session    *handlers.SessionHandler
+	workspaces *handlers.WorkspaceHandler
+	cache      *handlers.CacheHandler
+	graphql    *handlers.GraphQLHandler
+	exact      *handlers.ExactHandler
+	// idempotent middleware de Idempotency-Key para las rutas que crean o modifican recursos
+	idempotent fiber.Handler
+}
+
+// v1Routes rutas de la versión 1 de la API.
+// Una versión nueva (v2) define su propia función con sus propios handlers/modelos
+// de respuesta y se monta con mountRoutes bajo su prefijo, sin afectar a v1.
+func v1Routes(h apiHandlers) []route {
+	return []route{
+		// Rutas públicas (sin autenticación)
+		{fiber.MethodPost, "/auth/login", []fiber.Handler{controllers.Login}},
+		// Rutas protegidas (requieren JWT)
+		{fiber.MethodPost, "/matrix/process", []fiber.Handler{middleware.AuthenticateToken, h.idempotent, h.matrix.ProcessMatrix}},
+		{fiber.MethodPost, "/matrix/exact", []fiber.Handler{middleware.AuthenticateToken, h.exact.Compute}},
+		{fiber.MethodPost, "/matrix/batch", []fiber.Handler{middleware.AuthenticateToken, h.batch.ProcessBatch}},
+		{fiber.MethodPost, "/graphql", []fiber.Handler{middleware.AuthenticateToken, h.graphql.Query}},
+		{fiber.MethodGet, "/matrix/session", []fiber.Handler{middleware.AuthenticateWebSocket, h.session.Connect}},
+		{fiber.MethodPost, "/jobs", []fiber.Handler{middleware.AuthenticateToken, h.idempotent, h.jobs.CreateJob}},
+		{fiber.MethodGet, "/jobs/:id", []fiber.Handler{middleware.AuthenticateToken, h.jobs.GetJob}},
+		{fiber.MethodGet, "/jobs/:id/deliveries", []fiber.Handler{middleware.AuthenticateToken, h.jobs.GetJobDeliveries}},
+		{fiber.MethodDelete, "/jobs/:id", []fiber.Handler{middleware.AuthenticateToken, h.jobs.CancelJob}},
+		{fiber.MethodPost, "/workspaces", []fiber.Handler{middleware.AuthenticateToken, h.idempotent, h.workspaces.CreateWorkspace}},
+		{fiber.MethodGet, "/workspaces/:id", []fiber.Handler{middleware.AuthenticateToken, h.workspaces.GetWorkspace}},
+		{fiber.MethodPost, "/workspaces/:id/updates", []fiber.Handler{middleware.AuthenticateToken, h.idempotent, h.workspaces.UpdateWorkspace}},
+		{fiber.MethodDelete, "/workspaces/:id", []fiber.Handler{middleware.AuthenticateToken, h.workspaces.DeleteWorkspace}},
+		// Administración (requiere JWT con rol admin)
+		{fiber.MethodGet, "/admin/cache", []fiber.Handler{middleware.AuthenticateToken, middleware.RequireRole("admin"), h.cache.GetCache}},
+		{fiber.MethodDelete, "/admin/cache", []fiber.Handler{middleware.AuthenticateToken, middleware.RequireRole("admin"), h.cache.PurgeCache}},
+		{fiber.MethodDelete, "/admin/cache/:key", []fiber.Handler{middleware.AuthenticateToken, middleware.RequireRole("admin"), h.cache.DeleteCacheEntry}},
+	}
+}
+
+// legacyRoutes rutas sin versión que existían antes de /v1; se mantienen como alias
+// obsoletos hasta legacySunset. Las rutas nuevas solo existen bajo /v1.
+func legacyRoutes(h apiHandlers) []route {
+	var legacy []route
+	for _, rt := range v1Routes(h) {
+		if rt.path == "/auth/login" || rt.path == "/matrix/process" {
+			legacy = append(legacy, rt)
+		}
+	}
+	return legacy
+}
+
+// mountRoutes registra las rutas en el router; los middlewares extra se ejecutan antes de cada ruta
+func mountRoutes(r fiber.Router, routes []route, extra ...fiber.Handler) {
+	for _, rt := range routes {
+		handlers := append(append([]fiber.Handler{}, extra...), rt.handlers...)
+		r.Add(rt.method, rt.path, handlers...)
+	}
+}
+
+// registerAPIRoutes monta cada versión bajo su prefijo y mantiene las rutas sin versión
+// como alias obsoletos de v1 (con headers Deprecation, Sunset y Link)
+func registerAPIRoutes(app *fiber.App, h apiHandlers) {
+	mountRoutes(app.Group("/v1"), v1Routes(h))
+	mountRoutes(app, legacyRoutes(h), middleware.Deprecated(legacyDeprecatedAt, legacySunset, "/v1"))
+}
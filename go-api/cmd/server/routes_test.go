@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestVersionedRoutes(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+
+	app, _, err := newApp("http://localhost:9999")
+	if err != nil {
+		t.Fatalf("newApp: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		wantDeprecated bool
+		wantSuccessor  string
+	}{
+		{name: "ruta v1 vigente", path: "/v1/auth/login", wantDeprecated: false},
+		{name: "alias sin versión obsoleto", path: "/auth/login", wantDeprecated: true, wantSuccessor: "</v1/auth/login>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(`{"username":"admin","password":"admin"}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+
+			// Ambas rutas deben comportarse igual
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Status code = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			deprecation := resp.Header.Get("Deprecation")
+			sunset := resp.Header.Get("Sunset")
+			if tt.wantDeprecated {
+				if !strings.HasPrefix(deprecation, "@") || sunset == "" {
+					t.Errorf("Deprecation = %q, Sunset = %q, se esperaban ambos headers", deprecation, sunset)
+				}
+				if link := resp.Header.Get("Link"); !strings.HasPrefix(link, tt.wantSuccessor) {
+					t.Errorf("Link = %q, want prefijo %q", link, tt.wantSuccessor)
+				}
+			} else if deprecation != "" || sunset != "" {
+				t.Errorf("La ruta vigente no debe incluir Deprecation/Sunset (got %q, %q)", deprecation, sunset)
+			}
+		})
+	}
+}
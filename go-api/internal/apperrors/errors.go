@@ -0,0 +1,205 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	"go-api/internal/models"
+)
+
+// Code es un identificador estable de error pensado para clientes automatizados.
+// Los códigos no cambian aunque cambien los mensajes o el idioma.
+type Code string
+
+// Códigos de error de la API
+const (
+	CodeInvalidBody               Code = "INVALID_BODY"
+	CodeMatrixEmpty               Code = "MATRIX_EMPTY"
+	CodeMatrixRowEmpty            Code = "MATRIX_ROW_EMPTY"
+	CodeMatrixNotRectangular      Code = "MATRIX_NOT_RECTANGULAR"
+	CodeMatrixWiderThanTall       Code = "MATRIX_WIDER_THAN_TALL"
+	CodeQRFailed                  Code = "QR_DECOMPOSITION_FAILED"
+	CodeNodeStatsUnavailable      Code = "NODE_STATS_UNAVAILABLE"
+	CodeTokenMissing              Code = "TOKEN_MISSING"
+	CodeTokenMalformed            Code = "TOKEN_MALFORMED"
+	CodeTokenInvalid              Code = "TOKEN_INVALID"
+	CodeTokenExpired              Code = "TOKEN_EXPIRED"
+	CodeInvalidCredentials        Code = "INVALID_CREDENTIALS"
+	CodeTokenGenerationFailed     Code = "TOKEN_GENERATION_FAILED"
+	CodeServerMisconfigured       Code = "SERVER_MISCONFIGURED"
+	CodeBadRequest                Code = "BAD_REQUEST"
+	CodeNotFound                  Code = "NOT_FOUND"
+	CodeMethodNotAllowed          Code = "METHOD_NOT_ALLOWED"
+	CodePayloadTooLarge           Code = "PAYLOAD_TOO_LARGE"
+	CodeInternal                  Code = "INTERNAL_ERROR"
+	CodeJobNotFound               Code = "JOB_NOT_FOUND"
+	CodeJobQueueFull              Code = "JOB_QUEUE_FULL"
+	CodeJobNotCancelable          Code = "JOB_NOT_CANCELABLE"
+	CodeJobInvalidOperation       Code = "JOB_INVALID_OPERATION"
+	CodeJobInterrupted            Code = "JOB_INTERRUPTED"
+	CodeJobCanceled               Code = "JOB_CANCELED"
+	CodeJobInvalidCallback        Code = "JOB_INVALID_CALLBACK"
+	CodeBatchEmpty                Code = "BATCH_EMPTY"
+	CodeBatchTooManyItems         Code = "BATCH_TOO_MANY_ITEMS"
+	CodeBatchTooManyElements      Code = "BATCH_TOO_MANY_ELEMENTS"
+	CodeBatchDuplicateID          Code = "BATCH_DUPLICATE_ID"
+	CodeBatchInvalidOperation     Code = "BATCH_INVALID_OPERATION"
+	CodeSessionInvalidMessage     Code = "SESSION_INVALID_MESSAGE"
+	CodeSessionNoMatrix           Code = "SESSION_NO_MATRIX"
+	CodeSessionCellOutOfRange     Code = "SESSION_CELL_OUT_OF_RANGE"
+	CodeWorkspaceNotFound         Code = "WORKSPACE_NOT_FOUND"
+	CodeWorkspaceInvalidOperation Code = "WORKSPACE_INVALID_OPERATION"
+	CodeWorkspaceTooLarge         Code = "WORKSPACE_TOO_LARGE"
+	CodeWorkspaceLimitReached     Code = "WORKSPACE_LIMIT_REACHED"
+	CodeForbidden                 Code = "FORBIDDEN"
+	CodeIdempotencyKeyInvalid     Code = "IDEMPOTENCY_KEY_INVALID"
+	CodeIdempotencyKeyInUse       Code = "IDEMPOTENCY_KEY_IN_USE"
+	CodeIdempotencyKeyMismatch    Code = "IDEMPOTENCY_KEY_MISMATCH"
+	CodeMatrixParseError          Code = "MATRIX_PARSE_ERROR"
+	CodeSolveDimensionMismatch    Code = "SOLVE_DIMENSION_MISMATCH"
+	CodeMatrixRankDeficient       Code = "MATRIX_RANK_DEFICIENT"
+	CodeSparseInvalid             Code = "SPARSE_INVALID"
+	CodeSparseIndexOutOfRange     Code = "SPARSE_INDEX_OUT_OF_RANGE"
+	CodeSparseDuplicateEntry      Code = "SPARSE_DUPLICATE_ENTRY"
+	CodeSparseTooLarge            Code = "SPARSE_TOO_LARGE"
+	CodeExactInvalidNumber        Code = "EXACT_INVALID_NUMBER"
+	CodeExactTooLarge             Code = "EXACT_TOO_LARGE"
+	CodePrecisionInvalid          Code = "PRECISION_INVALID"
+	CodePrecisionTooLarge         Code = "PRECISION_TOO_LARGE"
+	CodeExplainTooLarge           Code = "EXPLAIN_TOO_LARGE"
+	CodeNonFiniteValue            Code = "NON_FINITE_VALUE"
+	CodeMatrixInputConflict       Code = "MATRIX_INPUT_CONFLICT"
+	CodeOptionNotApplicable       Code = "OPTION_NOT_APPLICABLE"
+	CodeResultFormatUnsupported   Code = "RESULT_FORMAT_UNSUPPORTED"
+	CodeOptionsConflict           Code = "OPTIONS_CONFLICT"
+	CodeInvalidExplainMethod      Code = "INVALID_EXPLAIN_METHOD"
+	CodeInvalidFormatOption       Code = "INVALID_FORMAT_OPTION"
+	CodeInvalidDelimiter          Code = "INVALID_DELIMITER"
+	CodeMatrixSelectionRequired   Code = "MATRIX_SELECTION_REQUIRED"
+	CodeMatrixDataLength          Code = "MATRIX_DATA_LENGTH"
+	CodeSolveNonFiniteValue       Code = "SOLVE_NON_FINITE_VALUE"
+	CodeIdempotencyStoreFull      Code = "IDEMPOTENCY_STORE_FULL"
+	CodePreconditionFailed        Code = "PRECONDITION_FAILED"
+	CodeGraphQLQueryRequired      Code = "GRAPHQL_QUERY_REQUIRED"
+	CodeMatrixValueInvalid        Code = "MATRIX_VALUE_INVALID"
+)
+
+// typeBaseURI prefijo del campo "type" de los problem+json (RFC 7807)
+const typeBaseURI = "urn:go-api:error:"
+
+// Error es un error de la API con código estable, código HTTP y detalles estructurados
+// (ej: el índice de la fila que rompe la rectangularidad)
+type Error struct {
+	Code    Code
+	Status  int
+	Details map[string]interface{}
+	Err     error
+}
+
+// New crea un error con el código dado; el código HTTP se toma del catálogo
+func New(code Code, details map[string]interface{}) *Error {
+	return &Error{
+		Code:    code,
+		Status:  statusFor(code),
+		Details: details,
+	}
+}
+
+// Wrap crea un error con el código dado conservando la causa original. La causa va en
+// Details["reason"] solo en los errores del cliente (4xx): en los 5xx puede tener texto
+// interno (SQL, rutas, mensajes de librerías), así que queda solo en Err para los logs.
+func Wrap(code Code, err error) *Error {
+	e := New(code, nil)
+	if err != nil && e.Status < http.StatusInternalServerError {
+		e.Details = map[string]interface{}{"reason": err.Error()}
+	}
+	e.Err = err
+	return e
+}
+
+// Error retorna el mensaje en el idioma por defecto (español); en los 5xx agrega la causa,
+// que no va en Details
+func (e *Error) Error() string {
+	if e.Err != nil && e.Status >= http.StatusInternalServerError {
+		return e.Message(DefaultLanguage) + ": " + e.Err.Error()
+	}
+	return e.Message(DefaultLanguage)
+}
+
+// Unwrap permite usar errors.Is/As sobre la causa original
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is compara errores de la API por código
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Message retorna el mensaje localizado con los detalles interpolados. Si los detalles
+// tienen reasonCode, {reason} es el texto localizado de ese motivo.
+func (e *Error) Message(lang string) string {
+	details := e.Details
+	if code, ok := details["reasonCode"].(string); ok {
+		details = maps.Clone(details)
+		details["reason"] = reasonText(code, lang, e.Details)
+	}
+	return interpolate(lookup(e.Code, lang).detail, details)
+}
+
+// Problem convierte el error en un cuerpo RFC 7807 localizado
+func (e *Error) Problem(lang string) models.Problem {
+	return models.Problem{
+		Type:    typeBaseURI + string(e.Code),
+		Title:   lookup(e.Code, lang).title,
+		Status:  e.Status,
+		Detail:  e.Message(lang),
+		Code:    string(e.Code),
+		Details: e.Details,
+	}
+}
+
+// From convierte cualquier error en un *Error: los errores de la API se retornan tal cual
+// y el resto se envuelve como INTERNAL_ERROR
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Wrap(CodeInternal, err)
+}
+
+// FromStatus crea un error genérico a partir de un código HTTP
+func FromStatus(status int, err error) *Error {
+	var code Code
+	switch {
+	case status == http.StatusNotFound:
+		code = CodeNotFound
+	case status == http.StatusMethodNotAllowed:
+		code = CodeMethodNotAllowed
+	case status == http.StatusRequestEntityTooLarge:
+		code = CodePayloadTooLarge
+	case status >= 400 && status < 500:
+		code = CodeBadRequest
+	default:
+		code = CodeInternal
+	}
+	e := Wrap(code, err)
+	e.Status = status
+	if status >= http.StatusInternalServerError {
+		delete(e.Details, "reason")
+	}
+	return e
+}
+
+// interpolate reemplaza los marcadores {clave} del mensaje por los valores de details
+func interpolate(msg string, details map[string]interface{}) string {
+	for key, value := range details {
+		msg = strings.ReplaceAll(msg, "{"+key+"}", fmt.Sprint(value))
+	}
+	return msg
+}
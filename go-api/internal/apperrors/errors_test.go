@@ -0,0 +1,170 @@
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestError_Problem(t *testing.T) {
+	err := New(CodeMatrixNotRectangular, map[string]interface{}{"row": 2, "cols": 1, "expected": 3})
+
+	tests := []struct {
+		lang       string
+		wantDetail string
+	}{
+		{lang: "es", wantDetail: "la matriz no es rectangular: la fila 2 tiene 1 columnas, se esperaban 3"},
+		{lang: "en", wantDetail: "the matrix is not rectangular: row 2 has 1 columns, expected 3"},
+		{lang: "fr", wantDetail: "la matriz no es rectangular: la fila 2 tiene 1 columnas, se esperaban 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			problem := err.Problem(tt.lang)
+			if problem.Detail != tt.wantDetail {
+				t.Errorf("Detail = %q, want %q", problem.Detail, tt.wantDetail)
+			}
+			if problem.Code != "MATRIX_NOT_RECTANGULAR" || problem.Status != http.StatusBadRequest {
+				t.Errorf("Code/Status = %s/%d, want MATRIX_NOT_RECTANGULAR/400", problem.Code, problem.Status)
+			}
+			if problem.Details["row"] != 2 {
+				t.Errorf("Details[row] = %v, want 2", problem.Details["row"])
+			}
+		})
+	}
+}
+
+func TestCatalog_AllCodesHaveSupportedLanguages(t *testing.T) {
+	for code, e := range catalog {
+		for _, lang := range SupportedLanguages {
+			m, ok := e.messages[lang]
+			if !ok || m.title == "" || m.detail == "" {
+				t.Errorf("El código %s no tiene mensaje completo en %q", code, lang)
+			}
+		}
+	}
+}
+
+func TestReasons_AllHaveSupportedLanguages(t *testing.T) {
+	for code, texts := range reasons {
+		for _, lang := range SupportedLanguages {
+			if texts[lang] == "" {
+				t.Errorf("El motivo %s no tiene texto en %q", code, lang)
+			}
+		}
+	}
+}
+
+func TestError_MessageWithReasonCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		details    map[string]interface{}
+		lang       string
+		wantDetail string
+	}{
+		{
+			name:       "motivo en español",
+			details:    map[string]interface{}{"line": 2, "column": 2, "reasonCode": "ROW_LENGTH", "cells": 1, "expected": 2},
+			lang:       "es",
+			wantDetail: "error de formato en la línea 2, columna 2: la fila tiene 1 celdas, se esperaban 2",
+		},
+		{
+			name:       "motivo en inglés",
+			details:    map[string]interface{}{"line": 2, "column": 2, "reasonCode": "ROW_LENGTH", "cells": 1, "expected": 2},
+			lang:       "en",
+			wantDetail: "format error at line 2, column 2: row has 1 cells, expected 2",
+		},
+		{
+			name:       "motivo sin texto",
+			details:    map[string]interface{}{"line": 1, "column": 1, "reasonCode": "UNKNOWN"},
+			lang:       "en",
+			wantDetail: "format error at line 1, column 1: UNKNOWN",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(CodeMatrixParseError, tt.details)
+			if got := err.Message(tt.lang); got != tt.wantDetail {
+				t.Errorf("Message(%q) = %q, want %q", tt.lang, got, tt.wantDetail)
+			}
+			if _, ok := err.Details["reason"]; ok {
+				t.Error("Message no debe modificar Details")
+			}
+		})
+	}
+}
+
+func TestWrapAndFrom(t *testing.T) {
+	cause := errors.New("boom")
+	wrapped := Wrap(CodeQRFailed, cause)
+	if !errors.Is(wrapped, cause) {
+		t.Error("Wrap() debe conservar la causa para errors.Is")
+	}
+	if !errors.Is(wrapped, New(CodeQRFailed, nil)) {
+		t.Error("errors.Is debe comparar por código")
+	}
+
+	if got := From(cause).Code; got != CodeInternal {
+		t.Errorf("From(error genérico).Code = %s, want %s", got, CodeInternal)
+	}
+	if got := From(wrapped); got != wrapped {
+		t.Error("From(*Error) debe retornar el mismo error")
+	}
+	if got := FromStatus(http.StatusNotFound, nil); got.Code != CodeNotFound || got.Status != http.StatusNotFound {
+		t.Errorf("FromStatus(404) = %s/%d, want NOT_FOUND/404", got.Code, got.Status)
+	}
+}
+
+func TestWrap_ReasonOnlyForClientErrors(t *testing.T) {
+	cause := errors.New(`pq: relation "jobs" does not exist`)
+	tests := []struct {
+		name       string
+		err        *Error
+		wantReason bool
+	}{
+		{name: "4xx conserva la causa", err: Wrap(CodeInvalidBody, cause), wantReason: true},
+		{name: "QR_DECOMPOSITION_FAILED", err: Wrap(CodeQRFailed, cause)},
+		{name: "INTERNAL_ERROR", err: From(cause)},
+		{name: "NODE_STATS_UNAVAILABLE", err: Wrap(CodeNodeStatsUnavailable, cause)},
+		{name: "FromStatus 503", err: FromStatus(http.StatusServiceUnavailable, cause)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, hasReason := tt.err.Details["reason"]
+			for _, lang := range SupportedLanguages {
+				problem := tt.err.Problem(lang)
+				if leaked := strings.Contains(problem.Detail, cause.Error()); hasReason != tt.wantReason || (!tt.wantReason && leaked) {
+					t.Errorf("Problem(%s) = %+v, want reason en details: %v", lang, problem, tt.wantReason)
+				}
+			}
+			// La causa sigue disponible para los logs
+			if !strings.Contains(tt.err.Error(), cause.Error()) && !tt.wantReason {
+				t.Errorf("Error() = %q, debería incluir la causa", tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: "es"},
+		{header: "en", want: "en"},
+		{header: "en-US,en;q=0.9,es;q=0.8", want: "en"},
+		{header: "es-AR,en;q=0.5", want: "es"},
+		{header: "fr-FR,en;q=0.3", want: "en"},
+		{header: "de, fr", want: "es"},
+		{header: "en;q=0.2, es;q=0.7", want: "es"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := NegotiateLanguage(tt.header); got != tt.want {
+				t.Errorf("NegotiateLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
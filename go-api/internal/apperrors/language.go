@@ -0,0 +1,54 @@
+package apperrors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage idioma usado cuando el cliente no envía Accept-Language o no hay coincidencia
+const DefaultLanguage = "es"
+
+// SupportedLanguages idiomas disponibles para los mensajes de error
+var SupportedLanguages = []string{"es", "en"}
+
+// NegotiateLanguage elige el idioma soportado con mayor peso (q) del header Accept-Language.
+// Ejemplo: "en-US,en;q=0.9,es;q=0.8" -> "en"
+func NegotiateLanguage(acceptLanguage string) string {
+	best, bestQ := DefaultLanguage, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		// Solo importa el idioma primario (en-US -> en)
+		primary, _, _ := strings.Cut(tag, "-")
+		if primary == "*" {
+			primary = DefaultLanguage
+		}
+		if q > bestQ && isSupported(primary) {
+			best, bestQ = primary, q
+		}
+	}
+	return best
+}
+
+func isSupported(lang string) bool {
+	for _, l := range SupportedLanguages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
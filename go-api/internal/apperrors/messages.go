@@ -0,0 +1,499 @@
+package apperrors
+
+import (
+	"net/http"
+	"sort"
+)
+
+// message título y detalle de un error en un idioma; el detalle admite marcadores {clave}
+type message struct {
+	title  string
+	detail string
+}
+
+// entry entrada del catálogo: código HTTP y mensajes por idioma
+type entry struct {
+	status   int
+	messages map[string]message
+}
+
+// catalog define el código HTTP y los mensajes (es, en) de cada código de error
+var catalog = map[Code]entry{
+	CodeInvalidBody: {http.StatusBadRequest, map[string]message{
+		"es": {"Cuerpo inválido", "formato JSON inválido: {reason}"},
+		"en": {"Invalid body", "invalid JSON format: {reason}"},
+	}},
+	CodeMatrixEmpty: {http.StatusBadRequest, map[string]message{
+		"es": {"Matriz vacía", "la matriz no puede estar vacía"},
+		"en": {"Empty matrix", "the matrix cannot be empty"},
+	}},
+	CodeMatrixRowEmpty: {http.StatusBadRequest, map[string]message{
+		"es": {"Fila vacía", "las filas de la matriz no pueden estar vacías"},
+		"en": {"Empty row", "matrix rows cannot be empty"},
+	}},
+	CodeMatrixNotRectangular: {http.StatusBadRequest, map[string]message{
+		"es": {"Matriz no rectangular", "la matriz no es rectangular: la fila {row} tiene {cols} columnas, se esperaban {expected}"},
+		"en": {"Matrix is not rectangular", "the matrix is not rectangular: row {row} has {cols} columns, expected {expected}"},
+	}},
+	CodeMatrixWiderThanTall: {http.StatusBadRequest, map[string]message{
+		"es": {"Matriz más ancha que alta", "la factorización QR necesita al menos tantas filas como columnas: la matriz es de {rows}x{cols}"},
+		"en": {"Matrix wider than tall", "the QR decomposition needs at least as many rows as columns: the matrix is {rows}x{cols}"},
+	}},
+	CodeQRFailed: {http.StatusInternalServerError, map[string]message{
+		"es": {"Error en factorización QR", "error al calcular factorización QR"},
+		"en": {"QR decomposition failed", "failed to compute QR decomposition"},
+	}},
+	CodeNodeStatsUnavailable: {http.StatusBadGateway, map[string]message{
+		"es": {"Estadísticas no disponibles", "No se pudieron obtener estadísticas de Node.js"},
+		"en": {"Statistics unavailable", "Could not get statistics from Node.js"},
+	}},
+	CodeTokenMissing: {http.StatusUnauthorized, map[string]message{
+		"es": {"Token de acceso requerido", "Agrega el header: Authorization: Bearer <token>"},
+		"en": {"Access token required", "Add the header: Authorization: Bearer <token>"},
+	}},
+	CodeTokenMalformed: {http.StatusUnauthorized, map[string]message{
+		"es": {"Formato de token inválido", "El formato debe ser: Bearer <token>"},
+		"en": {"Invalid token format", "The format must be: Bearer <token>"},
+	}},
+	CodeTokenInvalid: {http.StatusForbidden, map[string]message{
+		"es": {"Token inválido", "Token inválido: {reason}"},
+		"en": {"Invalid token", "Invalid token: {reason}"},
+	}},
+	CodeTokenExpired: {http.StatusForbidden, map[string]message{
+		"es": {"Token expirado", "El token expiró, inicia sesión nuevamente"},
+		"en": {"Token expired", "The token has expired, log in again"},
+	}},
+	CodeInvalidCredentials: {http.StatusUnauthorized, map[string]message{
+		"es": {"Credenciales inválidas", "Usuario o contraseña incorrectos"},
+		"en": {"Invalid credentials", "Incorrect username or password"},
+	}},
+	CodeTokenGenerationFailed: {http.StatusInternalServerError, map[string]message{
+		"es": {"Error al generar token", "No se pudo generar el token"},
+		"en": {"Token generation failed", "The token could not be generated"},
+	}},
+	CodeServerMisconfigured: {http.StatusInternalServerError, map[string]message{
+		"es": {"Error de configuración del servidor", "el servidor no está configurado correctamente"},
+		"en": {"Server configuration error", "the server is not configured correctly"},
+	}},
+	CodeBadRequest: {http.StatusBadRequest, map[string]message{
+		"es": {"Petición inválida", "{reason}"},
+		"en": {"Bad request", "{reason}"},
+	}},
+	CodeNotFound: {http.StatusNotFound, map[string]message{
+		"es": {"No encontrado", "el recurso solicitado no existe"},
+		"en": {"Not found", "the requested resource does not exist"},
+	}},
+	CodeMethodNotAllowed: {http.StatusMethodNotAllowed, map[string]message{
+		"es": {"Método no permitido", "el método no está permitido para este recurso"},
+		"en": {"Method not allowed", "the method is not allowed for this resource"},
+	}},
+	CodePayloadTooLarge: {http.StatusRequestEntityTooLarge, map[string]message{
+		"es": {"Cuerpo demasiado grande", "el cuerpo de la petición excede el tamaño máximo"},
+		"en": {"Payload too large", "the request body exceeds the maximum size"},
+	}},
+	CodeInternal: {http.StatusInternalServerError, map[string]message{
+		"es": {"Error interno", "error interno del servidor"},
+		"en": {"Internal error", "internal server error"},
+	}},
+	CodeJobNotFound: {http.StatusNotFound, map[string]message{
+		"es": {"Job no encontrado", "el job {id} no existe o ya expiró"},
+		"en": {"Job not found", "job {id} does not exist or has expired"},
+	}},
+	CodeJobQueueFull: {http.StatusServiceUnavailable, map[string]message{
+		"es": {"Cola de jobs llena", "la cola de jobs está llena, reintenta más tarde"},
+		"en": {"Job queue full", "the job queue is full, retry later"},
+	}},
+	CodeJobNotCancelable: {http.StatusConflict, map[string]message{
+		"es": {"Job no cancelable", "el job {id} ya terminó con estado {status}"},
+		"en": {"Job not cancelable", "job {id} already finished with status {status}"},
+	}},
+	CodeJobInvalidOperation: {http.StatusBadRequest, map[string]message{
+		"es": {"Operación inválida", "operación de job no soportada: {operation}"},
+		"en": {"Invalid operation", "unsupported job operation: {operation}"},
+	}},
+	CodeJobInterrupted: {http.StatusServiceUnavailable, map[string]message{
+		"es": {"Job interrumpido", "el servidor se reinició antes de terminar el job"},
+		"en": {"Job interrupted", "the server restarted before the job finished"},
+	}},
+	CodeJobCanceled: {http.StatusConflict, map[string]message{
+		"es": {"Job cancelado", "el job fue cancelado por el usuario"},
+		"en": {"Job canceled", "the job was canceled by the user"},
+	}},
+	CodeJobInvalidCallback: {http.StatusBadRequest, map[string]message{
+		"es": {"callbackUrl inválida", "callbackUrl inválida: {reason}"},
+		"en": {"Invalid callbackUrl", "invalid callbackUrl: {reason}"},
+	}},
+	CodeBatchEmpty: {http.StatusBadRequest, map[string]message{
+		"es": {"Lote vacío", "el lote debe contener al menos un elemento"},
+		"en": {"Empty batch", "the batch must contain at least one item"},
+	}},
+	CodeBatchTooManyItems: {http.StatusRequestEntityTooLarge, map[string]message{
+		"es": {"Lote demasiado grande", "el lote tiene {items} elementos, el máximo es {max}"},
+		"en": {"Batch too large", "the batch has {items} items, the maximum is {max}"},
+	}},
+	CodeBatchTooManyElements: {http.StatusRequestEntityTooLarge, map[string]message{
+		"es": {"Lote demasiado grande", "el lote suma {elements} elementos de matriz, el máximo es {max}"},
+		"en": {"Batch too large", "the batch adds up to {elements} matrix elements, the maximum is {max}"},
+	}},
+	CodeBatchDuplicateID: {http.StatusBadRequest, map[string]message{
+		"es": {"Id duplicado", "el id {id} aparece más de una vez en el lote"},
+		"en": {"Duplicate id", "id {id} appears more than once in the batch"},
+	}},
+	CodeBatchInvalidOperation: {http.StatusBadRequest, map[string]message{
+		"es": {"Operación inválida", "operación no soportada: {operation}"},
+		"en": {"Invalid operation", "unsupported operation: {operation}"},
+	}},
+	CodeSessionInvalidMessage: {http.StatusBadRequest, map[string]message{
+		"es": {"Mensaje inválido", "tipo de mensaje no soportado: {type}"},
+		"en": {"Invalid message", "unsupported message type: {type}"},
+	}},
+	CodeSessionNoMatrix: {http.StatusConflict, map[string]message{
+		"es": {"Sin matriz", "envía una matriz completa antes de modificar celdas"},
+		"en": {"No matrix", "send a full matrix before updating cells"},
+	}},
+	CodeSessionCellOutOfRange: {http.StatusBadRequest, map[string]message{
+		"es": {"Celda fuera de rango", "la celda ({row}, {col}) está fuera de la matriz de {rows}x{cols}"},
+		"en": {"Cell out of range", "cell ({row}, {col}) is outside the {rows}x{cols} matrix"},
+	}},
+	CodeWorkspaceNotFound: {http.StatusNotFound, map[string]message{
+		"es": {"Workspace no encontrado", "el workspace {id} no existe o expiró por inactividad"},
+		"en": {"Workspace not found", "workspace {id} does not exist or expired due to inactivity"},
+	}},
+	CodeWorkspaceInvalidOperation: {http.StatusBadRequest, map[string]message{
+		"es": {"Operación inválida", "la operación {index} ({type}) no se puede aplicar: {reason}"},
+		"en": {"Invalid operation", "operation {index} ({type}) cannot be applied: {reason}"},
+	}},
+	CodeWorkspaceTooLarge: {http.StatusRequestEntityTooLarge, map[string]message{
+		"es": {"Workspace demasiado grande", "la matriz tendría {elements} elementos, el máximo es {max}"},
+		"en": {"Workspace too large", "the matrix would have {elements} elements, the maximum is {max}"},
+	}},
+	CodeWorkspaceLimitReached: {http.StatusConflict, map[string]message{
+		"es": {"Límite de workspaces", "ya tienes {max} workspaces abiertos; elimina alguno antes de crear otro"},
+		"en": {"Workspace limit reached", "you already have {max} open workspaces; delete one before creating another"},
+	}},
+	CodeForbidden: {http.StatusForbidden, map[string]message{
+		"es": {"Acceso denegado", "esta operación requiere el rol {role}"},
+		"en": {"Forbidden", "this operation requires the {role} role"},
+	}},
+	CodeIdempotencyKeyInvalid: {http.StatusBadRequest, map[string]message{
+		"es": {"Idempotency-Key inválida", "el header Idempotency-Key debe tener entre 1 y {max} caracteres visibles"},
+		"en": {"Invalid Idempotency-Key", "the Idempotency-Key header must have between 1 and {max} visible characters"},
+	}},
+	CodeIdempotencyKeyInUse: {http.StatusConflict, map[string]message{
+		"es": {"Petición en curso", "otra petición con la misma Idempotency-Key todavía se está procesando, reintenta más tarde"},
+		"en": {"Request in progress", "another request with the same Idempotency-Key is still being processed, retry later"},
+	}},
+	CodeIdempotencyKeyMismatch: {http.StatusUnprocessableEntity, map[string]message{
+		"es": {"Idempotency-Key reutilizada", "la Idempotency-Key ya se usó con otra ruta o con otro cuerpo"},
+		"en": {"Idempotency-Key reused", "the Idempotency-Key was already used with another path or body"},
+	}},
+	CodeMatrixParseError: {http.StatusBadRequest, map[string]message{
+		"es": {"Matriz ilegible", "error de formato en la línea {line}, columna {column}: {reason}"},
+		"en": {"Unreadable matrix", "format error at line {line}, column {column}: {reason}"},
+	}},
+	CodeSolveDimensionMismatch: {http.StatusBadRequest, map[string]message{
+		"es": {"Dimensiones incompatibles", "el vector b tiene {length} elementos y la matriz {rows} filas"},
+		"en": {"Incompatible dimensions", "vector b has {length} elements and the matrix has {rows} rows"},
+	}},
+	CodeMatrixRankDeficient: {http.StatusUnprocessableEntity, map[string]message{
+		"es": {"Matriz de rango incompleto", "la matriz tiene rango {rank} y {cols} columnas: el sistema no tiene solución única"},
+		"en": {"Rank-deficient matrix", "the matrix has rank {rank} and {cols} columns: the system has no unique solution"},
+	}},
+	CodeSparseInvalid: {http.StatusBadRequest, map[string]message{
+		"es": {"Matriz dispersa inválida", "la matriz dispersa es inválida: {reason}"},
+		"en": {"Invalid sparse matrix", "invalid sparse matrix: {reason}"},
+	}},
+	CodeSparseIndexOutOfRange: {http.StatusBadRequest, map[string]message{
+		"es": {"Índice fuera de rango", "el elemento {entry} en ({row}, {col}) está fuera de la matriz de {rows}x{cols}"},
+		"en": {"Index out of range", "entry {entry} at ({row}, {col}) is outside the {rows}x{cols} matrix"},
+	}},
+	CodeSparseDuplicateEntry: {http.StatusBadRequest, map[string]message{
+		"es": {"Elemento duplicado", "la posición ({row}, {col}) aparece más de una vez (elemento {entry})"},
+		"en": {"Duplicate entry", "position ({row}, {col}) appears more than once (entry {entry})"},
+	}},
+	CodeSparseTooLarge: {http.StatusRequestEntityTooLarge, map[string]message{
+		"es": {"Matriz dispersa demasiado grande", "la matriz dispersa de {rows}x{cols} necesita {elements} elementos, el máximo es {max}"},
+		"en": {"Sparse matrix too large", "the {rows}x{cols} sparse matrix needs {elements} elements, the maximum is {max}"},
+	}},
+	CodeExactInvalidNumber: {http.StatusBadRequest, map[string]message{
+		"es": {"Número racional inválido", "{position} = {value} no es un número racional (ej: \"1/3\", \"-2\" o \"0.25\")"},
+		"en": {"Invalid rational number", "{position} = {value} is not a rational number (e.g. \"1/3\", \"-2\" or \"0.25\")"},
+	}},
+	CodeExactTooLarge: {http.StatusRequestEntityTooLarge, map[string]message{
+		"es": {"Matriz demasiado grande para el modo exacto", "la matriz de {rows}x{cols} supera el máximo de {max} filas o columnas del modo exacto"},
+		"en": {"Matrix too large for exact mode", "the {rows}x{cols} matrix exceeds the exact mode maximum of {max} rows or columns"},
+	}},
+	CodePrecisionInvalid: {http.StatusBadRequest, map[string]message{
+		"es": {"Precisión inválida", "la precisión de {precision} bits debe estar entre {min} y {max}"},
+		"en": {"Invalid precision", "the precision of {precision} bits must be between {min} and {max}"},
+	}},
+	CodePrecisionTooLarge: {http.StatusRequestEntityTooLarge, map[string]message{
+		"es": {"Matriz demasiado grande para el modo de precisión", "la matriz de {rows}x{cols} tiene {elements} elementos, el máximo con precision es {max}"},
+		"en": {"Matrix too large for precision mode", "the {rows}x{cols} matrix has {elements} elements, the maximum with precision is {max}"},
+	}},
+	CodeExplainTooLarge: {http.StatusRequestEntityTooLarge, map[string]message{
+		"es": {"Matriz demasiado grande para explicar", "la matriz de {rows}x{cols} tiene {elements} elementos, el máximo con explain es {max}"},
+		"en": {"Matrix too large to explain", "the {rows}x{cols} matrix has {elements} elements, the maximum with explain is {max}"},
+	}},
+	CodeNonFiniteValue: {http.StatusBadRequest, map[string]message{
+		"es": {"Valor no finito", "la matriz tiene un NaN o infinito en la fila {row}, columna {column}"},
+		"en": {"Non-finite value", "the matrix has a NaN or infinity at row {row}, column {column}"},
+	}},
+	CodeMatrixInputConflict: {http.StatusBadRequest, map[string]message{
+		"es": {"Matrices en conflicto", "envía solo una de matrix, sparse o complex"},
+		"en": {"Conflicting matrices", "send only one of matrix, sparse or complex"},
+	}},
+	CodeOptionNotApplicable: {http.StatusBadRequest, map[string]message{
+		"es": {"Opción no aplicable", "{option} solo se puede usar con {requires}"},
+		"en": {"Option not applicable", "{option} can only be used with {requires}"},
+	}},
+	CodeResultFormatUnsupported: {http.StatusBadRequest, map[string]message{
+		"es": {"Formato de resultado no soportado", "el resultado de {mode} solo está disponible en JSON, MessagePack o CBOR"},
+		"en": {"Unsupported result format", "{mode} results are only available as JSON, MessagePack or CBOR"},
+	}},
+	CodeOptionsConflict: {http.StatusBadRequest, map[string]message{
+		"es": {"Opciones en conflicto", "{option} no se puede usar con {other}"},
+		"en": {"Conflicting options", "{option} cannot be used with {other}"},
+	}},
+	CodeInvalidExplainMethod: {http.StatusBadRequest, map[string]message{
+		"es": {"Método de explicación inválido", "explainMethod {method} no es válido, usa {classical} o {modified}"},
+		"en": {"Invalid explain method", "explainMethod {method} is not valid, use {classical} or {modified}"},
+	}},
+	CodeInvalidFormatOption: {http.StatusBadRequest, map[string]message{
+		"es": {"Opción de formato inválida", "{option} \"{value}\" no es válido, usa {allowed}"},
+		"en": {"Invalid format option", "invalid {option} \"{value}\", use {allowed}"},
+	}},
+	CodeInvalidDelimiter: {http.StatusBadRequest, map[string]message{
+		"es": {"Delimitador inválido", "el delimitador \"{value}\" no es válido, usa un carácter (no comillas, # ni saltos de línea) o tab"},
+		"en": {"Invalid delimiter", "invalid delimiter \"{value}\", use one character (not a quote, # or line break) or tab"},
+	}},
+	CodeMatrixSelectionRequired: {http.StatusBadRequest, map[string]message{
+		"es": {"Falta elegir la matriz", "este formato guarda una sola matriz: elígela con matrix ({allowed})"},
+		"en": {"Matrix selection required", "this format holds a single matrix: choose one with matrix ({allowed})"},
+	}},
+	CodeMatrixDataLength: {http.StatusBadRequest, map[string]message{
+		"es": {"Datos de la matriz incompletos", "data tiene {length} elementos, se esperaban rows·cols = {expected}"},
+		"en": {"Matrix data length mismatch", "data has {length} elements, expected rows·cols = {expected}"},
+	}},
+	CodeSolveNonFiniteValue: {http.StatusBadRequest, map[string]message{
+		"es": {"Valor no finito", "el vector b tiene un NaN o infinito en la posición {position}"},
+		"en": {"Non-finite value", "vector b has a NaN or infinity at position {position}"},
+	}},
+	CodeIdempotencyStoreFull: {http.StatusServiceUnavailable, map[string]message{
+		"es": {"Demasiadas peticiones idempotentes en curso", "hay {max} peticiones con Idempotency-Key en curso, reintenta más tarde"},
+		"en": {"Too many idempotent requests in progress", "there are {max} requests with an Idempotency-Key in progress, retry later"},
+	}},
+	CodePreconditionFailed: {http.StatusPreconditionFailed, map[string]message{
+		"es": {"Precondición fallida", "el resultado coincide con un ETag de If-None-Match"},
+		"en": {"Precondition failed", "the result matches an ETag in If-None-Match"},
+	}},
+	CodeGraphQLQueryRequired: {http.StatusBadRequest, map[string]message{
+		"es": {"Falta la consulta", "el cuerpo debe incluir query con la consulta GraphQL"},
+		"en": {"Query required", "the body must include query with the GraphQL query"},
+	}},
+	CodeMatrixValueInvalid: {http.StatusBadRequest, map[string]message{
+		"es": {"Valor de la matriz inválido", "el valor de la fila {row}, columna {column} no es un número"},
+		"en": {"Invalid matrix value", "the value at row {row}, column {column} is not a number"},
+	}},
+}
+
+// reasons textos por idioma de los motivos de los errores de formato (details.reasonCode
+// de MATRIX_PARSE_ERROR); admiten los mismos marcadores {clave} que el detalle
+var reasons = map[string]map[string]string{
+	"BARE_QUOTE": {
+		"es": "comilla en una celda sin comillas",
+		"en": "bare quote in a non-quoted cell",
+	},
+	"QUOTE": {
+		"es": "comilla de más o sin cerrar en una celda entre comillas",
+		"en": "extraneous or missing quote in a quoted cell",
+	},
+	"ROW_LENGTH": {
+		"es": "la fila tiene {cells} celdas, se esperaban {expected}",
+		"en": "row has {cells} cells, expected {expected}",
+	},
+	"EMPTY_CELL": {
+		"es": "celda vacía",
+		"en": "empty cell",
+	},
+	"DECIMAL_POINT": {
+		"es": "número inválido \"{value}\" (el separador decimal es ',')",
+		"en": "invalid number \"{value}\" (the decimal separator is ',')",
+	},
+	"INVALID_NUMBER": {
+		"es": "número inválido \"{value}\"",
+		"en": "invalid number \"{value}\"",
+	},
+	"NON_FINITE_NUMBER": {
+		"es": "el número \"{value}\" no es finito",
+		"en": "non-finite number \"{value}\"",
+	},
+	"EMPTY_MATRIX": {
+		"es": "matriz vacía de {rows}x{cols}",
+		"en": "empty matrix of {rows}x{cols}",
+	},
+	"TOO_MANY_ELEMENTS": {
+		"es": "la matriz de {rows}x{cols} supera los {max} elementos",
+		"en": "the {rows}x{cols} matrix exceeds {max} elements",
+	},
+	"MTX_MISSING_HEADER": {
+		"es": "falta el encabezado %%MatrixMarket",
+		"en": "missing %%MatrixMarket header",
+	},
+	"MTX_INVALID_HEADER": {
+		"es": "se esperaba el encabezado %%MatrixMarket matrix <format> <field> <symmetry>",
+		"en": "expected header %%MatrixMarket matrix <format> <field> <symmetry>",
+	},
+	"MTX_UNSUPPORTED_OBJECT": {
+		"es": "objeto \"{value}\" no soportado, usa matrix",
+		"en": "unsupported object \"{value}\", use matrix",
+	},
+	"MTX_UNSUPPORTED_FORMAT": {
+		"es": "formato \"{value}\" no soportado, usa coordinate o array",
+		"en": "unsupported format \"{value}\", use coordinate or array",
+	},
+	"MTX_UNSUPPORTED_FIELD": {
+		"es": "campo \"{value}\" no soportado, usa real, integer o pattern",
+		"en": "unsupported field \"{value}\", use real, integer or pattern",
+	},
+	"MTX_PATTERN_NOT_COORDINATE": {
+		"es": "el campo pattern requiere el formato coordinate",
+		"en": "pattern field requires coordinate format",
+	},
+	"MTX_UNSUPPORTED_SYMMETRY": {
+		"es": "simetría \"{value}\" no soportada, usa general, symmetric, skew-symmetric o hermitian",
+		"en": "unsupported symmetry \"{value}\", use general, symmetric, skew-symmetric or hermitian",
+	},
+	"MTX_MISSING_SIZE": {
+		"es": "falta la línea de tamaño",
+		"en": "missing size line",
+	},
+	"MTX_SIZE_FIELDS": {
+		"es": "la línea de tamaño tiene {fields} campos, se esperaban {expected}",
+		"en": "size line has {fields} fields, expected {expected}",
+	},
+	"MTX_INVALID_SIZE": {
+		"es": "tamaño inválido \"{value}\"",
+		"en": "invalid size \"{value}\"",
+	},
+	"MTX_NOT_SQUARE": {
+		"es": "una matriz {symmetry} debe ser cuadrada, es de {rows}x{cols}",
+		"en": "a {symmetry} matrix must be square, got {rows}x{cols}",
+	},
+	"MTX_ENTRY_COUNT": {
+		"es": "se esperaban {expected} entradas, hay {got}",
+		"en": "expected {expected} entries, got {got}",
+	},
+	"MTX_ENTRY_FIELDS": {
+		"es": "la entrada tiene {fields} campos, se esperaban {expected}",
+		"en": "entry has {fields} fields, expected {expected}",
+	},
+	"MTX_INDEX_OUT_OF_RANGE": {
+		"es": "el índice \"{value}\" está fuera del rango 1..{limit}",
+		"en": "index \"{value}\" out of range 1..{limit}",
+	},
+	"MTX_SKEW_DIAGONAL": {
+		"es": "una matriz skew-symmetric no puede tener entradas en la diagonal",
+		"en": "a skew-symmetric matrix cannot have diagonal entries",
+	},
+	"MTX_EXTRA_ENTRY": {
+		"es": "entrada \"{entry}\" de más después de {entries} entradas",
+		"en": "unexpected entry \"{entry}\" after {entries} entries",
+	},
+	"NPY_MISSING_MAGIC": {
+		"es": "falta la cadena mágica \\x93NUMPY",
+		"en": "missing \\x93NUMPY magic string",
+	},
+	"NPY_TRUNCATED_HEADER": {
+		"es": "el largo del encabezado está truncado",
+		"en": "truncated header length",
+	},
+	"NPY_UNSUPPORTED_VERSION": {
+		"es": "versión de .npy {version} no soportada",
+		"en": "unsupported .npy version {version}",
+	},
+	"NPY_HEADER_LENGTH": {
+		"es": "el largo del encabezado ({length}) supera el cuerpo",
+		"en": "header length {length} exceeds the body",
+	},
+	"NPY_UNSUPPORTED_DTYPE": {
+		"es": "dtype no soportado, usa float64 ('<f8' o '>f8')",
+		"en": "unsupported dtype, use float64 ('<f8' or '>f8')",
+	},
+	"NPY_MISSING_FORTRAN_ORDER": {
+		"es": "falta fortran_order",
+		"en": "missing fortran_order",
+	},
+	"NPY_MISSING_SHAPE": {
+		"es": "falta shape",
+		"en": "missing shape",
+	},
+	"NPY_INVALID_SHAPE": {
+		"es": "shape inválido ({shape})",
+		"en": "invalid shape ({shape})",
+	},
+	"NPY_NOT_2D": {
+		"es": "se esperaba un arreglo de 2 dimensiones, el shape es ({shape})",
+		"en": "expected a 2-D array, got shape ({shape})",
+	},
+	"DATA_LENGTH": {
+		"es": "se esperaban {expected} bytes de datos, hay {got}",
+		"en": "expected {expected} bytes of data, got {got}",
+	},
+	"NON_FINITE_ELEMENT": {
+		"es": "valor no finito en el elemento {element}",
+		"en": "non-finite value at element {element}",
+	},
+	"FLOAT64_MISSING_HEADER": {
+		"es": "falta el encabezado MF64",
+		"en": "missing MF64 header",
+	},
+	"TRAILING_BYTES": {
+		"es": "{bytes} bytes sobrantes después de la matriz",
+		"en": "{bytes} trailing bytes after the matrix",
+	},
+}
+
+// statusFor retorna el código HTTP asociado a un código de error
+func statusFor(code Code) int {
+	if e, ok := catalog[code]; ok {
+		return e.status
+	}
+	return http.StatusInternalServerError
+}
+
+// lookup retorna el mensaje de un código en el idioma pedido, con fallback al idioma por defecto
+func lookup(code Code, lang string) message {
+	e, ok := catalog[code]
+	if !ok {
+		e = catalog[CodeInternal]
+	}
+	if m, ok := e.messages[lang]; ok {
+		return m
+	}
+	return e.messages[DefaultLanguage]
+}
+
+// reasonText retorna el texto del motivo code en el idioma pedido con los detalles
+// interpolados; un motivo sin texto se muestra tal cual
+func reasonText(code, lang string, details map[string]interface{}) string {
+	texts, ok := reasons[code]
+	if !ok {
+		return code
+	}
+	text, ok := texts[lang]
+	if !ok {
+		text = texts[DefaultLanguage]
+	}
+	return interpolate(text, details)
+}
+
+// Codes retorna todos los códigos de error del catálogo ordenados alfabéticamente
+func Codes() []Code {
+	codes := make([]Code, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
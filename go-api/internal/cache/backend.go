@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	_ "modernc.org/sqlite" // driver SQLite en Go puro (compatible con CGO_ENABLED=0)
+)
+
+// Backend almacenamiento persistente opcional detrás del LRU en memoria.
+// Las implementaciones deben ser seguras para uso concurrente.
+type Backend interface {
+	// Name identifica el backend en las estadísticas (ej: "sqlite")
+	Name() string
+	// Get retorna el valor y su expiración; ok es false si la clave no existe
+	Get(ctx context.Context, key string) (value []byte, expiresAt time.Time, ok bool, err error)
+	// Set guarda (o reemplaza) el valor con su expiración
+	Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error
+	// Delete elimina la clave y retorna si existía
+	Delete(ctx context.Context, key string) (bool, error)
+	// Purge elimina todas las claves
+	Purge(ctx context.Context) error
+	// Ping verifica que el backend esté disponible (usado por /readyz)
+	Ping(ctx context.Context) error
+	// Close libera los recursos del backend
+	Close() error
+}
+
+// sqliteSchema tabla de resultados serializados indexada por expiración
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS cache (
+	key        TEXT PRIMARY KEY,
+	expires_at INTEGER NOT NULL,
+	value      BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS cache_expires_at ON cache (expires_at);
+`
+
+// sqliteCleanupEvery cada cuántas escrituras se eliminan las filas expiradas
+const sqliteCleanupEvery = 1000
+
+// SQLiteBackend implementación de Backend sobre SQLite
+type SQLiteBackend struct {
+	db     *sql.DB
+	now    func() time.Time
+	writes atomic.Int64
+}
+
+// NewSQLiteBackend abre (o crea) la base de datos en path, aplica el esquema y
+// elimina los resultados que expiraron mientras el servidor estaba detenido
+func NewSQLiteBackend(path string) (*SQLiteBackend, error) {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path))
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir SQLite: %w", err)
+	}
+	// SQLite admite un único escritor: serializar el acceso evita errores SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al crear esquema del cache: %w", err)
+	}
+	b := &SQLiteBackend{db: db, now: time.Now}
+	if err := b.deleteExpired(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al limpiar el cache: %w", err)
+	}
+	return b, nil
+}
+
+func (b *SQLiteBackend) Name() string {
+	return "sqlite"
+}
+
+func (b *SQLiteBackend) Get(ctx context.Context, key string) ([]byte, time.Time, bool, error) {
+	var value []byte
+	var expiresAt int64
+	err := b.db.QueryRowContext(ctx, `SELECT value, expires_at FROM cache WHERE key = ?`, key).Scan(&value, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, false, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+	return value, time.Unix(0, expiresAt), true, nil
+}
+
+func (b *SQLiteBackend) Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error {
+	_, err := b.db.ExecContext(ctx,
+		`INSERT INTO cache (key, expires_at, value) VALUES (?, ?, ?)
+		 ON CONFLICT (key) DO UPDATE SET expires_at = excluded.expires_at, value = excluded.value`,
+		key, expiresAt.UnixNano(), value)
+	if err != nil {
+		return err
+	}
+	// Sin límite de tamaño en disco: las filas expiradas se limpian periódicamente
+	if b.writes.Add(1)%sqliteCleanupEvery == 0 {
+		return b.deleteExpired(ctx)
+	}
+	return nil
+}
+
+func (b *SQLiteBackend) Delete(ctx context.Context, key string) (bool, error) {
+	res, err := b.db.ExecContext(ctx, `DELETE FROM cache WHERE key = ?`, key)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (b *SQLiteBackend) Purge(ctx context.Context) error {
+	_, err := b.db.ExecContext(ctx, `DELETE FROM cache`)
+	return err
+}
+
+func (b *SQLiteBackend) Ping(ctx context.Context) error {
+	return b.db.PingContext(ctx)
+}
+
+func (b *SQLiteBackend) Close() error {
+	return b.db.Close()
+}
+
+// deleteExpired elimina las filas expiradas
+func (b *SQLiteBackend) deleteExpired(ctx context.Context) error {
+	_, err := b.db.ExecContext(ctx, `DELETE FROM cache WHERE expires_at < ?`, b.now().UnixNano())
+	return err
+}
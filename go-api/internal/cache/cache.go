@@ -0,0 +1,262 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"go-api/internal/metrics"
+	"go-api/internal/models"
+)
+
+// Config límites del cache en memoria
+type Config struct {
+	// MaxEntries cantidad máxima de resultados en memoria
+	MaxEntries int
+	// MaxBytes tamaño máximo en bytes de los resultados en memoria
+	MaxBytes int64
+	// TTL tiempo que un resultado se considera válido
+	TTL time.Duration
+}
+
+// entry resultado guardado en la lista LRU
+type entry struct {
+	key       string
+	value     []byte
+	createdAt time.Time
+	expiresAt time.Time
+	hits      int
+}
+
+// Cache cache LRU de resultados serializados con límite de entradas, de bytes y TTL.
+// Con un Backend, los resultados también se guardan ahí y un fallo en memoria se
+// busca en el backend antes de contar como MISS (sobreviven a reinicios).
+type Cache struct {
+	cfg     Config
+	backend Backend
+	now     func() time.Time
+
+	mu        sync.Mutex
+	lru       *list.List
+	items     map[string]*list.Element
+	bytes     int64
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// New crea un cache; backend puede ser nil (solo memoria)
+func New(cfg Config, backend Backend) *Cache {
+	if cfg.MaxEntries < 1 {
+		cfg.MaxEntries = 1
+	}
+	return &Cache{
+		cfg:     cfg,
+		backend: backend,
+		now:     time.Now,
+		lru:     list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+// Key calcula la clave canónica de un resultado: SHA-256 de la operación, las
+// dimensiones y los bits de cada valor. -0 y 0 se consideran iguales.
+func Key(operation string, matrix [][]float64) string {
+	h := sha256.New()
+	h.Write([]byte(operation))
+	h.Write([]byte{0})
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(len(matrix)))
+	h.Write(buf[:])
+	for _, row := range matrix {
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(row)))
+		h.Write(buf[:])
+		for _, value := range row {
+			if value == 0 {
+				value = 0
+			}
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(value))
+			h.Write(buf[:])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get retorna el resultado guardado con la clave dada
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool) {
+	now := c.now()
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		if e.expiresAt.After(now) {
+			e.hits++
+			c.lru.MoveToFront(el)
+			c.hits++
+			c.mu.Unlock()
+			metrics.CacheRequestsTotal.WithLabelValues("hit").Inc()
+			return e.value, true
+		}
+		c.remove(el)
+	}
+	c.mu.Unlock()
+
+	if c.backend != nil {
+		value, expiresAt, ok, err := c.backend.Get(ctx, key)
+		if err != nil {
+			slog.WarnContext(ctx, "cache backend get failed", "error", err)
+		}
+		if ok && expiresAt.After(now) {
+			c.mu.Lock()
+			c.hits++
+			c.insert(&entry{key: key, value: value, createdAt: now, expiresAt: expiresAt, hits: 1})
+			c.mu.Unlock()
+			metrics.CacheRequestsTotal.WithLabelValues("hit").Inc()
+			return value, true
+		}
+	}
+
+	c.mu.Lock()
+	c.misses++
+	c.mu.Unlock()
+	metrics.CacheRequestsTotal.WithLabelValues("miss").Inc()
+	return nil, false
+}
+
+// Set guarda un resultado; value no debe modificarse después
+func (c *Cache) Set(ctx context.Context, key string, value []byte) {
+	now := c.now()
+	e := &entry{key: key, value: value, createdAt: now, expiresAt: now.Add(c.cfg.TTL)}
+	c.mu.Lock()
+	c.insert(e)
+	c.mu.Unlock()
+
+	if c.backend != nil {
+		if err := c.backend.Set(ctx, key, value, e.expiresAt); err != nil {
+			slog.WarnContext(ctx, "cache backend set failed", "error", err)
+		}
+	}
+}
+
+// Delete elimina un resultado y retorna si existía
+func (c *Cache) Delete(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	el, found := c.items[key]
+	if found {
+		c.remove(el)
+	}
+	c.mu.Unlock()
+
+	if c.backend != nil {
+		deleted, err := c.backend.Delete(ctx, key)
+		if err != nil {
+			return found, err
+		}
+		found = found || deleted
+	}
+	return found, nil
+}
+
+// Purge elimina todos los resultados y retorna cuántos había en memoria
+func (c *Cache) Purge(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	purged := c.lru.Len()
+	c.lru.Init()
+	c.items = make(map[string]*list.Element)
+	c.bytes = 0
+	c.updateGauges()
+	c.mu.Unlock()
+
+	if c.backend != nil {
+		if err := c.backend.Purge(ctx); err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// Stats retorna los contadores del cache y, con limit > 0, las entradas más recientes
+func (c *Cache) Stats(limit int) models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := models.CacheStats{
+		Entries:    c.lru.Len(),
+		Bytes:      c.bytes,
+		MaxEntries: c.cfg.MaxEntries,
+		MaxBytes:   c.cfg.MaxBytes,
+		TTLSeconds: c.cfg.TTL.Seconds(),
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+		Backend:    "memory",
+		Items:      []models.CacheEntry{},
+	}
+	if c.backend != nil {
+		stats.Backend = c.backend.Name()
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRatio = float64(c.hits) / float64(total)
+	}
+	for el := c.lru.Front(); el != nil && len(stats.Items) < limit; el = el.Next() {
+		e := el.Value.(*entry)
+		stats.Items = append(stats.Items, models.CacheEntry{
+			Key:       e.key,
+			Bytes:     len(e.value),
+			Hits:      e.hits,
+			CreatedAt: e.createdAt.UTC(),
+			ExpiresAt: e.expiresAt.UTC(),
+		})
+	}
+	return stats
+}
+
+// Backend retorna el backend persistente, o nil si el cache es solo en memoria
+func (c *Cache) Backend() Backend {
+	return c.backend
+}
+
+// Close libera el backend
+func (c *Cache) Close() error {
+	if c.backend == nil {
+		return nil
+	}
+	return c.backend.Close()
+}
+
+// insert agrega (o reemplaza) una entrada y desaloja las menos usadas hasta respetar
+// los límites. Un resultado más grande que MaxBytes no se guarda. Requiere mu.
+func (c *Cache) insert(e *entry) {
+	if el, ok := c.items[e.key]; ok {
+		c.remove(el)
+	}
+	size := int64(len(e.value))
+	if c.cfg.MaxBytes > 0 && size > c.cfg.MaxBytes {
+		return
+	}
+	c.items[e.key] = c.lru.PushFront(e)
+	c.bytes += size
+	for c.lru.Len() > c.cfg.MaxEntries || (c.cfg.MaxBytes > 0 && c.bytes > c.cfg.MaxBytes) {
+		c.remove(c.lru.Back())
+		c.evictions++
+	}
+	c.updateGauges()
+}
+
+// remove quita una entrada de la lista y del índice. Requiere mu.
+func (c *Cache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*entry)
+	delete(c.items, e.key)
+	c.bytes -= int64(len(e.value))
+	c.updateGauges()
+}
+
+// updateGauges publica el tamaño actual del cache. Requiere mu.
+func (c *Cache) updateGauges() {
+	metrics.CacheEntries.Set(float64(c.lru.Len()))
+	metrics.CacheBytes.Set(float64(c.bytes))
+}
@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKey(t *testing.T) {
+	base := Key("process", [][]float64{{1, 2}, {3, 4}})
+
+	tests := []struct {
+		name      string
+		operation string
+		matrix    [][]float64
+		wantSame  bool
+	}{
+		{name: "misma matriz", operation: "process", matrix: [][]float64{{1, 2}, {3, 4}}, wantSame: true},
+		{name: "otra operación", operation: "qr", matrix: [][]float64{{1, 2}, {3, 4}}},
+		{name: "otro valor", operation: "process", matrix: [][]float64{{1, 2}, {3, 5}}},
+		{name: "mismos valores con otra forma", operation: "process", matrix: [][]float64{{1, 2, 3, 4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.operation, tt.matrix) == base; got != tt.wantSame {
+				t.Errorf("Key() igual a la base = %v, want %v", got, tt.wantSame)
+			}
+		})
+	}
+
+	if Key("process", [][]float64{{math.Copysign(0, -1), 1}}) != Key("process", [][]float64{{0, 1}}) {
+		t.Error("-0 y 0 deben tener la misma clave")
+	}
+}
+
+// newTestCache crea un cache con reloj controlable
+func newTestCache(cfg Config, backend Backend) (*Cache, *time.Time) {
+	now := time.Now()
+	c := New(cfg, backend)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		sets     []string
+		touch    string
+		wantKeys []string
+		wantGone []string
+	}{
+		{
+			name:     "por cantidad de entradas desaloja la menos usada",
+			cfg:      Config{MaxEntries: 2, TTL: time.Hour},
+			sets:     []string{"a", "b", "c"},
+			touch:    "a",
+			wantKeys: []string{"a", "c"},
+			wantGone: []string{"b"},
+		},
+		{
+			name:     "por tamaño en bytes",
+			cfg:      Config{MaxEntries: 10, MaxBytes: 10, TTL: time.Hour},
+			sets:     []string{"a", "b", "c"},
+			wantKeys: []string{"b", "c"},
+			wantGone: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestCache(tt.cfg, nil)
+			for i, key := range tt.sets {
+				c.Set(ctx, key, []byte("12345"))
+				// El acceso a touch antes de la última inserción lo vuelve el más reciente
+				if i == len(tt.sets)-2 && tt.touch != "" {
+					c.Get(ctx, tt.touch)
+				}
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := c.Get(ctx, key); !ok {
+					t.Errorf("Get(%q) = MISS, want HIT", key)
+				}
+			}
+			for _, key := range tt.wantGone {
+				if _, ok := c.Get(ctx, key); ok {
+					t.Errorf("Get(%q) = HIT, want MISS (desalojada)", key)
+				}
+			}
+			if stats := c.Stats(0); stats.Evictions != 1 {
+				t.Errorf("evictions = %d, want 1", stats.Evictions)
+			}
+		})
+	}
+}
+
+func TestCache_TTLAndLimits(t *testing.T) {
+	ctx := context.Background()
+	c, now := newTestCache(Config{MaxEntries: 10, MaxBytes: 4, TTL: time.Minute}, nil)
+
+	c.Set(ctx, "grande", []byte("12345"))
+	if _, ok := c.Get(ctx, "grande"); ok {
+		t.Error("un resultado más grande que MaxBytes no debe guardarse")
+	}
+
+	c.Set(ctx, "k", []byte("1"))
+	if _, ok := c.Get(ctx, "k"); !ok {
+		t.Fatal("Get() = MISS, want HIT")
+	}
+	*now = now.Add(2 * time.Minute)
+	if _, ok := c.Get(ctx, "k"); ok {
+		t.Error("Get() tras el TTL = HIT, want MISS")
+	}
+
+	stats := c.Stats(10)
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("stats = %+v, want 1 hit, 2 misses y el cache vacío", stats)
+	}
+}
+
+func TestCache_SQLiteBackend(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.db")
+	backend, err := NewSQLiteBackend(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteBackend: %v", err)
+	}
+	first, _ := newTestCache(Config{MaxEntries: 10, TTL: time.Hour}, backend)
+	first.Set(ctx, "k", []byte(`{"r":[[1]]}`))
+
+	// Un cache nuevo (ej: tras reiniciar) encuentra el resultado en el backend
+	second, _ := newTestCache(Config{MaxEntries: 10, TTL: time.Hour}, backend)
+	value, ok := second.Get(ctx, "k")
+	if !ok || string(value) != `{"r":[[1]]}` {
+		t.Fatalf("Get() = %q, %v; want el valor guardado por el primer cache", value, ok)
+	}
+	if stats := second.Stats(10); stats.Entries != 1 || stats.Backend != "sqlite" {
+		t.Errorf("stats = %+v, want la entrada promovida a memoria", stats)
+	}
+
+	if found, err := second.Delete(ctx, "k"); err != nil || !found {
+		t.Errorf("Delete() = %v, %v; want true", found, err)
+	}
+	if _, ok := first.Get(ctx, "otra"); ok {
+		t.Error("Get() de una clave inexistente = HIT")
+	}
+
+	first.Set(ctx, "a", []byte("1"))
+	if _, err := first.Purge(ctx); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if _, ok := second.Get(ctx, "a"); ok {
+		t.Error("Purge() debe vaciar también el backend")
+	}
+	if err := first.Backend().Ping(ctx); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if err := first.Backend().Ping(ctx); err == nil {
+		t.Error("Ping() tras Close debe fallar")
+	}
+}
@@ -0,0 +1,179 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Media types binarios: MessagePack y CBOR llevan la misma estructura que el JSON;
+// MIMEFloat64 es una matriz cruda (encabezado con la forma y float64 little-endian)
+const (
+	MIMEMsgPack = "application/msgpack"
+	// MIMEXMsgPack nombre anterior a que se registrara application/msgpack, aún muy usado
+	MIMEXMsgPack = "application/x-msgpack"
+	MIMECBOR     = "application/cbor"
+	MIMEFloat64  = "application/x-float64-matrix"
+)
+
+// EncodeMsgPack escribe v como MessagePack usando los nombres de los tags json
+func EncodeMsgPack(w io.Writer, v interface{}) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc.Encode(v)
+}
+
+// DecodeMsgPack lee MessagePack en v usando los nombres de los tags json
+func DecodeMsgPack(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// cborDecMode rechaza claves duplicadas, que en JSON quedarían con el último valor
+var cborDecMode, _ = cbor.DecOptions{DupMapKey: cbor.DupMapKeyEnforcedAPF}.DecMode()
+
+// EncodeCBOR escribe v como CBOR usando los nombres de los tags json
+func EncodeCBOR(w io.Writer, v interface{}) error {
+	return cbor.NewEncoder(w).Encode(v)
+}
+
+// DecodeCBOR lee CBOR en v usando los nombres de los tags json
+func DecodeCBOR(data []byte, v interface{}) error {
+	return cborDecMode.Unmarshal(data, v)
+}
+
+// float64Magic prefijo de cada matriz cruda
+var float64Magic = []byte("MF64")
+
+// float64HeaderLen magic (4) + filas (uint32) + columnas (uint32)
+const float64HeaderLen = 12
+
+// Motivos de los errores de formato propios del float64 crudo
+const (
+	ReasonFloat64MissingHeader = "FLOAT64_MISSING_HEADER"
+	ReasonTrailingBytes        = "TRAILING_BYTES"
+)
+
+// EncodeFloat64 escribe la matriz cruda: "MF64", filas y columnas como uint32 y los
+// elementos por filas como float64, todo little-endian
+func EncodeFloat64(w io.Writer, matrix [][]float64) error {
+	rows, cols := len(matrix), 0
+	if rows > 0 {
+		cols = len(matrix[0])
+	}
+	buf := make([]byte, float64HeaderLen, float64HeaderLen+rows*cols*8)
+	copy(buf, float64Magic)
+	binary.LittleEndian.PutUint32(buf[4:], uint32(rows))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(cols))
+	for _, row := range matrix {
+		for _, value := range row {
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(value))
+		}
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// EncodeFloat64Sections escribe las matrices crudas una detrás de otra, en el orden dado
+func EncodeFloat64Sections(w io.Writer, sections []Section) error {
+	for _, section := range sections {
+		if err := EncodeFloat64(w, section.Matrix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeFloat64 lee una sola matriz cruda; los bytes sobrantes son un error.
+// Los errores de formato son *ParseError con Line 1 y Column igual a la posición del byte.
+func DecodeFloat64(data []byte) ([][]float64, error) {
+	matrix, n, err := decodeFloat64Frame(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(data) {
+		return nil, &ParseError{Line: 1, Column: n + 1, Reason: ReasonTrailingBytes, Params: map[string]interface{}{
+			"bytes": len(data) - n,
+		}}
+	}
+	return matrix, nil
+}
+
+// DecodeFloat64Sections lee todas las matrices crudas de data (ej: rotated, q y r de una respuesta)
+func DecodeFloat64Sections(data []byte) ([][][]float64, error) {
+	var matrices [][][]float64
+	for offset := 0; offset < len(data); {
+		matrix, n, err := decodeFloat64Frame(data[offset:], offset)
+		if err != nil {
+			return nil, err
+		}
+		matrices = append(matrices, matrix)
+		offset += n
+	}
+	return matrices, nil
+}
+
+// decodeFloat64Frame lee la matriz cruda al comienzo de data y retorna cuántos bytes ocupó;
+// base es la posición de data en el cuerpo completo, para los errores
+func decodeFloat64Frame(data []byte, base int) ([][]float64, int, error) {
+	if len(data) < float64HeaderLen || !bytes.HasPrefix(data, float64Magic) {
+		return nil, 0, &ParseError{Line: 1, Column: base + 1, Reason: ReasonFloat64MissingHeader}
+	}
+	rows := int(binary.LittleEndian.Uint32(data[4:8]))
+	cols := int(binary.LittleEndian.Uint32(data[8:12]))
+	if err := checkDimensions(1, base+5, rows, cols); err != nil {
+		return nil, 0, err
+	}
+	size := float64HeaderLen + rows*cols*8
+	if len(data) < size {
+		return nil, 0, &ParseError{Line: 1, Column: base + len(data) + 1, Reason: ReasonDataLength, Params: map[string]interface{}{
+			"expected": rows * cols * 8, "got": len(data) - float64HeaderLen,
+		}}
+	}
+
+	values := make([]float64, rows*cols)
+	for k := range values {
+		offset := float64HeaderLen + k*8
+		value := math.Float64frombits(binary.LittleEndian.Uint64(data[offset:]))
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, 0, &ParseError{Line: 1, Column: base + offset + 1, Reason: ReasonNonFiniteElement, Params: map[string]interface{}{
+				"element": k,
+			}}
+		}
+		values[k] = value
+	}
+	matrix := make([][]float64, rows)
+	for i := range matrix {
+		matrix[i] = values[i*cols : (i+1)*cols]
+	}
+	return matrix, size, nil
+}
+
+// NonFiniteError valor NaN o infinito en la posición Row, Column (desde 1)
+type NonFiniteError struct {
+	Row    int
+	Column int
+}
+
+func (e *NonFiniteError) Error() string {
+	return fmt.Sprintf("non-finite value at row %d, column %d", e.Row, e.Column)
+}
+
+// CheckFinite rechaza NaN e infinitos, que MessagePack y CBOR pueden representar y JSON no
+// (*NonFiniteError)
+func CheckFinite(matrix [][]float64) error {
+	for i, row := range matrix {
+		for j, value := range row {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return &NonFiniteError{Row: i + 1, Column: j + 1}
+			}
+		}
+	}
+	return nil
+}
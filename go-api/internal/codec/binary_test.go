@@ -0,0 +1,224 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// processResult misma forma que models.MatrixProcessResponse, sin importar models
+type processResult struct {
+	Rotated   [][]float64 `json:"rotated"`
+	Q         [][]float64 `json:"q"`
+	R         [][]float64 `json:"r"`
+	ErrorCode string      `json:"errorCode,omitempty"`
+}
+
+func TestStructuredFormats_RoundTrip(t *testing.T) {
+	want := processResult{
+		Rotated: [][]float64{{4e-300, 1}, {0, 2.5}, {1e10, -3}},
+		Q:       [][]float64{{1, 0}, {0, 1}},
+		R:       fixtureMatrix,
+	}
+	tests := []struct {
+		name   string
+		encode func(*bytes.Buffer, interface{}) error
+		decode func([]byte, interface{}) error
+	}{
+		{
+			name:   "MessagePack",
+			encode: func(buf *bytes.Buffer, v interface{}) error { return EncodeMsgPack(buf, v) },
+			decode: DecodeMsgPack,
+		},
+		{
+			name:   "CBOR",
+			encode: func(buf *bytes.Buffer, v interface{}) error { return EncodeCBOR(buf, v) },
+			decode: DecodeCBOR,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.encode(&buf, want); err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			// Las claves son las del JSON, así que un cliente puede leerlo como mapa genérico
+			var generic map[string]interface{}
+			if err := tt.decode(buf.Bytes(), &generic); err != nil {
+				t.Fatalf("decode genérico: %v", err)
+			}
+			if _, ok := generic["rotated"]; !ok || len(generic) != 3 {
+				t.Errorf("claves = %v, want rotated, q, r sin errorCode", generic)
+			}
+
+			var got processResult
+			if err := tt.decode(buf.Bytes(), &got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ida y vuelta = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestFloat64_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeFloat64(&buf, fixtureMatrix); err != nil {
+		t.Fatalf("EncodeFloat64: %v", err)
+	}
+	if got, want := buf.Len(), 12+6*8; got != want {
+		t.Errorf("largo = %d, want %d", got, want)
+	}
+	if !bytes.Equal(buf.Bytes()[:12], []byte("MF64\x02\x00\x00\x00\x03\x00\x00\x00")) {
+		t.Errorf("encabezado = %q", buf.Bytes()[:12])
+	}
+	matrix, err := DecodeFloat64(buf.Bytes())
+	if err != nil || !reflect.DeepEqual(matrix, fixtureMatrix) {
+		t.Errorf("ida y vuelta = %v (err %v), want %v", matrix, err, fixtureMatrix)
+	}
+
+	buf.Reset()
+	sections := []Section{{Name: "a", Matrix: fixtureMatrix}, {Name: "b", Matrix: [][]float64{{7}}}}
+	if err := EncodeFloat64Sections(&buf, sections); err != nil {
+		t.Fatalf("EncodeFloat64Sections: %v", err)
+	}
+	matrices, err := DecodeFloat64Sections(buf.Bytes())
+	if err != nil || !reflect.DeepEqual(matrices, [][][]float64{fixtureMatrix, {{7}}}) {
+		t.Errorf("secciones = %v (err %v)", matrices, err)
+	}
+}
+
+func TestDecodeFloat64_Errors(t *testing.T) {
+	var valid bytes.Buffer
+	EncodeFloat64(&valid, fixtureMatrix)
+	withNaN := append([]byte(nil), valid.Bytes()...)
+	copy(withNaN[12+8:], []byte{0, 0, 0, 0, 0, 0, 0xf8, 0x7f})
+
+	tests := []struct {
+		name   string
+		data   []byte
+		column int
+		reason string
+	}{
+		{name: "sin magic", data: []byte("F64M\x01\x00\x00\x00\x01\x00\x00\x00"), column: 1, reason: ReasonFloat64MissingHeader},
+		{name: "encabezado incompleto", data: []byte("MF64\x01"), column: 1, reason: ReasonFloat64MissingHeader},
+		{name: "demasiados elementos", data: []byte("MF64\xff\xff\x00\x00\xff\xff\x00\x00"), column: 5, reason: ReasonTooManyElements},
+		{name: "sin columnas", data: []byte("MF64\xff\xff\xff\xff\x00\x00\x00\x00"), column: 5, reason: ReasonEmptyMatrix},
+		{name: "sin filas", data: []byte("MF64\x00\x00\x00\x00\x03\x00\x00\x00"), column: 5, reason: ReasonEmptyMatrix},
+		{name: "datos truncados", data: valid.Bytes()[:valid.Len()-3], column: valid.Len() - 2, reason: ReasonDataLength},
+		{name: "bytes sobrantes", data: append(valid.Bytes()[:valid.Len():valid.Len()], 0), column: valid.Len() + 1, reason: ReasonTrailingBytes},
+		{name: "NaN", data: withNaN, column: 21, reason: ReasonNonFiniteElement},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeFloat64(tt.data)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("err = %v, want *ParseError", err)
+			}
+			if parseErr.Column != tt.column {
+				t.Errorf("columna = %d, want %d (%s)", parseErr.Column, tt.column, parseErr.Reason)
+			}
+			if parseErr.Reason != tt.reason {
+				t.Errorf("motivo = %s, want %s", parseErr.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestCheckFinite(t *testing.T) {
+	if err := CheckFinite(fixtureMatrix); err != nil {
+		t.Errorf("CheckFinite(finita) = %v", err)
+	}
+	if err := CheckFinite([][]float64{{1, 2}, {3, math.Inf(-1)}}); err == nil || err.Error() != "non-finite value at row 2, column 2" {
+		t.Errorf("CheckFinite(-Inf) = %v", err)
+	}
+}
+
+// benchmarkFormats formatos comparados en los benchmarks, con el camino JSON actual como referencia
+var benchmarkFormats = []struct {
+	name   string
+	encode func(*bytes.Buffer, [][]float64) error
+	decode func([]byte) error
+}{
+	{
+		name:   "JSON",
+		encode: func(buf *bytes.Buffer, m [][]float64) error { return json.NewEncoder(buf).Encode(m) },
+		decode: func(data []byte) error { var m [][]float64; return json.Unmarshal(data, &m) },
+	},
+	{
+		name:   "MessagePack",
+		encode: func(buf *bytes.Buffer, m [][]float64) error { return EncodeMsgPack(buf, m) },
+		decode: func(data []byte) error { var m [][]float64; return DecodeMsgPack(data, &m) },
+	},
+	{
+		name:   "CBOR",
+		encode: func(buf *bytes.Buffer, m [][]float64) error { return EncodeCBOR(buf, m) },
+		decode: func(data []byte) error { var m [][]float64; return DecodeCBOR(data, &m) },
+	},
+	{
+		name:   "Float64",
+		encode: func(buf *bytes.Buffer, m [][]float64) error { return EncodeFloat64(buf, m) },
+		decode: func(data []byte) error { _, err := DecodeFloat64(data); return err },
+	},
+}
+
+// benchmarkMatrix matriz de 1000x1000 (1M de elementos) con valores aleatorios
+func benchmarkMatrix() [][]float64 {
+	rng := rand.New(rand.NewSource(1))
+	matrix := make([][]float64, 1000)
+	for i := range matrix {
+		matrix[i] = make([]float64, 1000)
+		for j := range matrix[i] {
+			matrix[i][j] = rng.NormFloat64()
+		}
+	}
+	return matrix
+}
+
+// Los benchmarks reportan MB/s sobre los 8 MB de datos de la matriz, no sobre el tamaño
+// codificado, para que el throughput sea comparable entre formatos; el tamaño va en bytes/op
+func BenchmarkEncode(b *testing.B) {
+	matrix := benchmarkMatrix()
+	for _, format := range benchmarkFormats {
+		b.Run(format.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			b.SetBytes(8 * 1000 * 1000)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if err := format.encode(&buf, matrix); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(buf.Len()), "encoded-bytes")
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	matrix := benchmarkMatrix()
+	for _, format := range benchmarkFormats {
+		b.Run(format.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			if err := format.encode(&buf, matrix); err != nil {
+				b.Fatal(err)
+			}
+			data := buf.Bytes()
+			b.SetBytes(8 * 1000 * 1000)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := format.decode(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
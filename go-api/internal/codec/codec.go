@@ -0,0 +1,73 @@
+// Package codec convierte matrices desde y hacia formatos distintos de JSON
+// (CSV y TSV exportados desde planillas, Matrix Market, NumPy .npy, MessagePack, CBOR
+// y float64 crudo).
+package codec
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+)
+
+// MIMEZip media type de un zip con un archivo por matriz
+const MIMEZip = "application/zip"
+
+// Section matriz con nombre dentro de una salida con varias matrices (ej: rotated, q, r)
+type Section struct {
+	Name   string
+	Matrix [][]float64
+}
+
+// ParseError error de formato con su posición en el cuerpo; Line y Column empiezan en 1.
+// Reason es un código estable del motivo (las constantes Reason*) y Params sus valores:
+// la API arma con ellos el mensaje en el idioma del cliente.
+type ParseError struct {
+	Line   int
+	Column int
+	Reason string
+	Params map[string]interface{}
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Params) == 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Reason)
+	}
+	return fmt.Sprintf("line %d, column %d: %s %v", e.Line, e.Column, e.Reason, e.Params)
+}
+
+// Motivos de los errores de dimensiones declaradas en un encabezado
+const (
+	ReasonEmptyMatrix     = "EMPTY_MATRIX"
+	ReasonTooManyElements = "TOO_MANY_ELEMENTS"
+)
+
+// checkDimensions valida las dimensiones declaradas en un encabezado antes de reservar
+// memoria: ninguna puede ser cero y cada una, y su producto, debe caber en MaxElements.
+// El *ParseError lleva la posición line, column del encabezado.
+func checkDimensions(line, column, rows, cols int) error {
+	params := map[string]interface{}{"rows": rows, "cols": cols}
+	switch {
+	case rows == 0 || cols == 0:
+		return &ParseError{Line: line, Column: column, Reason: ReasonEmptyMatrix, Params: params}
+	case rows > MaxElements || cols > MaxElements || cols > MaxElements/rows:
+		params["max"] = MaxElements
+		return &ParseError{Line: line, Column: column, Reason: ReasonTooManyElements, Params: params}
+	}
+	return nil
+}
+
+// EncodeZip escribe un zip con un archivo por sección (nombre + extensión, ej: q.csv)
+// codificado con encode. Con EncodeNPY y ".npy" el resultado es un .npz de NumPy.
+func EncodeZip(w io.Writer, sections []Section, extension string, encode func(io.Writer, [][]float64) error) error {
+	archive := zip.NewWriter(w)
+	for _, section := range sections {
+		file, err := archive.Create(section.Name + extension)
+		if err != nil {
+			return err
+		}
+		if err := encode(file, section.Matrix); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
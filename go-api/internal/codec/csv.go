@@ -0,0 +1,164 @@
+package codec
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Media types de matrices en texto delimitado
+const (
+	MIMECSV = "text/csv"
+	MIMETSV = "text/tab-separated-values"
+)
+
+// Motivos de los errores de formato del texto delimitado; InvalidNumber y NonFiniteNumber
+// también los usa Matrix Market
+const (
+	ReasonBareQuote       = "BARE_QUOTE"
+	ReasonQuote           = "QUOTE"
+	ReasonRowLength       = "ROW_LENGTH"
+	ReasonEmptyCell       = "EMPTY_CELL"
+	ReasonDecimalPoint    = "DECIMAL_POINT"
+	ReasonInvalidNumber   = "INVALID_NUMBER"
+	ReasonNonFiniteNumber = "NON_FINITE_NUMBER"
+)
+
+// CSVOptions formato del texto delimitado
+type CSVOptions struct {
+	// Delimiter separador de celdas (',' en CSV, '\t' en TSV)
+	Delimiter rune
+	// Header indica que la primera fila son títulos y se ignora al leer
+	Header bool
+	// DecimalComma usa ',' como separador decimal (planillas en español)
+	DecimalComma bool
+}
+
+// DecodeCSV lee una matriz de texto delimitado: una fila por línea, todas con la misma
+// cantidad de celdas. Las líneas vacías y las que empiezan con '#' se ignoran.
+// Los errores de formato son *ParseError; Column es el número de celda, salvo en los
+// errores de comillas, donde es la posición del carácter en la línea.
+func DecodeCSV(r io.Reader, opts CSVOptions) ([][]float64, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Delimiter
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	var matrix [][]float64
+	expected := -1
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return matrix, nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				reason := ReasonQuote
+				if errors.Is(parseErr.Err, csv.ErrBareQuote) {
+					reason = ReasonBareQuote
+				}
+				return nil, &ParseError{Line: parseErr.Line, Column: parseErr.Column, Reason: reason}
+			}
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if expected < 0 {
+			expected = len(record)
+		} else if len(record) != expected {
+			column := min(len(record), expected) + 1
+			return nil, &ParseError{Line: line, Column: column, Reason: ReasonRowLength, Params: map[string]interface{}{
+				"cells": len(record), "expected": expected,
+			}}
+		}
+		if first && opts.Header {
+			continue
+		}
+
+		row := make([]float64, len(record))
+		for j, field := range record {
+			value, reason := parseNumber(field, opts.DecimalComma)
+			if reason != "" {
+				return nil, &ParseError{Line: line, Column: j + 1, Reason: reason, Params: map[string]interface{}{"value": field}}
+			}
+			row[j] = value
+		}
+		matrix = append(matrix, row)
+	}
+}
+
+// parseNumber interpreta una celda como número finito; si no puede, retorna el motivo
+func parseNumber(field string, decimalComma bool) (float64, string) {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return 0, ReasonEmptyCell
+	}
+	text := field
+	if decimalComma {
+		if strings.Contains(text, ".") {
+			return 0, ReasonDecimalPoint
+		}
+		text = strings.Replace(text, ",", ".", 1)
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, ReasonInvalidNumber
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, ReasonNonFiniteNumber
+	}
+	return value, ""
+}
+
+// EncodeCSV escribe la matriz como texto delimitado, una fila por línea
+func EncodeCSV(w io.Writer, matrix [][]float64, opts CSVOptions) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = opts.Delimiter
+	record := make([]string, 0)
+	for _, row := range matrix {
+		record = record[:0]
+		for _, value := range row {
+			record = append(record, formatNumber(value, opts.DecimalComma))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// EncodeCSVSections escribe varias matrices en un solo texto: cada una precedida por
+// una línea "# nombre" y separadas por una línea vacía. Cada sección se puede leer de
+// vuelta con DecodeCSV.
+func EncodeCSVSections(w io.Writer, sections []Section, opts CSVOptions) error {
+	for i, section := range sections {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# %s\n", section.Name); err != nil {
+			return err
+		}
+		if err := EncodeCSV(w, section.Matrix, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatNumber representación más corta que conserva el valor exacto
+func formatNumber(value float64, decimalComma bool) string {
+	text := strconv.FormatFloat(value, 'g', -1, 64)
+	if decimalComma {
+		text = strings.Replace(text, ".", ",", 1)
+	}
+	return text
+}
@@ -0,0 +1,132 @@
+package codec
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeCSV(t *testing.T) {
+	csv := CSVOptions{Delimiter: ','}
+	tests := []struct {
+		name      string
+		input     string
+		opts      CSVOptions
+		expected  [][]float64
+		wantError *ParseError
+	}{
+		{name: "CSV simple", input: "1,2\n3,4\n", opts: csv, expected: [][]float64{{1, 2}, {3, 4}}},
+		{name: "TSV con CRLF", input: "1\t2\r\n3\t4\r\n", opts: CSVOptions{Delimiter: '\t'}, expected: [][]float64{{1, 2}, {3, 4}}},
+		{name: "fila de títulos", input: "a,b\n1,2\n", opts: CSVOptions{Delimiter: ',', Header: true}, expected: [][]float64{{1, 2}}},
+		{name: "coma decimal", input: "1,5;-2\n3;4,25e1\n", opts: CSVOptions{Delimiter: ';', DecimalComma: true}, expected: [][]float64{{1.5, -2}, {3, 42.5}}},
+		{name: "comentarios, espacios y líneas vacías", input: "# rotated\n 1, 2\n\n\"3\",4\n", opts: csv, expected: [][]float64{{1, 2}, {3, 4}}},
+		{name: "cuerpo vacío", input: "", opts: csv, expected: nil},
+		{name: "número inválido", input: "1,2\n3,x\n", opts: csv, wantError: &ParseError{Line: 2, Column: 2}},
+		{name: "celda vacía", input: "1,,3\n", opts: csv, wantError: &ParseError{Line: 1, Column: 2}},
+		{name: "fila con menos celdas", input: "1,2,3\n\n4,5\n", opts: csv, wantError: &ParseError{Line: 3, Column: 3}},
+		{name: "fila con más celdas", input: "1,2\n3,4,5\n", opts: csv, wantError: &ParseError{Line: 2, Column: 3}},
+		{name: "punto con coma decimal", input: "1.5;2\n", opts: CSVOptions{Delimiter: ';', DecimalComma: true}, wantError: &ParseError{Line: 1, Column: 1}},
+		{name: "valor no finito", input: "1,NaN\n", opts: csv, wantError: &ParseError{Line: 1, Column: 2}},
+		{name: "comillas sin cerrar", input: "1,2\n3,\"4\n", opts: csv, wantError: &ParseError{Line: 2, Column: 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matrix, err := DecodeCSV(strings.NewReader(tt.input), tt.opts)
+			if tt.wantError != nil {
+				var parseErr *ParseError
+				if !errors.As(err, &parseErr) {
+					t.Fatalf("err = %v, want *ParseError", err)
+				}
+				if parseErr.Line != tt.wantError.Line || parseErr.Column != tt.wantError.Column {
+					t.Errorf("posición = línea %d, columna %d; want línea %d, columna %d (%s)",
+						parseErr.Line, parseErr.Column, tt.wantError.Line, tt.wantError.Column, parseErr.Reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !reflect.DeepEqual(matrix, tt.expected) {
+				t.Errorf("matriz = %v, want %v", matrix, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeCSVSections_RoundTrip(t *testing.T) {
+	sections := []Section{
+		{Name: "rotated", Matrix: [][]float64{{3, 1}, {4, 2}}},
+		{Name: "q", Matrix: [][]float64{{-0.31622776601683794, -0.9486832980505138}, {-0.9486832980505138, 0.31622776601683794}}},
+		{Name: "r", Matrix: [][]float64{{-3.1622776601683795, -4.427188724235731}, {0, -0.6324555320336759}}},
+	}
+	tests := []struct {
+		name string
+		opts CSVOptions
+	}{
+		{name: "CSV", opts: CSVOptions{Delimiter: ','}},
+		{name: "TSV", opts: CSVOptions{Delimiter: '\t'}},
+		{name: "coma decimal", opts: CSVOptions{Delimiter: ';', DecimalComma: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeCSVSections(&buf, sections, tt.opts); err != nil {
+				t.Fatalf("EncodeCSVSections: %v", err)
+			}
+			parts := strings.Split(buf.String(), "\n\n")
+			if len(parts) != len(sections) {
+				t.Fatalf("secciones = %d, want %d:\n%s", len(parts), len(sections), buf.String())
+			}
+			for i, part := range parts {
+				if want := "# " + sections[i].Name + "\n"; !strings.HasPrefix(part, want) {
+					t.Errorf("sección %d empieza con %q, want %q", i, part, want)
+				}
+				matrix, err := DecodeCSV(strings.NewReader(part), tt.opts)
+				if err != nil {
+					t.Fatalf("DecodeCSV(%s): %v", sections[i].Name, err)
+				}
+				if !reflect.DeepEqual(matrix, sections[i].Matrix) {
+					t.Errorf("%s = %v, want %v", sections[i].Name, matrix, sections[i].Matrix)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeZip_CSV(t *testing.T) {
+	sections := []Section{
+		{Name: "q", Matrix: [][]float64{{1, 0}, {0, 1}}},
+		{Name: "r", Matrix: [][]float64{{2.5, 1}, {0, 3}}},
+	}
+	var buf bytes.Buffer
+	opts := CSVOptions{Delimiter: '\t'}
+	if err := EncodeZip(&buf, sections, ".tsv", func(w io.Writer, m [][]float64) error { return EncodeCSV(w, m, opts) }); err != nil {
+		t.Fatalf("EncodeZip: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip inválido: %v", err)
+	}
+	if len(archive.File) != len(sections) {
+		t.Fatalf("archivos = %d, want %d", len(archive.File), len(sections))
+	}
+	for i, file := range archive.File {
+		if file.Name != sections[i].Name+".tsv" {
+			t.Errorf("archivo %d = %s, want %s.tsv", i, file.Name, sections[i].Name)
+		}
+		f, _ := file.Open()
+		data, _ := io.ReadAll(f)
+		f.Close()
+		matrix, err := DecodeCSV(bytes.NewReader(data), opts)
+		if err != nil || !reflect.DeepEqual(matrix, sections[i].Matrix) {
+			t.Errorf("%s = %v (err %v), want %v", file.Name, matrix, err, sections[i].Matrix)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package codec
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MIMEMatrixMarket media type de Matrix Market (.mtx)
+const MIMEMatrixMarket = "text/x-matrix-market"
+
+// MaxElements cantidad máxima de elementos de una matriz decodificada. Un archivo
+// coordinate pequeño puede declarar dimensiones enormes: se valida antes de reservar memoria.
+var MaxElements = 1 << 22
+
+// Motivos de los errores de formato de Matrix Market, además de InvalidNumber y
+// NonFiniteNumber
+const (
+	ReasonMTXMissingHeader        = "MTX_MISSING_HEADER"
+	ReasonMTXInvalidHeader        = "MTX_INVALID_HEADER"
+	ReasonMTXUnsupportedObject    = "MTX_UNSUPPORTED_OBJECT"
+	ReasonMTXUnsupportedFormat    = "MTX_UNSUPPORTED_FORMAT"
+	ReasonMTXUnsupportedField     = "MTX_UNSUPPORTED_FIELD"
+	ReasonMTXPatternNotCoordinate = "MTX_PATTERN_NOT_COORDINATE"
+	ReasonMTXUnsupportedSymmetry  = "MTX_UNSUPPORTED_SYMMETRY"
+	ReasonMTXMissingSize          = "MTX_MISSING_SIZE"
+	ReasonMTXSizeFields           = "MTX_SIZE_FIELDS"
+	ReasonMTXInvalidSize          = "MTX_INVALID_SIZE"
+	ReasonMTXNotSquare            = "MTX_NOT_SQUARE"
+	ReasonMTXEntryCount           = "MTX_ENTRY_COUNT"
+	ReasonMTXEntryFields          = "MTX_ENTRY_FIELDS"
+	ReasonMTXIndexOutOfRange      = "MTX_INDEX_OUT_OF_RANGE"
+	ReasonMTXSkewDiagonal         = "MTX_SKEW_DIAGONAL"
+	ReasonMTXExtraEntry           = "MTX_EXTRA_ENTRY"
+)
+
+// mtxHeader encabezado "%%MatrixMarket matrix <format> <field> <symmetry>"
+type mtxHeader struct {
+	coordinate bool
+	pattern    bool
+	symmetry   string
+}
+
+// DecodeMatrixMarket lee una matriz en formato Matrix Market, coordinate o array, con
+// valores real, integer o pattern y simetría general, symmetric o skew-symmetric.
+// Los errores de formato son *ParseError; Column es el número de campo en la línea.
+func DecodeMatrixMarket(r io.Reader) ([][]float64, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	// next retorna los campos de la próxima línea que no es comentario ni vacía
+	next := func() ([]string, error) {
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "%") {
+				continue
+			}
+			return strings.Fields(text), nil
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	if !scanner.Scan() {
+		return nil, &ParseError{Line: 1, Column: 1, Reason: ReasonMTXMissingHeader}
+	}
+	line = 1
+	header, err := parseMTXHeader(strings.Fields(scanner.Text()))
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := next()
+	if errors.Is(err, io.EOF) {
+		return nil, &ParseError{Line: line + 1, Column: 1, Reason: ReasonMTXMissingSize}
+	}
+	if err != nil {
+		return nil, err
+	}
+	wantFields := 2
+	if header.coordinate {
+		wantFields = 3
+	}
+	if len(size) != wantFields {
+		return nil, &ParseError{Line: line, Column: 1, Reason: ReasonMTXSizeFields, Params: map[string]interface{}{
+			"fields": len(size), "expected": wantFields,
+		}}
+	}
+	dims := make([]int, len(size))
+	for i, field := range size {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, &ParseError{Line: line, Column: i + 1, Reason: ReasonMTXInvalidSize, Params: map[string]interface{}{"value": field}}
+		}
+		dims[i] = n
+	}
+	rows, cols := dims[0], dims[1]
+	if header.symmetry != "general" && rows != cols {
+		return nil, &ParseError{Line: line, Column: 1, Reason: ReasonMTXNotSquare, Params: map[string]interface{}{
+			"symmetry": header.symmetry, "rows": rows, "cols": cols,
+		}}
+	}
+	if err := checkDimensions(line, 1, rows, cols); err != nil {
+		return nil, err
+	}
+
+	data := make([]float64, rows*cols)
+	matrix := make([][]float64, rows)
+	for i := range matrix {
+		matrix[i] = data[i*cols : (i+1)*cols]
+	}
+	set := func(i, j int, value float64) {
+		matrix[i][j] = value
+		switch header.symmetry {
+		case "symmetric":
+			matrix[j][i] = value
+		case "skew-symmetric":
+			matrix[j][i] = -value
+		}
+	}
+
+	// En array los valores van por columnas y, si la matriz es simétrica, solo se
+	// almacena el triángulo inferior (sin la diagonal si es antisimétrica)
+	firstRow := func(j int) int {
+		switch header.symmetry {
+		case "symmetric":
+			return j
+		case "skew-symmetric":
+			return j + 1
+		}
+		return 0
+	}
+	entries := rows * cols
+	switch {
+	case header.coordinate:
+		entries = dims[2]
+	case header.symmetry == "symmetric":
+		entries = rows * (rows + 1) / 2
+	case header.symmetry == "skew-symmetric":
+		entries = rows * (rows - 1) / 2
+	}
+	nextRow, nextCol := firstRow(0), 0
+
+	for k := 0; k < entries; k++ {
+		fields, err := next()
+		if errors.Is(err, io.EOF) {
+			return nil, &ParseError{Line: line + 1, Column: 1, Reason: ReasonMTXEntryCount, Params: map[string]interface{}{
+				"expected": entries, "got": k,
+			}}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var i, j int
+		values := fields
+		if header.coordinate {
+			want := 3
+			if header.pattern {
+				want = 2
+			}
+			if len(fields) != want {
+				return nil, &ParseError{Line: line, Column: 1, Reason: ReasonMTXEntryFields, Params: map[string]interface{}{
+					"fields": len(fields), "expected": want,
+				}}
+			}
+			for f, target := range []*int{&i, &j} {
+				n, err := strconv.Atoi(fields[f])
+				limit := rows
+				if f == 1 {
+					limit = cols
+				}
+				if err != nil || n < 1 || n > limit {
+					return nil, &ParseError{Line: line, Column: f + 1, Reason: ReasonMTXIndexOutOfRange, Params: map[string]interface{}{
+						"value": fields[f], "limit": limit,
+					}}
+				}
+				*target = n - 1
+			}
+			values = fields[2:]
+		} else {
+			if len(fields) != 1 {
+				return nil, &ParseError{Line: line, Column: 2, Reason: ReasonMTXEntryFields, Params: map[string]interface{}{
+					"fields": len(fields), "expected": 1,
+				}}
+			}
+			for nextRow >= rows {
+				nextCol++
+				nextRow = firstRow(nextCol)
+			}
+			i, j = nextRow, nextCol
+			nextRow++
+		}
+		if header.symmetry == "skew-symmetric" && i == j {
+			return nil, &ParseError{Line: line, Column: 1, Reason: ReasonMTXSkewDiagonal}
+		}
+
+		value := 1.0
+		if !header.pattern {
+			column := len(fields)
+			value, err = strconv.ParseFloat(values[0], 64)
+			if err != nil {
+				return nil, &ParseError{Line: line, Column: column, Reason: ReasonInvalidNumber, Params: map[string]interface{}{"value": values[0]}}
+			}
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, &ParseError{Line: line, Column: column, Reason: ReasonNonFiniteNumber, Params: map[string]interface{}{"value": values[0]}}
+			}
+		}
+		set(i, j, value)
+	}
+
+	if fields, err := next(); err == nil {
+		return nil, &ParseError{Line: line, Column: 1, Reason: ReasonMTXExtraEntry, Params: map[string]interface{}{
+			"entry": strings.Join(fields, " "), "entries": entries,
+		}}
+	} else if !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+// parseMTXHeader valida la primera línea del archivo
+func parseMTXHeader(fields []string) (mtxHeader, error) {
+	if len(fields) != 5 || !strings.EqualFold(fields[0], "%%MatrixMarket") {
+		return mtxHeader{}, &ParseError{Line: 1, Column: 1, Reason: ReasonMTXInvalidHeader}
+	}
+	for i := range fields {
+		fields[i] = strings.ToLower(fields[i])
+	}
+	if fields[1] != "matrix" {
+		return mtxHeader{}, &ParseError{Line: 1, Column: 2, Reason: ReasonMTXUnsupportedObject, Params: map[string]interface{}{"value": fields[1]}}
+	}
+
+	var h mtxHeader
+	switch fields[2] {
+	case "coordinate":
+		h.coordinate = true
+	case "array":
+	default:
+		return h, &ParseError{Line: 1, Column: 3, Reason: ReasonMTXUnsupportedFormat, Params: map[string]interface{}{"value": fields[2]}}
+	}
+	switch fields[3] {
+	case "real", "double", "integer":
+	case "pattern":
+		if !h.coordinate {
+			return h, &ParseError{Line: 1, Column: 4, Reason: ReasonMTXPatternNotCoordinate}
+		}
+		h.pattern = true
+	default:
+		return h, &ParseError{Line: 1, Column: 4, Reason: ReasonMTXUnsupportedField, Params: map[string]interface{}{"value": fields[3]}}
+	}
+	switch fields[4] {
+	case "general", "symmetric", "skew-symmetric":
+		h.symmetry = fields[4]
+	case "hermitian":
+		// Con valores reales, hermitiana equivale a simétrica
+		h.symmetry = "symmetric"
+	default:
+		return h, &ParseError{Line: 1, Column: 5, Reason: ReasonMTXUnsupportedSymmetry, Params: map[string]interface{}{"value": fields[4]}}
+	}
+	return h, nil
+}
+
+// EncodeMatrixMarket escribe la matriz como Matrix Market real general: array (todos los
+// valores por columnas) o coordinate (solo los distintos de cero, por columnas)
+func EncodeMatrixMarket(w io.Writer, matrix [][]float64, coordinate bool) error {
+	bw := bufio.NewWriter(w)
+	rows, cols := len(matrix), 0
+	if rows > 0 {
+		cols = len(matrix[0])
+	}
+
+	if coordinate {
+		nonzeros := 0
+		for _, row := range matrix {
+			for _, value := range row {
+				if value != 0 {
+					nonzeros++
+				}
+			}
+		}
+		fmt.Fprintf(bw, "%%%%MatrixMarket matrix coordinate real general\n%d %d %d\n", rows, cols, nonzeros)
+	} else {
+		fmt.Fprintf(bw, "%%%%MatrixMarket matrix array real general\n%d %d\n", rows, cols)
+	}
+
+	buf := make([]byte, 0, 64)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			value := matrix[i][j]
+			buf = buf[:0]
+			if coordinate {
+				if value == 0 {
+					continue
+				}
+				buf = strconv.AppendInt(buf, int64(i+1), 10)
+				buf = append(buf, ' ')
+				buf = strconv.AppendInt(buf, int64(j+1), 10)
+				buf = append(buf, ' ')
+			}
+			buf = strconv.AppendFloat(buf, value, 'g', -1, 64)
+			buf = append(buf, '\n')
+			bw.Write(buf)
+		}
+	}
+	return bw.Flush()
+}
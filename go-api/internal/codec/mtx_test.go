@@ -0,0 +1,120 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fixtureMatrix matriz guardada en testdata/general.npy, array_general.mtx y coordinate_general.mtx
+var fixtureMatrix = [][]float64{{1, 2.5, -3}, {4e-300, 0, 1e10}}
+
+// readFixture lee un archivo de testdata
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func TestDecodeMatrixMarket_Fixtures(t *testing.T) {
+	tests := []struct {
+		file     string
+		expected [][]float64
+	}{
+		{file: "array_general.mtx", expected: fixtureMatrix},
+		{file: "coordinate_general.mtx", expected: fixtureMatrix},
+		{file: "nist_example.mtx", expected: [][]float64{
+			{1, 0, 0, 6, 0},
+			{0, 10.5, 0, 0, 0},
+			{0, 0, 0.015, 0, 0},
+			{0, 250.5, 0, -280, 33.32},
+			{0, 0, 0, 0, 12},
+		}},
+		{file: "symmetric.mtx", expected: [][]float64{{4, -1, 0}, {-1, 0, 2}, {0, 2, 5}}},
+		{file: "skew_array.mtx", expected: [][]float64{{0, -1.5, 2}, {1.5, 0, -3}, {-2, 3, 0}}},
+		{file: "pattern.mtx", expected: [][]float64{{0, 1}, {1, 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			matrix, err := DecodeMatrixMarket(bytes.NewReader(readFixture(t, tt.file)))
+			if err != nil {
+				t.Fatalf("DecodeMatrixMarket: %v", err)
+			}
+			if !reflect.DeepEqual(matrix, tt.expected) {
+				t.Errorf("matriz = %v, want %v", matrix, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeMatrixMarket_RoundTrip(t *testing.T) {
+	tests := []struct {
+		file       string
+		coordinate bool
+	}{
+		{file: "array_general.mtx"},
+		{file: "coordinate_general.mtx", coordinate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeMatrixMarket(&buf, fixtureMatrix, tt.coordinate); err != nil {
+				t.Fatalf("EncodeMatrixMarket: %v", err)
+			}
+			if want := readFixture(t, tt.file); !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("salida =\n%s\nwant\n%s", buf.Bytes(), want)
+			}
+			matrix, err := DecodeMatrixMarket(&buf)
+			if err != nil || !reflect.DeepEqual(matrix, fixtureMatrix) {
+				t.Errorf("ida y vuelta = %v (err %v), want %v", matrix, err, fixtureMatrix)
+			}
+		})
+	}
+}
+
+func TestDecodeMatrixMarket_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+		col   int
+	}{
+		{name: "sin encabezado", input: "2 2\n1\n2\n3\n4\n", line: 1, col: 1},
+		{name: "valores complejos", input: "%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 0\n", line: 1, col: 4},
+		{name: "pattern en array", input: "%%MatrixMarket matrix array pattern general\n1 1\n", line: 1, col: 4},
+		{name: "línea de tamaño incompleta", input: "%%MatrixMarket matrix coordinate real general\n% comentario\n2 2\n", line: 3, col: 1},
+		{name: "simétrica no cuadrada", input: "%%MatrixMarket matrix array real symmetric\n2 3\n", line: 2, col: 1},
+		{name: "demasiados elementos", input: "%%MatrixMarket matrix coordinate real general\n100000 100000 1\n1 1 1\n", line: 2, col: 1},
+		{name: "array sin columnas", input: "%%MatrixMarket matrix array real general\n4000000000 0\n", line: 2, col: 1},
+		{name: "coordinate sin filas", input: "%%MatrixMarket matrix coordinate real general\n0 3 0\n", line: 2, col: 1},
+		{name: "dimensión enorme", input: "%%MatrixMarket matrix array real general\n9000000000000000000 0\n", line: 2, col: 1},
+		{name: "índice fuera de rango", input: "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 3 1\n", line: 3, col: 2},
+		{name: "número inválido", input: "%%MatrixMarket matrix array real general\n1 2\n1\nx\n", line: 4, col: 1},
+		{name: "faltan entradas", input: "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n", line: 4, col: 1},
+		{name: "entradas de más", input: "%%MatrixMarket matrix array real general\n1 1\n1\n2\n", line: 4, col: 1},
+		{name: "diagonal en antisimétrica", input: "%%MatrixMarket matrix coordinate real skew-symmetric\n2 2 1\n1 1 1\n", line: 3, col: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeMatrixMarket(strings.NewReader(tt.input))
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("err = %v, want *ParseError", err)
+			}
+			if parseErr.Line != tt.line || parseErr.Column != tt.col {
+				t.Errorf("posición = línea %d, columna %d; want línea %d, columna %d (%s)",
+					parseErr.Line, parseErr.Column, tt.line, tt.col, parseErr.Reason)
+			}
+		})
+	}
+}
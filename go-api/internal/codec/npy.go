@@ -0,0 +1,164 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Media types de NumPy: un arreglo (.npy) o un zip de arreglos con nombre (.npz)
+const (
+	MIMENPY = "application/x-npy"
+	MIMENPZ = "application/x-npz"
+)
+
+// Motivos de los errores de formato de .npy; DataLength y NonFiniteElement también los usa
+// el float64 crudo
+const (
+	ReasonNPYMissingMagic       = "NPY_MISSING_MAGIC"
+	ReasonNPYTruncatedHeader    = "NPY_TRUNCATED_HEADER"
+	ReasonNPYUnsupportedVersion = "NPY_UNSUPPORTED_VERSION"
+	ReasonNPYHeaderLength       = "NPY_HEADER_LENGTH"
+	ReasonNPYUnsupportedDtype   = "NPY_UNSUPPORTED_DTYPE"
+	ReasonNPYMissingFortran     = "NPY_MISSING_FORTRAN_ORDER"
+	ReasonNPYMissingShape       = "NPY_MISSING_SHAPE"
+	ReasonNPYInvalidShape       = "NPY_INVALID_SHAPE"
+	ReasonNPYNot2D              = "NPY_NOT_2D"
+	ReasonDataLength            = "DATA_LENGTH"
+	ReasonNonFiniteElement      = "NON_FINITE_ELEMENT"
+)
+
+// npyMagic prefijo de todo archivo .npy
+var npyMagic = []byte("\x93NUMPY")
+
+// Campos del diccionario del encabezado .npy: {'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }
+var (
+	npyDescr   = regexp.MustCompile(`'descr'\s*:\s*'([<>|=]?f8)'`)
+	npyFortran = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`'shape'\s*:\s*\(([^)]*)\)`)
+)
+
+// DecodeNPY lee un arreglo NumPy .npy (versiones 1.0 a 3.0) de float64 con dos
+// dimensiones, en orden C o Fortran y en cualquier endianness.
+// Los errores de formato son *ParseError con Line 1 y Column igual a la posición del byte.
+func DecodeNPY(data []byte) ([][]float64, error) {
+	if len(data) < 10 || !bytes.HasPrefix(data, npyMagic) {
+		return nil, &ParseError{Line: 1, Column: 1, Reason: ReasonNPYMissingMagic}
+	}
+	major := data[6]
+	var headerLen, offset int
+	switch major {
+	case 1:
+		headerLen, offset = int(binary.LittleEndian.Uint16(data[8:10])), 10
+	case 2, 3:
+		if len(data) < 12 {
+			return nil, &ParseError{Line: 1, Column: 9, Reason: ReasonNPYTruncatedHeader}
+		}
+		headerLen, offset = int(binary.LittleEndian.Uint32(data[8:12])), 12
+	default:
+		return nil, &ParseError{Line: 1, Column: 7, Reason: ReasonNPYUnsupportedVersion, Params: map[string]interface{}{
+			"version": fmt.Sprintf("%d.%d", major, data[7]),
+		}}
+	}
+	if headerLen > len(data)-offset {
+		return nil, &ParseError{Line: 1, Column: offset + 1, Reason: ReasonNPYHeaderLength, Params: map[string]interface{}{
+			"length": headerLen,
+		}}
+	}
+	header := string(data[offset : offset+headerLen])
+	headerColumn := offset + 1
+
+	descr := npyDescr.FindStringSubmatch(header)
+	if descr == nil {
+		return nil, &ParseError{Line: 1, Column: headerColumn, Reason: ReasonNPYUnsupportedDtype}
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if strings.HasPrefix(descr[1], ">") {
+		order = binary.BigEndian
+	}
+	fortran := npyFortran.FindStringSubmatch(header)
+	if fortran == nil {
+		return nil, &ParseError{Line: 1, Column: headerColumn, Reason: ReasonNPYMissingFortran}
+	}
+	shape := npyShape.FindStringSubmatch(header)
+	if shape == nil {
+		return nil, &ParseError{Line: 1, Column: headerColumn, Reason: ReasonNPYMissingShape}
+	}
+	var dims []int
+	for _, field := range strings.Split(shape[1], ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, &ParseError{Line: 1, Column: headerColumn, Reason: ReasonNPYInvalidShape, Params: map[string]interface{}{"shape": shape[1]}}
+		}
+		dims = append(dims, n)
+	}
+	if len(dims) != 2 {
+		return nil, &ParseError{Line: 1, Column: headerColumn, Reason: ReasonNPYNot2D, Params: map[string]interface{}{"shape": shape[1]}}
+	}
+	rows, cols := dims[0], dims[1]
+	if err := checkDimensions(1, headerColumn, rows, cols); err != nil {
+		return nil, err
+	}
+
+	body := data[offset+headerLen:]
+	if want := rows * cols * 8; len(body) != want {
+		return nil, &ParseError{Line: 1, Column: offset + headerLen + 1, Reason: ReasonDataLength, Params: map[string]interface{}{
+			"expected": want, "got": len(body),
+		}}
+	}
+
+	values := make([]float64, rows*cols)
+	matrix := make([][]float64, rows)
+	for i := range matrix {
+		matrix[i] = values[i*cols : (i+1)*cols]
+	}
+	columnMajor := fortran[1] == "True"
+	for k := range values {
+		value := math.Float64frombits(order.Uint64(body[k*8:]))
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, &ParseError{Line: 1, Column: offset + headerLen + k*8 + 1, Reason: ReasonNonFiniteElement, Params: map[string]interface{}{
+				"element": k,
+			}}
+		}
+		if columnMajor {
+			matrix[k%rows][k/rows] = value
+		} else {
+			values[k] = value
+		}
+	}
+	return matrix, nil
+}
+
+// EncodeNPY escribe la matriz como .npy versión 1.0, float64 little-endian en orden C,
+// con el encabezado alineado a 64 bytes como lo escribe NumPy
+func EncodeNPY(w io.Writer, matrix [][]float64) error {
+	rows, cols := len(matrix), 0
+	if rows > 0 {
+		cols = len(matrix[0])
+	}
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%d, %d), }", rows, cols)
+	// magic (6) + versión (2) + largo (2) + encabezado + relleno + '\n'
+	padding := 63 - (10+len(header))%64
+	header += strings.Repeat(" ", padding) + "\n"
+
+	buf := make([]byte, 10, 10+len(header)+rows*cols*8)
+	copy(buf, npyMagic)
+	buf[6], buf[7] = 1, 0
+	binary.LittleEndian.PutUint16(buf[8:], uint16(len(header)))
+	buf = append(buf, header...)
+	for _, row := range matrix {
+		for _, value := range row {
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(value))
+		}
+	}
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,81 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeNPY_Fixtures(t *testing.T) {
+	// Todos los archivos guardan la misma matriz con distinto orden, endianness o versión
+	for _, file := range []string{"general.npy", "fortran.npy", "bigendian.npy", "v2.npy"} {
+		t.Run(file, func(t *testing.T) {
+			matrix, err := DecodeNPY(readFixture(t, file))
+			if err != nil {
+				t.Fatalf("DecodeNPY: %v", err)
+			}
+			if !reflect.DeepEqual(matrix, fixtureMatrix) {
+				t.Errorf("matriz = %v, want %v", matrix, fixtureMatrix)
+			}
+		})
+	}
+}
+
+func TestEncodeNPY_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeNPY(&buf, fixtureMatrix); err != nil {
+		t.Fatalf("EncodeNPY: %v", err)
+	}
+	// El encabezado queda alineado a 64 bytes, igual que el que escribe NumPy
+	if want := readFixture(t, "general.npy"); !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("salida = %q, want %q", buf.Bytes(), want)
+	}
+	matrix, err := DecodeNPY(buf.Bytes())
+	if err != nil || !reflect.DeepEqual(matrix, fixtureMatrix) {
+		t.Errorf("ida y vuelta = %v (err %v), want %v", matrix, err, fixtureMatrix)
+	}
+}
+
+func TestDecodeNPY_Errors(t *testing.T) {
+	valid := readFixture(t, "general.npy")
+	// withHeader reemplaza el diccionario del encabezado conservando su largo
+	withHeader := func(dict string) []byte {
+		data := append([]byte(nil), valid...)
+		header := data[10:128]
+		for i := range header {
+			header[i] = ' '
+		}
+		copy(header, dict)
+		return data
+	}
+
+	tests := []struct {
+		name   string
+		data   []byte
+		column int
+	}{
+		{name: "sin magic", data: []byte("NUMPY\x01\x00\x00\x00"), column: 1},
+		{name: "versión no soportada", data: append([]byte("\x93NUMPY\x09\x00"), valid[8:]...), column: 7},
+		{name: "dtype float32", data: withHeader("{'descr': '<f4', 'fortran_order': False, 'shape': (2, 3), }"), column: 11},
+		{name: "arreglo de una dimensión", data: withHeader("{'descr': '<f8', 'fortran_order': False, 'shape': (6,), }"), column: 11},
+		{name: "sin columnas", data: withHeader("{'descr': '<f8', 'fortran_order': False, 'shape': (50000000, 0), }"), column: 11},
+		{name: "sin filas", data: withHeader("{'descr': '<f8', 'fortran_order': False, 'shape': (0, 3), }"), column: 11},
+		{name: "demasiados elementos", data: withHeader("{'descr': '<f8', 'fortran_order': False, 'shape': (4096, 4096), }"), column: 11},
+		{name: "datos truncados", data: valid[:len(valid)-8], column: 129},
+		{name: "encabezado más largo que el cuerpo", data: valid[:64], column: 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeNPY(tt.data)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("err = %v, want *ParseError", err)
+			}
+			if parseErr.Column != tt.column {
+				t.Errorf("columna = %d, want %d (%s)", parseErr.Column, tt.column, parseErr.Reason)
+			}
+		})
+	}
+}
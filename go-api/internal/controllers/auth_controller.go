@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"go-api/internal/apperrors"
 	"go-api/internal/middleware"
 
 	"github.com/gofiber/fiber/v2"
@@ -40,18 +41,12 @@ func Login(c *fiber.Ctx) error {
 	var req LoginRequest
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Formato JSON inválido",
-			"message": err.Error(),
-		})
+		return middleware.WriteProblem(c, apperrors.Wrap(apperrors.CodeInvalidBody, err))
 	}
 
 	// Credenciales simples (en producción usar hash y base de datos)
 	if req.Username != "admin" || req.Password != "admin" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "Credenciales inválidas",
-			"message": "Usuario o contraseña incorrectos",
-		})
+		return middleware.WriteProblem(c, apperrors.New(apperrors.CodeInvalidCredentials, nil))
 	}
 
 	// Crear claims
@@ -68,19 +63,13 @@ func Login(c *fiber.Ctx) error {
 	// Generar token
 	secret, err := getJWTSecret()
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Error de configuración del servidor",
-			"message": err.Error(),
-		})
+		return middleware.WriteProblem(c, apperrors.Wrap(apperrors.CodeServerMisconfigured, err))
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(secret)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Error al generar token",
-			"message": err.Error(),
-		})
+		return middleware.WriteProblem(c, apperrors.Wrap(apperrors.CodeTokenGenerationFailed, err))
 	}
 
 	return c.JSON(LoginResponse{
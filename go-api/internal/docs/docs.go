@@ -0,0 +1,58 @@
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// OpenAPI especificación OpenAPI 3 de la API (fuente de verdad para generar SDKs)
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+// swaggerInitializer reemplaza el swagger-initializer.js de la distribución
+// para que Swagger UI cargue /openapi.json
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "/openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// Spec sirve la especificación OpenAPI
+// GET /openapi.json
+func Spec(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	return c.Send(OpenAPI)
+}
+
+// RedirectToUI redirige /docs a /docs/index.html para que las rutas relativas de Swagger UI resuelvan bien
+// (Fiber trata /docs y /docs/ como la misma ruta)
+// GET /docs
+func RedirectToUI(c *fiber.Ctx) error {
+	return c.Redirect("/docs/index.html", fiber.StatusMovedPermanently)
+}
+
+// Initializer sirve la configuración de Swagger UI apuntando a /openapi.json
+// GET /docs/swagger-initializer.js
+func Initializer(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "application/javascript")
+	return c.SendString(swaggerInitializer)
+}
+
+// UI sirve los archivos estáticos de Swagger UI embebidos en el binario
+// Se monta con app.Use("/docs", docs.UI())
+func UI() fiber.Handler {
+	return filesystem.New(filesystem.Config{
+		Root: http.FS(swaggerFiles.FS),
+	})
+}
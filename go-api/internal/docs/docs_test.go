@@ -0,0 +1,134 @@
+package docs
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+// openAPIDocument subconjunto de la especificación usado en los tests
+type openAPIDocument struct {
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+			Enum       []string                   `json:"enum"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) openAPIDocument {
+	t.Helper()
+	var doc openAPIDocument
+	if err := json.Unmarshal(OpenAPI, &doc); err != nil {
+		t.Fatalf("openapi.json no es JSON válido: %v", err)
+	}
+	return doc
+}
+
+// TestSpec_ModelsAreDocumented verifica que cada modelo de internal/models tenga un schema
+// con exactamente las mismas propiedades JSON
+func TestSpec_ModelsAreDocumented(t *testing.T) {
+	doc := loadSpec(t)
+
+	modelTypes := []interface{}{
+		models.MatrixRequest{},
+		models.SparseMatrix{},
+		models.Complex{},
+		models.ExactMatrixRequest{},
+		models.ExactQRResponse{},
+		models.ExactSolution{},
+		models.PrecisionResiduals{},
+		models.GramSchmidtExplanation{},
+		models.GramSchmidtStep{},
+		models.MatrixStatsRequest{},
+		models.MatrixStatsResponse{},
+		models.MatrixProcessResponse{},
+		models.CheckResult{},
+		models.ReadinessResponse{},
+		models.Problem{},
+		models.JobRequest{},
+		models.Job{},
+		models.WebhookDelivery{},
+		models.BatchItemOptions{},
+		models.BatchItem{},
+		models.BatchRequest{},
+		models.BatchItemResult{},
+		models.BatchResponse{},
+		models.CellUpdate{},
+		models.SessionMessage{},
+		models.SessionEvent{},
+		models.WorkspaceRequest{},
+		models.WorkspaceOperation{},
+		models.WorkspaceUpdateRequest{},
+		models.Workspace{},
+		models.CacheEntry{},
+		models.CacheStats{},
+		models.CachePurgeResponse{},
+	}
+
+	for _, model := range modelTypes {
+		typ := reflect.TypeOf(model)
+		t.Run(typ.Name(), func(t *testing.T) {
+			schema, ok := doc.Components.Schemas[typ.Name()]
+			if !ok {
+				t.Fatalf("Falta el schema %s en openapi.json", typ.Name())
+			}
+
+			fields := jsonFields(typ)
+			for _, name := range fields {
+				if _, ok := schema.Properties[name]; !ok {
+					t.Errorf("El schema %s no documenta la propiedad %q", typ.Name(), name)
+				}
+			}
+			if len(schema.Properties) != len(fields) {
+				t.Errorf("El schema %s tiene %d propiedades, el modelo tiene %d (%v)", typ.Name(), len(schema.Properties), len(fields), fields)
+			}
+		})
+	}
+}
+
+// jsonFields retorna los nombres JSON de los campos de typ; los campos de los
+// structs embebidos sin tag se incluyen como propios, igual que hace encoding/json
+func jsonFields(typ reflect.Type) []string {
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			fields = append(fields, jsonFields(embedded)...)
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+// TestSpec_ErrorCodes verifica que el enum ErrorCode coincida con el catálogo de apperrors
+func TestSpec_ErrorCodes(t *testing.T) {
+	doc := loadSpec(t)
+
+	documented := append([]string(nil), doc.Components.Schemas["ErrorCode"].Enum...)
+	sort.Strings(documented)
+
+	var codes []string
+	for _, code := range apperrors.Codes() {
+		codes = append(codes, string(code))
+	}
+
+	if !reflect.DeepEqual(documented, codes) {
+		t.Errorf("ErrorCode documentado = %v, catálogo = %v", documented, codes)
+	}
+}
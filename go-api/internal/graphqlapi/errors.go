@@ -0,0 +1,32 @@
+package graphqlapi
+
+import "go-api/internal/apperrors"
+
+// extendedError error de un resolver: el mensaje localizado y en extensions el código
+// estable, el estado HTTP equivalente y los detalles (como en application/problem+json)
+type extendedError struct {
+	message    string
+	extensions map[string]interface{}
+}
+
+func (e *extendedError) Error() string {
+	return e.message
+}
+
+// Extensions implementa gqlerrors.ExtendedError
+func (e *extendedError) Extensions() map[string]interface{} {
+	return e.extensions
+}
+
+// resolverError convierte un error en un error de GraphQL localizado en lang
+func resolverError(err error, lang string) error {
+	appErr := apperrors.From(err)
+	extensions := map[string]interface{}{
+		"code":   string(appErr.Code),
+		"status": appErr.Status,
+	}
+	if len(appErr.Details) > 0 {
+		extensions["details"] = appErr.Details
+	}
+	return &extendedError{message: appErr.Message(lang), extensions: extensions}
+}
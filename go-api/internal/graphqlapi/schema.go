@@ -0,0 +1,135 @@
+// Package graphqlapi expone el procesamiento de matrices por GraphQL. Los resolvers solo
+// calculan lo que pide la consulta: sin rotated no se rota, sin nodeStats no se llama a
+// Node.js y sin q no se construye Q.
+package graphqlapi
+
+import (
+	"context"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/services"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Request cuerpo de POST /v1/graphql (GraphQL sobre HTTP)
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// optionsKey clave de las opciones de la petición (token y lenguaje) en el contexto
+type optionsKey struct{}
+
+// matrixType [[Float!]!] matriz por filas
+var matrixType = graphql.NewList(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.Float))))
+
+var nodeStatsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "NodeStats",
+	Description: "Estadísticas calculadas por Node.js",
+	Fields: graphql.Fields{
+		"max":         {Type: graphql.NewNonNull(graphql.Float)},
+		"min":         {Type: graphql.NewNonNull(graphql.Float)},
+		"avg":         {Type: graphql.NewNonNull(graphql.Float)},
+		"sum":         {Type: graphql.NewNonNull(graphql.Float)},
+		"anyDiagonal": {Type: graphql.NewNonNull(graphql.Boolean)},
+	},
+})
+
+var matrixResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "MatrixResult",
+	Description: "Resultado de process; solo se calculan los campos seleccionados",
+	Fields: graphql.Fields{
+		"rotated": {Type: matrixType, Description: "Matriz rotada 90° en sentido horario"},
+		"q":       {Type: matrixType, Description: "Q ortogonal (rows×rows, o rows×cols con thin)"},
+		"r":       {Type: matrixType, Description: "R triangular superior (rows×cols, o cols×cols con thin)"},
+		"nodeStats": {
+			Type:        nodeStatsType,
+			Description: "Estadísticas de Node.js sobre la rotación y la factorización completa; null si Node.js no responde",
+		},
+		"error":     {Type: graphql.String, Description: "Mensaje localizado si Node.js no responde"},
+		"errorCode": {Type: graphql.String, Description: "NODE_STATS_UNAVAILABLE si Node.js no responde"},
+	},
+})
+
+// NewSchema crea el esquema con la consulta process sobre processor
+func NewSchema(processor *services.MatrixProcessor) (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"process": {
+				Type:        graphql.NewNonNull(matrixResultType),
+				Description: "Rota la matriz, calcula QR y obtiene estadísticas de Node.js, solo para los campos pedidos",
+				Args: graphql.FieldConfigArgument{
+					"matrix": {Type: graphql.NewNonNull(matrixType)},
+					"thin": {
+						Type:         graphql.Boolean,
+						DefaultValue: false,
+						Description:  "Factorización reducida: Q de rows×cols y R de cols×cols",
+					},
+				},
+				Resolve: processResolver(processor),
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// Execute ejecuta la consulta; opts lleva el token que se reenvía a Node.js y el idioma
+// de los mensajes de error
+func Execute(ctx context.Context, schema graphql.Schema, req Request, opts services.ProcessOptions) *graphql.Result {
+	return graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        context.WithValue(ctx, optionsKey{}, opts),
+	})
+}
+
+// processResolver resuelve process calculando solo los campos seleccionados
+func processResolver(processor *services.MatrixProcessor) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		opts, _ := p.Context.Value(optionsKey{}).(services.ProcessOptions)
+		matrix, err := toMatrix(p.Args["matrix"])
+		if err != nil {
+			return nil, resolverError(err, opts.Language)
+		}
+
+		fields := selectedFields(p.Info)
+		thin, _ := p.Args["thin"].(bool)
+		sel := services.Selection{
+			Rotated:   fields["rotated"],
+			Q:         fields["q"],
+			R:         fields["r"],
+			NodeStats: fields["nodeStats"] || fields["error"] || fields["errorCode"],
+			Thin:      thin,
+		}
+		response, err := processor.ProcessSelection(p.Context, matrix, sel, opts)
+		if err != nil {
+			return nil, resolverError(err, opts.Language)
+		}
+		return response, nil
+	}
+}
+
+// toMatrix convierte el argumento matrix ([]interface{} de filas) en [][]float64
+func toMatrix(arg interface{}) ([][]float64, error) {
+	rows, _ := arg.([]interface{})
+	matrix := make([][]float64, len(rows))
+	for i, row := range rows {
+		values, _ := row.([]interface{})
+		matrix[i] = make([]float64, len(values))
+		for j, value := range values {
+			f, ok := value.(float64)
+			if !ok {
+				return nil, apperrors.New(apperrors.CodeMatrixValueInvalid, map[string]interface{}{
+					"row": i + 1, "column": j + 1,
+				})
+			}
+			matrix[i][j] = f
+		}
+	}
+	return matrix, nil
+}
@@ -0,0 +1,22 @@
+package graphqlapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go-api/internal/apperrors"
+)
+
+func TestToMatrix(t *testing.T) {
+	matrix, err := toMatrix([]interface{}{[]interface{}{1.0, 2.0}, []interface{}{3.0, 4.0}})
+	if err != nil || !reflect.DeepEqual(matrix, [][]float64{{1, 2}, {3, 4}}) {
+		t.Errorf("toMatrix = %v, %v", matrix, err)
+	}
+
+	_, err = toMatrix([]interface{}{[]interface{}{1.0}, []interface{}{"x"}})
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) || appErr.Code != apperrors.CodeMatrixValueInvalid || appErr.Details["row"] != 2 || appErr.Details["column"] != 1 {
+		t.Errorf("err = %v, want MATRIX_VALUE_INVALID en la fila 2, columna 1", err)
+	}
+}
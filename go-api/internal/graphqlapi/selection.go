@@ -0,0 +1,35 @@
+package graphqlapi
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// selectedFields nombres de los campos seleccionados bajo el campo que se resuelve,
+// incluyendo los de fragmentos en línea y con nombre. Los campos con @skip/@include
+// cuentan como seleccionados: calcular de más es seguro, de menos no.
+func selectedFields(info graphql.ResolveInfo) map[string]bool {
+	fields := make(map[string]bool)
+	for _, field := range info.FieldASTs {
+		collectFields(field.SelectionSet, info.Fragments, fields)
+	}
+	return fields
+}
+
+func collectFields(set *ast.SelectionSet, fragments map[string]ast.Definition, fields map[string]bool) {
+	if set == nil {
+		return
+	}
+	for _, selection := range set.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			fields[s.Name.Value] = true
+		case *ast.InlineFragment:
+			collectFields(s.SelectionSet, fragments, fields)
+		case *ast.FragmentSpread:
+			if fragment, ok := fragments[s.Name.Value].(*ast.FragmentDefinition); ok {
+				collectFields(fragment.SelectionSet, fragments, fields)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package graphqlapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// resolveInfo arma el ResolveInfo del primer campo de la consulta, como lo recibe el resolver
+func resolveInfo(t *testing.T, query string) graphql.ResolveInfo {
+	t.Helper()
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	info := graphql.ResolveInfo{Fragments: make(map[string]ast.Definition)}
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.OperationDefinition:
+			info.FieldASTs = []*ast.Field{d.SelectionSet.Selections[0].(*ast.Field)}
+		case *ast.FragmentDefinition:
+			info.Fragments[d.Name.Value] = d
+		}
+	}
+	return info
+}
+
+func TestSelectedFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  map[string]bool
+	}{
+		{
+			name:  "campos directos",
+			query: `{ process(matrix: [[1]]) { r q } }`,
+			want:  map[string]bool{"r": true, "q": true},
+		},
+		{
+			name:  "alias cuenta el nombre del campo",
+			query: `{ process(matrix: [[1]]) { triangular: r } }`,
+			want:  map[string]bool{"r": true},
+		},
+		{
+			name:  "fragmento con nombre",
+			query: `{ process(matrix: [[1]]) { ...qr } } fragment qr on MatrixResult { q r }`,
+			want:  map[string]bool{"q": true, "r": true},
+		},
+		{
+			name:  "fragmento en línea",
+			query: `{ process(matrix: [[1]]) { rotated ... on MatrixResult { errorCode } } }`,
+			want:  map[string]bool{"rotated": true, "errorCode": true},
+		},
+		{
+			name:  "fragmentos anidados con alias",
+			query: `{ process(matrix: [[1]]) { ...outer } } fragment outer on MatrixResult { ... on MatrixResult { stats: nodeStats { max } } }`,
+			want:  map[string]bool{"nodeStats": true},
+		},
+		{
+			name:  "fragmento inexistente",
+			query: `{ process(matrix: [[1]]) { r ...missing } }`,
+			want:  map[string]bool{"r": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectedFields(resolveInfo(t, tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectedFields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go-api/internal/apperrors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain dominio de los google.rpc.ErrorInfo que acompañan a los errores
+const ErrorDomain = "go-api"
+
+// grpcCode código gRPC equivalente al código HTTP de un error de la API
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+// statusError convierte un error en un status gRPC con el mensaje localizado según
+// accept-language y un ErrorInfo con el código de la API (reason) y sus detalles
+func statusError(ctx context.Context, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	appErr := apperrors.From(err)
+	st := status.New(grpcCode(appErr.Status), appErr.Message(language(ctx)))
+	info := &errdetails.ErrorInfo{
+		Reason:   string(appErr.Code),
+		Domain:   ErrorDomain,
+		Metadata: stringDetails(appErr.Details),
+	}
+	if withDetails, detailsErr := st.WithDetails(info); detailsErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// stringDetails detalles del error como texto (la metadata de ErrorInfo es map<string, string>)
+func stringDetails(details map[string]interface{}) map[string]string {
+	if len(details) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(details))
+	for key, value := range details {
+		out[key] = fmt.Sprint(value)
+	}
+	return out
+}
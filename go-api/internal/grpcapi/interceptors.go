@@ -0,0 +1,127 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/metrics"
+	"go-api/internal/middleware"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// claimsKey clave de los claims del JWT en el contexto de la llamada
+type claimsKey struct{}
+
+// Claims retorna los claims del JWT validado por el interceptor de autenticación
+// (nil si la llamada no pasó por él)
+func Claims(ctx context.Context) *middleware.Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*middleware.Claims)
+	return claims
+}
+
+// metadataValue primer valor de una clave de la metadata entrante ("" si no está)
+func metadataValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// language idioma de los mensajes de error según la metadata accept-language
+func language(ctx context.Context) string {
+	return apperrors.NegotiateLanguage(metadataValue(ctx, "accept-language"))
+}
+
+// bearerToken token JWT de la metadata authorization, para reenviarlo a Node.js
+func bearerToken(ctx context.Context) string {
+	return strings.TrimPrefix(metadataValue(ctx, "authorization"), "Bearer ")
+}
+
+// authenticate valida la metadata authorization ("Bearer <token>") con la misma lógica
+// que middleware.AuthenticateToken y guarda los claims en el contexto
+func authenticate(ctx context.Context) (context.Context, error) {
+	claims, err := middleware.ParseBearerToken(metadataValue(ctx, "authorization"))
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return context.WithValue(ctx, claimsKey{}, claims), nil
+}
+
+// UnaryAuth interceptor que exige un JWT válido en las llamadas unarias
+func UnaryAuth(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamAuth interceptor que exige un JWT válido al abrir un stream
+func StreamAuth(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// contextStream ServerStream con el contexto reemplazado (con los claims del JWT)
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryMetrics interceptor que registra métricas y un log por llamada unaria
+func UnaryMetrics(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observe(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+// StreamMetrics interceptor que registra métricas y un log al cerrar cada stream
+func StreamMetrics(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	observe(ss.Context(), info.FullMethod, start, err)
+	return err
+}
+
+// observe registra la llamada terminada en las métricas gRPC y en el log, con el mismo
+// criterio de nivel que middleware.AccessLog (errores del servidor en error, del cliente en warn)
+func observe(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	elapsed := time.Since(start)
+	metrics.GRPCRequestsTotal.WithLabelValues(method, code.String()).Inc()
+	metrics.GRPCRequestDuration.WithLabelValues(method, code.String()).Observe(elapsed.Seconds())
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("latency_ms", float64(elapsed.Microseconds())/1000),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	slog.LogAttrs(ctx, level, "grpc request", attrs...)
+}
@@ -0,0 +1,262 @@
+// API gRPC de operaciones con matrices. Usa los mismos servicios que la API REST.
+//
+// Autenticación: cada llamada lleva el JWT de POST /v1/auth/login en la metadata
+// "authorization" con el valor "Bearer <token>". "accept-language" (es o en) elige el
+// idioma de los mensajes de error.
+//
+// Errores: el status gRPC lleva el mensaje localizado y un google.rpc.ErrorInfo con
+// reason igual al código de error de la API REST (ej: MATRIX_NOT_RECTANGULAR),
+// domain "go-api" y los detalles del error como metadata.
+//
+// Para regenerar el código Go (internal/grpcapi/matrixpb) desde go-api/:
+//
+//	protoc -I proto --go_out=. --go_opt=module=go-api --go-grpc_out=. --go-grpc_opt=module=go-api matrix/v1/matrix.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: matrix/v1/matrix.proto
+
+package matrixpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MatrixService_Process_FullMethodName      = "/matrix.v1.MatrixService/Process"
+	MatrixService_Decompose_FullMethodName    = "/matrix.v1.MatrixService/Decompose"
+	MatrixService_Solve_FullMethodName        = "/matrix.v1.MatrixService/Solve"
+	MatrixService_ProcessBatch_FullMethodName = "/matrix.v1.MatrixService/ProcessBatch"
+)
+
+// MatrixServiceClient is the client API for MatrixService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MatrixService procesamiento, factorización QR, resolución de sistemas y lotes
+type MatrixServiceClient interface {
+	// Process rota la matriz, calcula QR y obtiene estadísticas de Node.js
+	// (igual que POST /v1/matrix/process)
+	Process(ctx context.Context, in *ProcessRequest, opts ...grpc.CallOption) (*ProcessResponse, error)
+	// Decompose calcula solo la factorización QR (A = Q·R)
+	Decompose(ctx context.Context, in *DecomposeRequest, opts ...grpc.CallOption) (*DecomposeResponse, error)
+	// Solve resuelve A·x = b por mínimos cuadrados
+	Solve(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error)
+	// ProcessBatch procesa un lote en streaming bidireccional: cada elemento recibido se
+	// procesa en cuanto llega y su resultado se envía en cuanto está listo (no
+	// necesariamente en orden; usar id o index para asociarlos)
+	ProcessBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BatchItem, BatchItemResult], error)
+}
+
+type matrixServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMatrixServiceClient(cc grpc.ClientConnInterface) MatrixServiceClient {
+	return &matrixServiceClient{cc}
+}
+
+func (c *matrixServiceClient) Process(ctx context.Context, in *ProcessRequest, opts ...grpc.CallOption) (*ProcessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessResponse)
+	err := c.cc.Invoke(ctx, MatrixService_Process_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matrixServiceClient) Decompose(ctx context.Context, in *DecomposeRequest, opts ...grpc.CallOption) (*DecomposeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecomposeResponse)
+	err := c.cc.Invoke(ctx, MatrixService_Decompose_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matrixServiceClient) Solve(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SolveResponse)
+	err := c.cc.Invoke(ctx, MatrixService_Solve_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matrixServiceClient) ProcessBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BatchItem, BatchItemResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MatrixService_ServiceDesc.Streams[0], MatrixService_ProcessBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BatchItem, BatchItemResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatrixService_ProcessBatchClient = grpc.BidiStreamingClient[BatchItem, BatchItemResult]
+
+// MatrixServiceServer is the server API for MatrixService service.
+// All implementations must embed UnimplementedMatrixServiceServer
+// for forward compatibility.
+//
+// MatrixService procesamiento, factorización QR, resolución de sistemas y lotes
+type MatrixServiceServer interface {
+	// Process rota la matriz, calcula QR y obtiene estadísticas de Node.js
+	// (igual que POST /v1/matrix/process)
+	Process(context.Context, *ProcessRequest) (*ProcessResponse, error)
+	// Decompose calcula solo la factorización QR (A = Q·R)
+	Decompose(context.Context, *DecomposeRequest) (*DecomposeResponse, error)
+	// Solve resuelve A·x = b por mínimos cuadrados
+	Solve(context.Context, *SolveRequest) (*SolveResponse, error)
+	// ProcessBatch procesa un lote en streaming bidireccional: cada elemento recibido se
+	// procesa en cuanto llega y su resultado se envía en cuanto está listo (no
+	// necesariamente en orden; usar id o index para asociarlos)
+	ProcessBatch(grpc.BidiStreamingServer[BatchItem, BatchItemResult]) error
+	mustEmbedUnimplementedMatrixServiceServer()
+}
+
+// UnimplementedMatrixServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMatrixServiceServer struct{}
+
+func (UnimplementedMatrixServiceServer) Process(context.Context, *ProcessRequest) (*ProcessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Process not implemented")
+}
+func (UnimplementedMatrixServiceServer) Decompose(context.Context, *DecomposeRequest) (*DecomposeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decompose not implemented")
+}
+func (UnimplementedMatrixServiceServer) Solve(context.Context, *SolveRequest) (*SolveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Solve not implemented")
+}
+func (UnimplementedMatrixServiceServer) ProcessBatch(grpc.BidiStreamingServer[BatchItem, BatchItemResult]) error {
+	return status.Errorf(codes.Unimplemented, "method ProcessBatch not implemented")
+}
+func (UnimplementedMatrixServiceServer) mustEmbedUnimplementedMatrixServiceServer() {}
+func (UnimplementedMatrixServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeMatrixServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MatrixServiceServer will
+// result in compilation errors.
+type UnsafeMatrixServiceServer interface {
+	mustEmbedUnimplementedMatrixServiceServer()
+}
+
+func RegisterMatrixServiceServer(s grpc.ServiceRegistrar, srv MatrixServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMatrixServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MatrixService_ServiceDesc, srv)
+}
+
+func _MatrixService_Process_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatrixServiceServer).Process(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatrixService_Process_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatrixServiceServer).Process(ctx, req.(*ProcessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatrixService_Decompose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecomposeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatrixServiceServer).Decompose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatrixService_Decompose_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatrixServiceServer).Decompose(ctx, req.(*DecomposeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatrixService_Solve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatrixServiceServer).Solve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatrixService_Solve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatrixServiceServer).Solve(ctx, req.(*SolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatrixService_ProcessBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MatrixServiceServer).ProcessBatch(&grpc.GenericServerStream[BatchItem, BatchItemResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatrixService_ProcessBatchServer = grpc.BidiStreamingServer[BatchItem, BatchItemResult]
+
+// MatrixService_ServiceDesc is the grpc.ServiceDesc for MatrixService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MatrixService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "matrix.v1.MatrixService",
+	HandlerType: (*MatrixServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Process",
+			Handler:    _MatrixService_Process_Handler,
+		},
+		{
+			MethodName: "Decompose",
+			Handler:    _MatrixService_Decompose_Handler,
+		},
+		{
+			MethodName: "Solve",
+			Handler:    _MatrixService_Solve_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ProcessBatch",
+			Handler:       _MatrixService_ProcessBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "matrix/v1/matrix.proto",
+}
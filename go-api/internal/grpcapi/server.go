@@ -0,0 +1,305 @@
+// Package grpcapi expone las operaciones con matrices por gRPC (proto/matrix/v1/matrix.proto)
+// reutilizando los servicios y la validación de JWT de la API REST.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/codec"
+	"go-api/internal/grpcapi/matrixpb"
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"google.golang.org/grpc"
+)
+
+// Server implementa matrixpb.MatrixServiceServer
+type Server struct {
+	matrixpb.UnimplementedMatrixServiceServer
+	Processor *services.MatrixProcessor
+	Batch     *services.BatchProcessor
+	// Limits límites de cada stream de ProcessBatch (como los lotes NDJSON de la API REST)
+	Limits services.BatchLimits
+	// MaxElements elementos (rows·cols) máximos de cada matriz de los mensajes, como
+	// codec.MaxElements en los formatos binarios de la API REST
+	MaxElements int
+}
+
+// NewServer crea el servicio gRPC sobre los procesadores de la API REST
+func NewServer(processor *services.MatrixProcessor, batch *services.BatchProcessor, limits services.BatchLimits) *Server {
+	return &Server{Processor: processor, Batch: batch, Limits: limits, MaxElements: codec.MaxElements}
+}
+
+// NewGRPCServer crea un *grpc.Server con los interceptores de métricas y autenticación
+// (todas las llamadas requieren JWT) y registra srv
+func NewGRPCServer(srv *Server, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryMetrics, UnaryAuth),
+		grpc.ChainStreamInterceptor(StreamMetrics, StreamAuth),
+	}, opts...)
+	server := grpc.NewServer(opts...)
+	matrixpb.RegisterMatrixServiceServer(server, srv)
+	return server
+}
+
+// Process rota la matriz, calcula QR y obtiene estadísticas de Node.js
+func (s *Server) Process(ctx context.Context, req *matrixpb.ProcessRequest) (*matrixpb.ProcessResponse, error) {
+	matrix, err := s.fromProto(req.GetMatrix())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	response, err := s.Processor.Process(ctx, matrix, services.ProcessOptions{
+		Token:    bearerToken(ctx),
+		Language: language(ctx),
+	})
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return processResponse(response), nil
+}
+
+// Decompose calcula solo la factorización QR
+func (s *Server) Decompose(ctx context.Context, req *matrixpb.DecomposeRequest) (*matrixpb.DecomposeResponse, error) {
+	matrix, err := s.fromProto(req.GetMatrix())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	if err := services.ValidateMatrix(matrix); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	if err := services.ValidateQRShape(len(matrix), len(matrix[0])); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	q, r, err := services.QRDecomposition(matrix)
+	if err != nil {
+		return nil, statusError(ctx, apperrors.Wrap(apperrors.CodeQRFailed, err))
+	}
+	return &matrixpb.DecomposeResponse{Q: toProto(q), R: toProto(r)}, nil
+}
+
+// Solve resuelve A·x = b por mínimos cuadrados
+func (s *Server) Solve(ctx context.Context, req *matrixpb.SolveRequest) (*matrixpb.SolveResponse, error) {
+	matrix, err := s.fromProto(req.GetA())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	for i, value := range req.GetB() {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, statusError(ctx, apperrors.New(apperrors.CodeSolveNonFiniteValue, map[string]interface{}{"position": i + 1}))
+		}
+	}
+	solution, err := services.SolveLeastSquares(matrix, req.GetB())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return &matrixpb.SolveResponse{X: solution.X, Residual: solution.Residual}, nil
+}
+
+// ProcessBatch procesa los elementos a medida que llegan con el mismo pool que
+// POST /v1/matrix/batch y envía cada resultado en cuanto está listo. Un elemento
+// inválido o con id repetido recibe su problema y el stream sigue; superar un límite
+// envía un último problema y deja de leer (los elementos ya recibidos se terminan).
+func (s *Server) ProcessBatch(stream matrixpb.MatrixService_ProcessBatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	lang := language(ctx)
+
+	// Send no se puede llamar en paralelo: los problemas del lector y los resultados
+	// de los workers pasan por el mismo mutex
+	var mu sync.Mutex
+	var sendErr error
+	send := func(result *matrixpb.BatchItemResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		if sendErr != nil {
+			return
+		}
+		if sendErr = stream.Send(result); sendErr != nil {
+			cancel()
+		}
+	}
+	failed := func(index int, id string, err error) {
+		send(&matrixpb.BatchItemResult{Id: id, Index: uint32(index), Outcome: &matrixpb.BatchItemResult_Problem{Problem: problem(err, lang)}})
+	}
+
+	// positions posición en el stream de cada elemento enviado a Run, en el orden en que Run los numera
+	var positions []int
+	in := make(chan models.BatchItem)
+	recvErr := make(chan error, 1)
+	go func() {
+		defer close(in)
+		recvErr <- s.receive(ctx, stream, in, &mu, &positions, failed)
+	}()
+
+	s.Batch.Run(ctx, in, services.ProcessOptions{Token: bearerToken(ctx), Language: lang}, func(index int, result models.BatchItemResult) {
+		mu.Lock()
+		position := positions[index]
+		mu.Unlock()
+		out := &matrixpb.BatchItemResult{Id: result.ID, Index: uint32(position)}
+		if result.Problem != nil {
+			out.Outcome = &matrixpb.BatchItemResult_Problem{Problem: problemFromModel(result.Problem)}
+		} else {
+			out.Outcome = &matrixpb.BatchItemResult_Result{Result: processResponse(result.MatrixProcessResponse)}
+		}
+		send(out)
+	})
+
+	if err := <-recvErr; err != nil {
+		return statusError(ctx, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if sendErr != nil {
+		return sendErr
+	}
+	if err := stream.Context().Err(); err != nil {
+		return statusError(ctx, err)
+	}
+	return nil
+}
+
+// receive lee el stream de entrada y envía por in los elementos válidos hasta el fin del
+// stream, un límite superado o la cancelación de ctx. Retorna error solo si falla la lectura.
+func (s *Server) receive(ctx context.Context, stream matrixpb.MatrixService_ProcessBatchServer, in chan<- models.BatchItem, mu *sync.Mutex, positions *[]int, failed func(int, string, error)) error {
+	tracker := s.Limits.Tracker()
+	for position := 0; ; position++ {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			if position == 0 {
+				failed(0, "", apperrors.New(apperrors.CodeBatchEmpty, nil))
+			}
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		item := models.BatchItem{ID: msg.GetId()}
+		if item.ID == "" {
+			item.ID = strconv.Itoa(position)
+		}
+		item.Matrix, err = s.fromProto(msg.GetMatrix())
+		if err != nil {
+			failed(position, item.ID, err)
+			continue
+		}
+		switch msg.GetOperation() {
+		case matrixpb.Operation_OPERATION_UNSPECIFIED, matrixpb.Operation_OPERATION_PROCESS:
+			item.Options.Operation = models.JobOperationProcess
+		case matrixpb.Operation_OPERATION_QR:
+			item.Options.Operation = models.JobOperationQR
+		default:
+			failed(position, item.ID, apperrors.New(apperrors.CodeBatchInvalidOperation, map[string]interface{}{"operation": msg.GetOperation().String()}))
+			continue
+		}
+		if err := tracker.Add(item); err != nil {
+			failed(position, item.ID, err)
+			if errors.Is(err, apperrors.New(apperrors.CodeBatchDuplicateID, nil)) {
+				continue
+			}
+			return nil
+		}
+
+		mu.Lock()
+		*positions = append(*positions, position)
+		mu.Unlock()
+		select {
+		case in <- item:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// fromProto convierte una matriz del mensaje en [][]float64; data debe tener rows·cols elementos
+// finitos, como en los formatos binarios de la API REST (NON_FINITE_VALUE). Las dimensiones se
+// validan antes de reservar memoria: con cols = 0, data vacía coincidiría con cualquier rows.
+func (s *Server) fromProto(m *matrixpb.Matrix) ([][]float64, error) {
+	rows, cols := int(m.GetRows()), int(m.GetCols())
+	switch {
+	case rows == 0:
+		return nil, apperrors.New(apperrors.CodeMatrixEmpty, nil)
+	case cols == 0:
+		return nil, apperrors.New(apperrors.CodeMatrixRowEmpty, nil)
+	case rows > s.MaxElements || cols > s.MaxElements/rows:
+		return nil, apperrors.New(apperrors.CodePayloadTooLarge, nil)
+	}
+	data := m.GetData()
+	if len(data) != rows*cols {
+		return nil, apperrors.New(apperrors.CodeMatrixDataLength, map[string]interface{}{
+			"length": len(data), "expected": rows * cols,
+		})
+	}
+	for k, value := range data {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, apperrors.New(apperrors.CodeNonFiniteValue, map[string]interface{}{
+				"row": k/cols + 1, "column": k%cols + 1,
+			})
+		}
+	}
+	matrix := make([][]float64, rows)
+	for i := range matrix {
+		matrix[i] = data[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return matrix, nil
+}
+
+// toProto convierte una matriz rectangular en el mensaje Matrix
+func toProto(matrix [][]float64) *matrixpb.Matrix {
+	rows, cols := len(matrix), 0
+	if rows > 0 {
+		cols = len(matrix[0])
+	}
+	data := make([]float64, 0, rows*cols)
+	for _, row := range matrix {
+		data = append(data, row...)
+	}
+	return &matrixpb.Matrix{Rows: uint32(rows), Cols: uint32(cols), Data: data}
+}
+
+// processResponse convierte el resultado de MatrixProcessor al mensaje ProcessResponse
+func processResponse(response *models.MatrixProcessResponse) *matrixpb.ProcessResponse {
+	out := &matrixpb.ProcessResponse{
+		Rotated:   toProto(response.Rotated),
+		Q:         toProto(response.Q),
+		R:         toProto(response.R),
+		Error:     response.Error,
+		ErrorCode: response.ErrorCode,
+	}
+	if stats := response.NodeStats; stats != nil {
+		out.NodeStats = &matrixpb.NodeStats{
+			Max:         stats.Max,
+			Min:         stats.Min,
+			Avg:         stats.Avg,
+			Sum:         stats.Sum,
+			AnyDiagonal: stats.AnyDiagonal,
+		}
+	}
+	return out
+}
+
+// problem convierte un error en el mensaje Problem localizado
+func problem(err error, lang string) *matrixpb.Problem {
+	p := apperrors.From(err).Problem(lang)
+	return problemFromModel(&p)
+}
+
+// problemFromModel convierte un problem+json en el mensaje Problem
+func problemFromModel(p *models.Problem) *matrixpb.Problem {
+	return &matrixpb.Problem{
+		Code:    p.Code,
+		Title:   p.Title,
+		Detail:  p.Detail,
+		Status:  int32(p.Status),
+		Details: stringDetails(p.Details),
+	}
+}
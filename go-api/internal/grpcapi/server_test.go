@@ -0,0 +1,373 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api/internal/grpcapi/matrixpb"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testSecret = "test-secret-key"
+
+// newTestClient levanta el servidor gRPC en memoria con un Node.js simulado que responde
+// Sum 10 si recibe el JWT reenviado
+func newTestClient(t *testing.T, limits services.BatchLimits) matrixpb.MatrixServiceClient {
+	t.Helper()
+	t.Setenv("JWT_SECRET", testSecret)
+
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/matrix/stats/batch" {
+			var req models.MatrixStatsBatchRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			results := make([]models.MatrixStatsBatchResult, len(req.Items))
+			for i := range results {
+				results[i].Sum = 10
+			}
+			json.NewEncoder(w).Encode(models.MatrixStatsBatchResponse{Results: results})
+			return
+		}
+		json.NewEncoder(w).Encode(models.MatrixStatsResponse{Sum: 10})
+	}))
+	t.Cleanup(node.Close)
+
+	nodeClient := services.NewNodeClient(node.URL)
+	nodeClient.MaxRetries = 0
+	processor := services.NewMatrixProcessor(nodeClient)
+	server := NewGRPCServer(NewServer(processor, services.NewBatchProcessor(processor, 2), limits))
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return matrixpb.NewMatrixServiceClient(conn)
+}
+
+// authContext contexto con el JWT en la metadata authorization
+func authContext(t *testing.T, pairs ...string) context.Context {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{Username: "admin", ID: 1, Role: "admin"}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("Error al crear token de prueba: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), append([]string{"authorization", "Bearer " + token}, pairs...)...)
+}
+
+// errorReason código de la API que viaja en el ErrorInfo del status
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+// matrix arma el mensaje Matrix desde filas
+func matrix(rows ...[]float64) *matrixpb.Matrix {
+	return toProto(rows)
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	client := newTestClient(t, services.BatchLimits{})
+	req := &matrixpb.DecomposeRequest{Matrix: matrix([]float64{1})}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		code   codes.Code
+		reason string
+	}{
+		{name: "sin token", ctx: context.Background(), code: codes.Unauthenticated, reason: "TOKEN_MISSING"},
+		{name: "sin Bearer", ctx: metadata.AppendToOutgoingContext(context.Background(), "authorization", "abc"), code: codes.Unauthenticated, reason: "TOKEN_MALFORMED"},
+		{name: "token inválido", ctx: metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer abc"), code: codes.PermissionDenied, reason: "TOKEN_INVALID"},
+		{name: "token válido", ctx: authContext(t), code: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Decompose(tt.ctx, req)
+			if status.Code(err) != tt.code || errorReason(err) != tt.reason {
+				t.Errorf("error = %v (reason %q), want %s %s", err, errorReason(err), tt.code, tt.reason)
+			}
+		})
+	}
+
+	// Los streams pasan por el mismo interceptor
+	stream, err := client.ProcessBatch(context.Background())
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ProcessBatch sin token: %v, want Unauthenticated", err)
+	}
+}
+
+func TestUnaryRPCs(t *testing.T) {
+	client := newTestClient(t, services.BatchLimits{})
+	ctx := authContext(t)
+
+	t.Run("Process", func(t *testing.T) {
+		resp, err := client.Process(ctx, &matrixpb.ProcessRequest{Matrix: matrix([]float64{1, 2}, []float64{3, 4})})
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		if got := resp.GetRotated().GetData(); len(got) != 4 || got[0] != 3 || got[1] != 1 {
+			t.Errorf("rotated = %v, want [3 1 4 2]", got)
+		}
+		if resp.GetNodeStats().GetSum() != 10 || resp.GetErrorCode() != "" {
+			t.Errorf("nodeStats = %v, errorCode %q", resp.GetNodeStats(), resp.GetErrorCode())
+		}
+	})
+
+	t.Run("Decompose", func(t *testing.T) {
+		resp, err := client.Decompose(ctx, &matrixpb.DecomposeRequest{Matrix: matrix([]float64{3, 0}, []float64{4, 5})})
+		if err != nil {
+			t.Fatalf("Decompose: %v", err)
+		}
+		if resp.GetQ().GetRows() != 2 || resp.GetR().GetCols() != 2 || len(resp.GetR().GetData()) != 4 {
+			t.Errorf("Q %v, R %v", resp.GetQ(), resp.GetR())
+		}
+	})
+
+	t.Run("Solve", func(t *testing.T) {
+		resp, err := client.Solve(ctx, &matrixpb.SolveRequest{A: matrix([]float64{2, 1}, []float64{1, 3}), B: []float64{3, 5}})
+		if err != nil {
+			t.Fatalf("Solve: %v", err)
+		}
+		if x := resp.GetX(); len(x) != 2 || x[0] < 0.8-1e-12 || x[0] > 0.8+1e-12 {
+			t.Errorf("x = %v, want [0.8 1.4]", x)
+		}
+	})
+}
+
+func TestErrorStatus(t *testing.T) {
+	client := newTestClient(t, services.BatchLimits{})
+
+	tests := []struct {
+		name    string
+		call    func(context.Context) error
+		code    codes.Code
+		reason  string
+		message string
+	}{
+		{
+			name: "data con otra longitud",
+			call: func(ctx context.Context) error {
+				_, err := client.Decompose(ctx, &matrixpb.DecomposeRequest{Matrix: &matrixpb.Matrix{Rows: 2, Cols: 2, Data: []float64{1}}})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "MATRIX_DATA_LENGTH",
+			message: "data tiene 1 elementos, se esperaban rows·cols = 4",
+		},
+		{
+			name: "NaN en Process",
+			call: func(ctx context.Context) error {
+				_, err := client.Process(ctx, &matrixpb.ProcessRequest{Matrix: matrix([]float64{1, 2}, []float64{math.NaN(), 4})})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "NON_FINITE_VALUE",
+			message: "la matriz tiene un NaN o infinito en la fila 2, columna 1",
+		},
+		{
+			name: "infinito en Decompose",
+			call: func(ctx context.Context) error {
+				_, err := client.Decompose(ctx, &matrixpb.DecomposeRequest{Matrix: matrix([]float64{1, math.Inf(1)}, []float64{3, 4})})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "NON_FINITE_VALUE",
+		},
+		{
+			name: "infinito negativo en A de Solve",
+			call: func(ctx context.Context) error {
+				_, err := client.Solve(ctx, &matrixpb.SolveRequest{A: matrix([]float64{math.Inf(-1)}), B: []float64{1}})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "NON_FINITE_VALUE",
+		},
+		{
+			name: "NaN en b de Solve",
+			call: func(ctx context.Context) error {
+				_, err := client.Solve(ctx, &matrixpb.SolveRequest{A: matrix([]float64{2, 1}, []float64{1, 3}), B: []float64{3, math.NaN()}})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "SOLVE_NON_FINITE_VALUE",
+			message: "el vector b tiene un NaN o infinito en la posición 2",
+		},
+		{
+			name: "sin columnas y con filas enormes",
+			call: func(ctx context.Context) error {
+				_, err := client.Decompose(ctx, &matrixpb.DecomposeRequest{Matrix: &matrixpb.Matrix{Rows: math.MaxUint32}})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "MATRIX_ROW_EMPTY",
+		},
+		{
+			name: "más elementos que el máximo",
+			call: func(ctx context.Context) error {
+				_, err := client.Process(ctx, &matrixpb.ProcessRequest{Matrix: &matrixpb.Matrix{Rows: 1 << 16, Cols: 1 << 16}})
+				return err
+			},
+			code: codes.ResourceExhausted, reason: "PAYLOAD_TOO_LARGE",
+		},
+		{
+			name: "matriz más ancha que alta",
+			call: func(ctx context.Context) error {
+				_, err := client.Decompose(ctx, &matrixpb.DecomposeRequest{Matrix: matrix([]float64{1, 2, 3})})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "MATRIX_WIDER_THAN_TALL",
+		},
+		{
+			name: "matriz vacía",
+			call: func(ctx context.Context) error {
+				_, err := client.Process(ctx, &matrixpb.ProcessRequest{})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "MATRIX_EMPTY",
+		},
+		{
+			name: "b con otra longitud en inglés",
+			call: func(ctx context.Context) error {
+				_, err := client.Solve(metadata.AppendToOutgoingContext(ctx, "accept-language", "en"), &matrixpb.SolveRequest{A: matrix([]float64{1}), B: []float64{1, 2}})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "SOLVE_DIMENSION_MISMATCH",
+			message: "vector b has 2 elements and the matrix has 1 rows",
+		},
+		{
+			name: "rango incompleto",
+			call: func(ctx context.Context) error {
+				_, err := client.Solve(ctx, &matrixpb.SolveRequest{A: matrix([]float64{1, 2}, []float64{2, 4}), B: []float64{1, 2}})
+				return err
+			},
+			code: codes.FailedPrecondition, reason: "MATRIX_RANK_DEFICIENT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(authContext(t))
+			if status.Code(err) != tt.code || errorReason(err) != tt.reason {
+				t.Errorf("error = %v (reason %q), want %s %s", err, errorReason(err), tt.code, tt.reason)
+			}
+			if tt.message != "" && status.Convert(err).Message() != tt.message {
+				t.Errorf("mensaje = %q, want %q", status.Convert(err).Message(), tt.message)
+			}
+		})
+	}
+}
+
+func TestProcessBatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits services.BatchLimits
+		items  []*matrixpb.BatchItem
+		// want código de problema por posición en el stream ("" si el elemento se procesó)
+		want map[uint32]string
+	}{
+		{
+			name: "elementos válidos, inválidos y repetidos",
+			items: []*matrixpb.BatchItem{
+				{Id: "a", Matrix: matrix([]float64{1, 2}, []float64{3, 4})},
+				{Matrix: matrix([]float64{5})},
+				{Id: "c", Matrix: matrix([]float64{1, 2}, []float64{3, 4}), Operation: matrixpb.Operation_OPERATION_QR},
+				{Id: "d", Matrix: &matrixpb.Matrix{Rows: 1, Cols: 2}},
+				{Id: "a", Matrix: matrix([]float64{1})},
+				{Id: "f", Matrix: matrix([]float64{1, 2, 3})},
+				{Id: "g", Matrix: matrix([]float64{1}, []float64{math.Inf(1)})},
+			},
+			want: map[uint32]string{0: "", 1: "", 2: "", 3: "MATRIX_DATA_LENGTH", 4: "BATCH_DUPLICATE_ID", 5: "MATRIX_WIDER_THAN_TALL", 6: "NON_FINITE_VALUE"},
+		},
+		{
+			name:   "límite de elementos corta el stream",
+			limits: services.BatchLimits{MaxItems: 2},
+			items: []*matrixpb.BatchItem{
+				{Matrix: matrix([]float64{1})},
+				{Matrix: matrix([]float64{2})},
+				{Matrix: matrix([]float64{3})},
+				{Matrix: matrix([]float64{4})},
+			},
+			want: map[uint32]string{0: "", 1: "", 2: "BATCH_TOO_MANY_ITEMS"},
+		},
+		{
+			name: "stream vacío",
+			want: map[uint32]string{0: "BATCH_EMPTY"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.limits)
+			stream, err := client.ProcessBatch(authContext(t))
+			if err != nil {
+				t.Fatalf("ProcessBatch: %v", err)
+			}
+			// El envío va en paralelo con la lectura, como haría un cliente real
+			go func() {
+				for _, item := range tt.items {
+					if stream.Send(item) != nil {
+						break
+					}
+				}
+				stream.CloseSend()
+			}()
+
+			got := make(map[uint32]string)
+			for {
+				result, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Recv: %v", err)
+				}
+				if _, seen := got[result.GetIndex()]; seen {
+					t.Errorf("index %d repetido", result.GetIndex())
+				}
+				got[result.GetIndex()] = result.GetProblem().GetCode()
+				if result.GetProblem() == nil && result.GetResult().GetQ() == nil {
+					t.Errorf("resultado %d sin Q", result.GetIndex())
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("resultados = %v, want %v", got, tt.want)
+			}
+			for index, code := range tt.want {
+				if gotCode, ok := got[index]; !ok || gotCode != code {
+					t.Errorf("resultado %d = %q (presente %v), want %q", index, gotCode, ok, code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxNDJSONLine tamaño máximo por defecto de una línea NDJSON (igual al límite de cuerpo de Fiber)
+const maxNDJSONLine = fiber.DefaultBodyLimit
+
+// BatchHandler maneja el procesamiento de lotes de matrices
+type BatchHandler struct {
+	Batch  *services.BatchProcessor
+	Limits services.BatchLimits
+	// StreamLimits límites de un lote NDJSON; pueden ser mayores porque la memoria no crece con el lote
+	StreamLimits services.BatchLimits
+	// MaxLineBytes tamaño máximo de una línea en modo NDJSON
+	MaxLineBytes int
+}
+
+// NewBatchHandler crea un nuevo handler de lotes; los lotes NDJSON usan los mismos límites
+func NewBatchHandler(batch *services.BatchProcessor, limits services.BatchLimits) *BatchHandler {
+	return &BatchHandler{
+		Batch:        batch,
+		Limits:       limits,
+		StreamLimits: limits,
+		MaxLineBytes: maxNDJSONLine,
+	}
+}
+
+// ProcessBatch procesa un lote de matrices y retorna un resultado por elemento.
+// Los errores de un elemento van en su resultado y no hacen fallar al lote.
+// Con Content-Type application/x-ndjson el lote se procesa en streaming (ver processNDJSON).
+// POST /v1/matrix/batch
+func (h *BatchHandler) ProcessBatch(c *fiber.Ctx) error {
+	if middleware.IsNDJSON(c) {
+		return h.processNDJSON(c)
+	}
+
+	var req models.BatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return middleware.WriteProblem(c, apperrors.Wrap(apperrors.CodeInvalidBody, err))
+	}
+	if err := h.Limits.Validate(req.Items); err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+
+	results := h.Batch.Process(c.UserContext(), req.Items, services.ProcessOptions{
+		Token:    bearerToken(c),
+		Language: middleware.Language(c),
+	})
+
+	response := models.BatchResponse{Results: results}
+	for _, result := range results {
+		if result.Problem != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// processNDJSON procesa un lote NDJSON: cada línea es un BatchItem y cada línea de la
+// respuesta un BatchItemResult, escrito en cuanto está listo (no en el orden de entrada).
+// Las líneas se leen a medida que los workers se liberan, así la memoria no crece con el lote.
+// Un error de una línea (JSON inválido, id duplicado) va en su resultado; superar un límite
+// o una línea demasiado larga emite un último resultado con el problema y corta el stream.
+func (h *BatchHandler) processNDJSON(c *fiber.Ctx) error {
+	// El stream se escribe después de que el handler retorna: capturar ahora lo que se usa de c
+	ctx := c.UserContext()
+	lang := middleware.Language(c)
+	opts := services.ProcessOptions{Token: bearerToken(c), Language: lang}
+	var body io.Reader = c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	} else {
+		// Si el stream se corta antes del final queda cuerpo sin leer: la conexión no se puede reutilizar
+		c.Context().SetConnectionClose()
+	}
+
+	c.Set(fiber.HeaderContentType, middleware.NDJSONContentType)
+	c.Status(fiber.StatusOK)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var mu sync.Mutex
+		write := func(result models.BatchItemResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			if ctx.Err() != nil {
+				return
+			}
+			line, err := json.Marshal(result)
+			if err != nil {
+				problem := apperrors.From(err).Problem(lang)
+				line, _ = json.Marshal(models.BatchItemResult{ID: result.ID, Problem: &problem})
+			}
+			// Un error al escribir significa que el cliente se desconectó: cancelar el resto
+			if _, err := w.Write(append(line, '\n')); err != nil {
+				cancel()
+				return
+			}
+			if err := w.Flush(); err != nil {
+				cancel()
+			}
+		}
+
+		in := make(chan models.BatchItem)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer close(in)
+			h.readNDJSON(ctx, body, in, write, lang)
+		}()
+		h.Batch.Run(ctx, in, opts, func(_ int, result models.BatchItemResult) {
+			write(result)
+		})
+		// El lector usa el cuerpo de la petición: esperar a que termine antes de liberarla
+		cancel()
+		<-done
+	})
+	return nil
+}
+
+// readNDJSON lee las líneas del cuerpo y envía por in los elementos válidos.
+// Los elementos sin id reciben su posición (líneas no vacías) como id.
+func (h *BatchHandler) readNDJSON(ctx context.Context, body io.Reader, in chan<- models.BatchItem, write func(models.BatchItemResult), lang string) {
+	failed := func(id string, err error) {
+		problem := apperrors.From(err).Problem(lang)
+		write(models.BatchItemResult{ID: id, Problem: &problem})
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), h.MaxLineBytes)
+	tracker := h.StreamLimits.Tracker()
+	position := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		id := strconv.Itoa(position)
+		position++
+
+		var item models.BatchItem
+		if err := json.Unmarshal(line, &item); err != nil {
+			failed(id, apperrors.Wrap(apperrors.CodeInvalidBody, err))
+			continue
+		}
+		if item.ID == "" {
+			item.ID = id
+		}
+		if err := tracker.Add(item); err != nil {
+			failed(item.ID, err)
+			if errors.Is(err, apperrors.New(apperrors.CodeBatchDuplicateID, nil)) {
+				continue
+			}
+			return
+		}
+
+		select {
+		case in <- item:
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	switch err := scanner.Err(); {
+	case errors.Is(err, bufio.ErrTooLong):
+		failed(strconv.Itoa(position), apperrors.New(apperrors.CodePayloadTooLarge, nil))
+	case err != nil:
+		failed(strconv.Itoa(position), apperrors.Wrap(apperrors.CodeInvalidBody, err))
+	case position == 0:
+		failed("", apperrors.New(apperrors.CodeBatchEmpty, nil))
+	}
+}
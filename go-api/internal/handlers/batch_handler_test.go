@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+)
+
+func TestProcessBatch(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	token := createTestToken(t, "test-secret-key")
+
+	// Node.js no disponible: los elementos válidos se responden parciales (sin nodeStats)
+	nodeClient := services.NewNodeClient("http://localhost:9999")
+	nodeClient.MaxRetries = 0
+	handler := NewBatchHandler(services.NewBatchProcessor(services.NewMatrixProcessor(nodeClient), 2), services.BatchLimits{
+		MaxItems:    3,
+		MaxElements: 10,
+	})
+	app := fiber.New()
+	app.Post("/v1/matrix/batch", middleware.AuthenticateToken, handler.ProcessBatch)
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		expectedStatus int
+		checkResponse  func(*testing.T, map[string]interface{})
+	}{
+		{
+			name: "lote con un elemento inválido no falla completo",
+			body: models.BatchRequest{Items: []models.BatchItem{
+				{ID: "ok", Matrix: [][]float64{{1, 2}, {3, 4}}, Options: models.BatchItemOptions{Operation: "qr"}},
+				{ID: "mal", Matrix: [][]float64{{1, 2}, {3}}},
+			}},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				if result["succeeded"] != float64(1) || result["failed"] != float64(1) {
+					t.Errorf("succeeded = %v, failed = %v, want 1 y 1", result["succeeded"], result["failed"])
+				}
+				results, _ := result["results"].([]interface{})
+				if len(results) != 2 {
+					t.Fatalf("results = %v, want 2 elementos", results)
+				}
+				first, _ := results[0].(map[string]interface{})
+				if first["id"] != "ok" || first["q"] == nil {
+					t.Errorf("results[0] = %v, want id ok con q", first)
+				}
+				second, _ := results[1].(map[string]interface{})
+				problem, _ := second["problem"].(map[string]interface{})
+				if second["id"] != "mal" || problem["code"] != "MATRIX_NOT_RECTANGULAR" {
+					t.Errorf("results[1] = %v, want id mal con problem MATRIX_NOT_RECTANGULAR", second)
+				}
+			},
+		},
+		{
+			name: "supera el máximo de elementos de matriz",
+			body: models.BatchRequest{Items: []models.BatchItem{
+				{ID: "a", Matrix: [][]float64{{1, 2, 3}, {4, 5, 6}}},
+				{ID: "b", Matrix: [][]float64{{1, 2, 3}, {4, 5, 6}}},
+			}},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				if result["code"] != "BATCH_TOO_MANY_ELEMENTS" {
+					t.Errorf("code = %v, want BATCH_TOO_MANY_ELEMENTS", result["code"])
+				}
+			},
+		},
+		{
+			name:           "lote vacío",
+			body:           models.BatchRequest{},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				if result["code"] != "BATCH_EMPTY" {
+					t.Errorf("code = %v, want BATCH_EMPTY", result["code"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodyJSON, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest("POST", "/v1/matrix/batch", bytes.NewBuffer(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			var result map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&result)
+			tt.checkResponse(t, result)
+		})
+	}
+}
+
+func TestProcessBatch_NDJSON(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	token := createTestToken(t, "test-secret-key")
+
+	nodeClient := services.NewNodeClient("http://localhost:9999")
+	nodeClient.MaxRetries = 0
+	handler := NewBatchHandler(services.NewBatchProcessor(services.NewMatrixProcessor(nodeClient), 2), services.BatchLimits{
+		MaxItems:    4,
+		MaxElements: 100,
+	})
+	// BodyLimit bajo para que el cuerpo llegue como stream, igual que un lote grande en producción
+	app := fiber.New(fiber.Config{StreamRequestBody: true, BodyLimit: 64})
+	app.Post("/v1/matrix/batch", middleware.AuthenticateToken, handler.ProcessBatch)
+
+	tests := []struct {
+		name  string
+		lines []string
+		want  map[string][]string // id -> códigos de problem por línea de respuesta ("" si es exitosa)
+	}{
+		{
+			name: "cada línea responde con su id",
+			lines: []string{
+				`{"id":"ok","matrix":[[1,2],[3,4]],"options":{"operation":"qr"}}`,
+				``,
+				`{"matrix":[[5]],"options":{"operation":"qr"}}`,
+				`{"id":"mal","matrix":[[1,2],[3]]}`,
+				`no es json`,
+				`{"id":"ok","matrix":[[1]]}`,
+			},
+			want: map[string][]string{
+				"ok":  {"", "BATCH_DUPLICATE_ID"},
+				"1":   {""},
+				"mal": {"MATRIX_NOT_RECTANGULAR"},
+				"3":   {"INVALID_BODY"},
+			},
+		},
+		{
+			name: "superar el máximo de elementos corta el stream",
+			lines: []string{
+				`{"id":"a","matrix":[[1]],"options":{"operation":"qr"}}`,
+				`{"id":"b","matrix":[[1]],"options":{"operation":"qr"}}`,
+				`{"id":"c","matrix":[[1]],"options":{"operation":"qr"}}`,
+				`{"id":"d","matrix":[[1]],"options":{"operation":"qr"}}`,
+				`{"id":"e","matrix":[[1]],"options":{"operation":"qr"}}`,
+				`{"id":"f","matrix":[[1]],"options":{"operation":"qr"}}`,
+			},
+			want: map[string][]string{"a": {""}, "b": {""}, "c": {""}, "d": {""}, "e": {"BATCH_TOO_MANY_ITEMS"}},
+		},
+		{
+			name:  "stream vacío",
+			lines: nil,
+			want:  map[string][]string{"": {"BATCH_EMPTY"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/matrix/batch", strings.NewReader(strings.Join(tt.lines, "\n")))
+			req.Header.Set("Content-Type", "application/x-ndjson")
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Status code = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+				t.Errorf("Content-Type = %q, want application/x-ndjson", ct)
+			}
+
+			got := map[string][]string{}
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				var result struct {
+					ID      string          `json:"id"`
+					Q       [][]float64     `json:"q"`
+					Problem *models.Problem `json:"problem"`
+				}
+				if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+					t.Fatalf("línea inválida %q: %v", scanner.Text(), err)
+				}
+				switch {
+				case result.Problem != nil:
+					got[result.ID] = append(got[result.ID], result.Problem.Code)
+				case result.Q == nil:
+					t.Errorf("resultado %q sin q ni problem", result.ID)
+				default:
+					got[result.ID] = append(got[result.ID], "")
+				}
+			}
+
+			// Los resultados llegan en el orden en que terminan: comparar sin importar el orden
+			for _, codes := range got {
+				sort.Strings(codes)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resultados = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
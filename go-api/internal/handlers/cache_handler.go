@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"go-api/internal/apperrors"
+	"go-api/internal/cache"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxCacheItems cantidad máxima de entradas que lista GET /v1/admin/cache
+const maxCacheItems = 1000
+
+// CacheHandler administración del cache de resultados (requiere rol admin)
+type CacheHandler struct {
+	Cache *cache.Cache
+}
+
+// NewCacheHandler crea un nuevo handler de administración del cache
+func NewCacheHandler(c *cache.Cache) *CacheHandler {
+	return &CacheHandler{
+		Cache: c,
+	}
+}
+
+// GetCache retorna los contadores del cache y las entradas usadas más recientemente
+// GET /v1/admin/cache?limit=100
+func (h *CacheHandler) GetCache(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 100)
+	if limit < 0 {
+		limit = 0
+	}
+	if limit > maxCacheItems {
+		limit = maxCacheItems
+	}
+	return c.JSON(h.Cache.Stats(limit))
+}
+
+// PurgeCache vacía el cache (memoria y backend persistente)
+// DELETE /v1/admin/cache
+func (h *CacheHandler) PurgeCache(c *fiber.Ctx) error {
+	purged, err := h.Cache.Purge(c.UserContext())
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	return c.JSON(models.CachePurgeResponse{Purged: purged})
+}
+
+// DeleteCacheEntry elimina un resultado del cache
+// DELETE /v1/admin/cache/:key
+func (h *CacheHandler) DeleteCacheEntry(c *fiber.Ctx) error {
+	found, err := h.Cache.Delete(c.UserContext(), c.Params("key"))
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	if !found {
+		return middleware.WriteProblem(c, apperrors.New(apperrors.CodeNotFound, nil))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go-api/internal/cache"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+)
+
+// newCacheTestApp crea una app con /v1/matrix/process cacheado y las rutas de administración.
+// Node.js responde estadísticas salvo que la matriz contenga 0; calls cuenta sus llamadas.
+func newCacheTestApp(t *testing.T, calls *int32) *fiber.App {
+	t.Helper()
+	resultCache := cache.New(cache.Config{MaxEntries: 10, TTL: time.Hour}, nil)
+	matrixHandler := NewMatrixHandler(services.NewMatrixProcessor(newNodeStub(t, calls)))
+	matrixHandler.Cache = resultCache
+	cacheHandler := NewCacheHandler(resultCache)
+
+	app := fiber.New()
+	app.Post("/v1/matrix/process", middleware.AuthenticateToken, matrixHandler.ProcessMatrix)
+	admin := []fiber.Handler{middleware.AuthenticateToken, middleware.RequireRole("admin")}
+	app.Get("/v1/admin/cache", append(admin, cacheHandler.GetCache)...)
+	app.Delete("/v1/admin/cache", append(admin, cacheHandler.PurgeCache)...)
+	app.Delete("/v1/admin/cache/:key", append(admin, cacheHandler.DeleteCacheEntry)...)
+	return app
+}
+
+// newNodeStub cliente de un servicio Node.js falso que cuenta las llamadas en calls y
+// responde 400 si la matriz rotada tiene ceros
+func newNodeStub(t testing.TB, calls *int32) *services.NodeClient {
+	t.Helper()
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		var req models.MatrixStatsRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		for _, row := range req.Rotated {
+			for _, value := range row {
+				if value == 0 {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(models.MatrixStatsResponse{Sum: 10})
+	}))
+	t.Cleanup(node.Close)
+
+	nodeClient := services.NewNodeClient(node.URL)
+	nodeClient.MaxRetries = 0
+	return nodeClient
+}
+
+// postMatrix envía una matriz a /v1/matrix/process con los headers extra dados
+func postMatrix(t *testing.T, app *fiber.App, token string, matrix [][]float64, headers map[string]string) *http.Response {
+	t.Helper()
+	data, _ := json.Marshal(models.MatrixRequest{Matrix: matrix})
+	req := httptest.NewRequest(http.MethodPost, "/v1/matrix/process", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Error al hacer request: %v", err)
+	}
+	return resp
+}
+
+func TestProcessMatrix_Cache(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	var calls int32
+	app := newCacheTestApp(t, &calls)
+	token := createTestToken(t, "test-secret-key")
+	matrix := [][]float64{{1, 2}, {3, 4}}
+
+	first := postMatrix(t, app, token, matrix, nil)
+	etag := first.Header.Get("ETag")
+	if first.StatusCode != http.StatusOK || first.Header.Get(HeaderCache) != "MISS" || etag == "" {
+		t.Fatalf("primera petición: status %d, X-Cache %q, ETag %q; want 200, MISS y un ETag", first.StatusCode, first.Header.Get(HeaderCache), etag)
+	}
+
+	tests := []struct {
+		name           string
+		matrix         [][]float64
+		headers        map[string]string
+		expectedStatus int
+		expectedCache  string
+		expectedCalls  int32
+	}{
+		{name: "misma matriz", matrix: matrix, expectedStatus: http.StatusOK, expectedCache: "HIT", expectedCalls: 1},
+		{name: "If-None-Match coincide en un POST", matrix: matrix, headers: map[string]string{"If-None-Match": `"otro", ` + etag}, expectedStatus: http.StatusPreconditionFailed, expectedCache: "HIT", expectedCalls: 1},
+		{name: "If-None-Match débil", matrix: matrix, headers: map[string]string{"If-None-Match": "W/" + etag}, expectedStatus: http.StatusPreconditionFailed, expectedCache: "HIT", expectedCalls: 1},
+		{name: "If-None-Match distinto", matrix: matrix, headers: map[string]string{"If-None-Match": `"otro"`}, expectedStatus: http.StatusOK, expectedCache: "HIT", expectedCalls: 1},
+		{name: "respuesta parcial no se guarda", matrix: [][]float64{{0}, {1}}, expectedStatus: http.StatusOK, expectedCache: "MISS", expectedCalls: 2},
+		{name: "respuesta parcial repetida", matrix: [][]float64{{0}, {1}}, expectedStatus: http.StatusOK, expectedCache: "MISS", expectedCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postMatrix(t, app, token, tt.matrix, tt.headers)
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if got := resp.Header.Get(HeaderCache); got != tt.expectedCache {
+				t.Errorf("X-Cache = %q, want %q", got, tt.expectedCache)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.expectedCalls {
+				t.Errorf("llamadas a Node.js = %d, want %d", got, tt.expectedCalls)
+			}
+		})
+	}
+
+	var result models.MatrixProcessResponse
+	json.NewDecoder(postMatrix(t, app, token, matrix, nil).Body).Decode(&result)
+	if result.NodeStats == nil || result.NodeStats.Sum != 10 {
+		t.Errorf("resultado cacheado = %+v, want nodeStats.sum 10", result)
+	}
+}
+
+func TestCacheHandler_Admin(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	var calls int32
+	app := newCacheTestApp(t, &calls)
+	admin := createTestToken(t, "test-secret-key")
+	user := createUserToken(t, "test-secret-key", 2)
+	postMatrix(t, app, admin, [][]float64{{1, 2}, {3, 4}}, nil)
+	postMatrix(t, app, admin, [][]float64{{5}}, nil)
+	key := cache.Key(models.JobOperationProcess, [][]float64{{5}})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		expectedStatus int
+		check          func(*testing.T, map[string]interface{})
+	}{
+		{name: "sin rol admin", method: http.MethodGet, path: "/v1/admin/cache", token: user, expectedStatus: http.StatusForbidden},
+		{
+			name: "estadísticas", method: http.MethodGet, path: "/v1/admin/cache?limit=1", token: admin, expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				items, _ := body["items"].([]interface{})
+				if body["entries"] != float64(2) || len(items) != 1 {
+					t.Errorf("entries = %v, items = %v; want 2 entradas y 1 listada", body["entries"], items)
+				}
+			},
+		},
+		{name: "eliminar una clave", method: http.MethodDelete, path: "/v1/admin/cache/" + key, token: admin, expectedStatus: http.StatusNoContent},
+		{name: "eliminar una clave inexistente", method: http.MethodDelete, path: "/v1/admin/cache/" + key, token: admin, expectedStatus: http.StatusNotFound},
+		{
+			name: "vaciar", method: http.MethodDelete, path: "/v1/admin/cache", token: admin, expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if body["purged"] != float64(1) {
+					t.Errorf("purged = %v, want 1", body["purged"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := doJobRequest(t, app, tt.method, tt.path, tt.token, nil)
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Status code = %d, want %d (body %v)", resp.StatusCode, tt.expectedStatus, body)
+			}
+			if tt.check != nil {
+				tt.check(t, body)
+			}
+		})
+	}
+}
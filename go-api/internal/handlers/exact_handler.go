@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"go-api/internal/apperrors"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExactHandler maneja el modo exacto: Gram-Schmidt y mínimos cuadrados con fracciones
+// (math/big), sin el ruido de punto flotante
+type ExactHandler struct {
+	// MaxDimension filas o columnas máximas de la matriz
+	MaxDimension int
+}
+
+// NewExactHandler crea un nuevo handler del modo exacto
+func NewExactHandler() *ExactHandler {
+	return &ExactHandler{
+		MaxDimension: services.DefaultExactMaxDimension,
+	}
+}
+
+// Compute calcula la factorización exacta y, si el pedido trae b, la solución de A·x = b
+// POST /v1/matrix/exact
+func (h *ExactHandler) Compute(c *fiber.Ctx) error {
+	var req models.ExactMatrixRequest
+	if err := c.BodyParser(&req); err != nil {
+		return middleware.WriteProblem(c, apperrors.Wrap(apperrors.CodeInvalidBody, err))
+	}
+
+	response, err := services.ProcessExact(&req, h.MaxDimension)
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	return c.JSON(response)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go-api/internal/middleware"
+)
+
+func TestExactCompute(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	token := createTestToken(t, "test-secret-key")
+
+	handler := NewExactHandler()
+	handler.MaxDimension = 3
+	app := fiber.New()
+	app.Post("/v1/matrix/exact", middleware.AuthenticateToken, handler.Compute)
+
+	tests := []struct {
+		name           string
+		body           string
+		authToken      string
+		expectedStatus int
+		expectedCode   string
+		checkResponse  func(*testing.T, map[string]interface{})
+	}{
+		{
+			name:           "fracciones como strings y números con b",
+			body:           `{"matrix": [["1/3", 1], [2, "-1/2"]], "b": [1, "1/2"]}`,
+			authToken:      token,
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				solution, _ := result["solution"].(map[string]interface{})
+				if !reflect.DeepEqual(solution["x"], []interface{}{"6/13", "11/13"}) || solution["residual2"] != "0" {
+					t.Errorf("solution = %v, want x = [6/13 11/13] exacta", solution)
+				}
+				if result["rank"] != float64(2) || result["q"] == nil || result["basis"] == nil {
+					t.Errorf("resultado incompleto: %v", result)
+				}
+			},
+		},
+		{
+			name:           "sin b no hay solución",
+			body:           `{"matrix": [["1"]]}`,
+			authToken:      token,
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				if _, ok := result["solution"]; ok || !reflect.DeepEqual(result["r"], []interface{}{[]interface{}{"1"}}) {
+					t.Errorf("resultado = %v, want r = [[1]] sin solution", result)
+				}
+			},
+		},
+		{
+			name:           "número inválido",
+			body:           `{"matrix": [["1/3", "x"]]}`,
+			authToken:      token,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "EXACT_INVALID_NUMBER",
+		},
+		{
+			name:           "supera MaxDimension",
+			body:           `{"matrix": [[1], [2], [3], [4]]}`,
+			authToken:      token,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedCode:   "EXACT_TOO_LARGE",
+		},
+		{
+			name:           "elemento que no es número ni string",
+			body:           `{"matrix": [[true]]}`,
+			authToken:      token,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_BODY",
+		},
+		{
+			name:           "sin token",
+			body:           `{"matrix": [[1]]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/matrix/exact", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.authToken != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.authToken)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			var result map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&result)
+			if tt.expectedCode != "" && result["code"] != tt.expectedCode {
+				t.Errorf("code = %v, want %s", result["code"], tt.expectedCode)
+			}
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"go-api/internal/apperrors"
+	"go-api/internal/graphqlapi"
+	"go-api/internal/middleware"
+	"go-api/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+)
+
+// GraphQLHandler sirve el esquema GraphQL de procesamiento selectivo de matrices
+type GraphQLHandler struct {
+	Schema graphql.Schema
+}
+
+// NewGraphQLHandler crea el handler con el esquema sobre processor
+func NewGraphQLHandler(processor *services.MatrixProcessor) (*GraphQLHandler, error) {
+	schema, err := graphqlapi.NewSchema(processor)
+	if err != nil {
+		return nil, err
+	}
+	return &GraphQLHandler{Schema: schema}, nil
+}
+
+// Query ejecuta una consulta GraphQL con el JWT ya validado por AuthenticateToken.
+// Los errores de la consulta van en errors con status 200, como indica GraphQL sobre HTTP;
+// solo un cuerpo inválido responde problem+json.
+// POST /v1/graphql
+func (h *GraphQLHandler) Query(c *fiber.Ctx) error {
+	var req graphqlapi.Request
+	if err := c.BodyParser(&req); err != nil {
+		return middleware.WriteProblem(c, apperrors.Wrap(apperrors.CodeInvalidBody, err))
+	}
+	if req.Query == "" {
+		return middleware.WriteProblem(c, apperrors.New(apperrors.CodeGraphQLQueryRequired, nil))
+	}
+
+	result := graphqlapi.Execute(c.UserContext(), h.Schema, req, services.ProcessOptions{
+		Token:    bearerToken(c),
+		Language: middleware.Language(c),
+	})
+	return c.JSON(result)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+)
+
+func TestGraphQLQuery(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	token := createTestToken(t, "test-secret-key")
+
+	// Node.js simulado que cuenta las llamadas: sin nodeStats en la consulta no se llama
+	var calls int32
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		json.NewEncoder(w).Encode(models.MatrixStatsResponse{Max: 4})
+	}))
+	defer node.Close()
+
+	handler, err := NewGraphQLHandler(services.NewMatrixProcessor(services.NewNodeClient(node.URL)))
+	if err != nil {
+		t.Fatalf("NewGraphQLHandler: %v", err)
+	}
+	app := fiber.New()
+	app.Post("/v1/graphql", middleware.AuthenticateToken, handler.Query)
+
+	tests := []struct {
+		name           string
+		body           string
+		authToken      string
+		lang           string
+		expectedStatus int
+		wantCalls      int32
+		checkResponse  func(*testing.T, map[string]interface{})
+	}{
+		{
+			name:           "solo R y nodeStats.max",
+			body:           `{"query": "{ process(matrix: [[1, 2], [3, 4]]) { r nodeStats { max } } }"}`,
+			authToken:      token,
+			expectedStatus: http.StatusOK,
+			wantCalls:      1,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				process := graphqlField(result, "process")
+				if len(process) != 2 || process["r"] == nil {
+					t.Errorf("process = %v, want solo r y nodeStats", process)
+				}
+				stats, _ := process["nodeStats"].(map[string]interface{})
+				if stats["max"] != float64(4) {
+					t.Errorf("nodeStats = %v, want max 4", stats)
+				}
+			},
+		},
+		{
+			name:           "Q reducida con variables y fragmento sin llamar a Node.js",
+			body:           `{"query": "query P($m: [[Float!]!]!) { process(matrix: $m, thin: true) { ...qr } } fragment qr on MatrixResult { q r }", "variables": {"m": [[1, 2], [3, 4], [5, 6]]}}`,
+			authToken:      token,
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				process := graphqlField(result, "process")
+				q, _ := process["q"].([]interface{})
+				r, _ := process["r"].([]interface{})
+				firstRow, _ := q[0].([]interface{})
+				if len(q) != 3 || len(firstRow) != 2 || len(r) != 2 {
+					t.Errorf("q = %v, r = %v, want Q 3x2 y R 2x2", q, r)
+				}
+			},
+		},
+		{
+			name:           "matriz inválida en errors con el código en extensions",
+			body:           `{"query": "{ process(matrix: [[1, 2], [3]]) { r } }"}`,
+			authToken:      token,
+			lang:           "en",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				errs, _ := result["errors"].([]interface{})
+				if len(errs) != 1 {
+					t.Fatalf("errors = %v, want 1", result["errors"])
+				}
+				first, _ := errs[0].(map[string]interface{})
+				extensions, _ := first["extensions"].(map[string]interface{})
+				if extensions["code"] != "MATRIX_NOT_RECTANGULAR" || extensions["status"] != float64(400) {
+					t.Errorf("extensions = %v, want MATRIX_NOT_RECTANGULAR 400", extensions)
+				}
+				if first["message"] != "the matrix is not rectangular: row 1 has 1 columns, expected 2" {
+					t.Errorf("message = %v", first["message"])
+				}
+			},
+		},
+		{
+			name:           "campo inexistente",
+			body:           `{"query": "{ process(matrix: [[1]]) { determinant } }"}`,
+			authToken:      token,
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				if errs, _ := result["errors"].([]interface{}); len(errs) == 0 {
+					t.Errorf("errors = %v, want error de validación", result["errors"])
+				}
+			},
+		},
+		{
+			name:           "sin query",
+			body:           `{}`,
+			authToken:      token,
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, result map[string]interface{}) {
+				if result["code"] != "GRAPHQL_QUERY_REQUIRED" {
+					t.Errorf("code = %v, want GRAPHQL_QUERY_REQUIRED", result["code"])
+				}
+			},
+		},
+		{
+			name:           "sin token",
+			body:           `{"query": "{ process(matrix: [[1]]) { r } }"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
+			req := httptest.NewRequest(http.MethodPost, "/v1/graphql", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.authToken != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.authToken)
+			}
+			if tt.lang != "" {
+				req.Header.Set("Accept-Language", tt.lang)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("llamadas a Node.js = %d, want %d", got, tt.wantCalls)
+			}
+			if tt.checkResponse != nil {
+				var result map[string]interface{}
+				json.NewDecoder(resp.Body).Decode(&result)
+				tt.checkResponse(t, result)
+			}
+		})
+	}
+}
+
+// graphqlField campo de primer nivel de data en una respuesta GraphQL
+func graphqlField(result map[string]interface{}, name string) map[string]interface{} {
+	data, _ := result["data"].(map[string]interface{})
+	field, _ := data[name].(map[string]interface{})
+	return field
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthHandler maneja los endpoints de liveness y readiness
+type HealthHandler struct {
+	Checker *services.ReadinessChecker
+}
+
+// NewHealthHandler crea un nuevo handler de health checks
+func NewHealthHandler(readiness *services.ReadinessChecker) *HealthHandler {
+	return &HealthHandler{
+		Checker: readiness,
+	}
+}
+
+// Liveness indica que el proceso está vivo; no verifica dependencias
+// GET /health, GET /livez
+func (h *HealthHandler) Liveness(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"status":  "ok",
+		"service": "go-api",
+	})
+}
+
+// Readiness verifica que el servicio pueda atender tráfico (Node.js, configuración, stores)
+// Retorna 503 si alguna verificación falla
+// GET /readyz
+func (h *HealthHandler) Readiness(c *fiber.Ctx) error {
+	result := h.Checker.Run(c.UserContext())
+
+	status := fiber.StatusOK
+	if result.Status != models.CheckStatusOK {
+		status = fiber.StatusServiceUnavailable
+	}
+	return c.Status(status).JSON(result)
+}
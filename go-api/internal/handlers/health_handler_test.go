@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go-api/internal/models"
+	"go-api/internal/services"
+)
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		checkErr       error
+		expectedStatus int
+	}{
+		{
+			name:           "liveness responde ok aunque falle una dependencia",
+			path:           "/livez",
+			checkErr:       errors.New("caído"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "readiness ok con dependencias disponibles",
+			path:           "/readyz",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "readiness 503 con dependencia caída",
+			path:           "/readyz",
+			checkErr:       errors.New("caído"),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := services.NewReadinessChecker(time.Second)
+			checker.Register("node", func(ctx context.Context) error { return tt.checkErr })
+			handler := NewHealthHandler(checker)
+
+			app := fiber.New()
+			app.Get("/livez", handler.Liveness)
+			app.Get("/readyz", handler.Readiness)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+
+			if tt.path == "/readyz" {
+				var result models.ReadinessResponse
+				json.NewDecoder(resp.Body).Decode(&result)
+				if _, ok := result.Checks["node"]; !ok {
+					t.Error("Expected 'node' check in response")
+				}
+			}
+		})
+	}
+}
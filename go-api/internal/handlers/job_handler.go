@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	"go-api/internal/jobs"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JobHandler maneja los jobs asíncronos de procesamiento de matrices
+type JobHandler struct {
+	Manager *jobs.Manager
+}
+
+// NewJobHandler crea un nuevo handler de jobs
+func NewJobHandler(manager *jobs.Manager) *JobHandler {
+	return &JobHandler{
+		Manager: manager,
+	}
+}
+
+// CreateJob encola una operación sobre una matriz y responde 202 con el job creado.
+// Con un cuerpo CSV o TSV, operation y callbackUrl se leen de la query.
+// POST /v1/jobs
+func (h *JobHandler) CreateJob(c *fiber.Ctx) error {
+	var req models.JobRequest
+	tabular, err := parseMatrixBody(c, &req, &req.Matrix)
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	if tabular {
+		req.Operation = c.Query("operation")
+		req.CallbackURL = c.Query("callbackUrl")
+	}
+
+	job, err := h.Manager.Submit(c.UserContext(), userID(c), req, bearerToken(c), middleware.Language(c))
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+
+	c.Location(c.Path() + "/" + job.ID)
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// GetJob retorna el estado, progreso y resultado de un job del usuario con su ETag:
+// un sondeo con If-None-Match recibe 304 mientras el job no cambie
+// GET /v1/jobs/:id
+func (h *JobHandler) GetJob(c *fiber.Ctx) error {
+	job, err := h.Manager.Get(c.UserContext(), userID(c), c.Params("id"))
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	return sendWithETag(c, data, fiber.MIMEApplicationJSON)
+}
+
+// GetJobDeliveries retorna el registro de entregas del webhook de un job del usuario
+// GET /v1/jobs/:id/deliveries
+func (h *JobHandler) GetJobDeliveries(c *fiber.Ctx) error {
+	job, err := h.Manager.Get(c.UserContext(), userID(c), c.Params("id"))
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	deliveries := job.Deliveries
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	return c.JSON(deliveries)
+}
+
+// CancelJob cancela un job en cola o en ejecución
+// DELETE /v1/jobs/:id
+func (h *JobHandler) CancelJob(c *fiber.Ctx) error {
+	job, err := h.Manager.Cancel(c.UserContext(), userID(c), c.Params("id"))
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	return c.JSON(job)
+}
+
+// userID obtiene el ID del usuario autenticado (guardado por AuthenticateToken)
+func userID(c *fiber.Ctx) int {
+	claims, _ := c.Locals("user").(*middleware.Claims)
+	if claims == nil {
+		return 0
+	}
+	return claims.ID
+}
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go-api/internal/jobs"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+)
+
+// testWebhookSecret secreto con el que se firman los webhooks en los tests
+var testWebhookSecret = []byte("test-webhook-secret")
+
+// newJobTestApp crea una app con las rutas de jobs sobre un manager en memoria
+func newJobTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+	nodeClient := services.NewNodeClient("http://localhost:9999")
+	nodeClient.MaxRetries = 0
+	notifier := jobs.NewNotifier(testWebhookSecret)
+	notifier.Backoff = 10 * time.Millisecond
+	// Los receptores de prueba escuchan en loopback
+	notifier.AllowedNetworks = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+	manager := jobs.NewManager(jobs.NewMemoryStore(), services.NewMatrixProcessor(nodeClient), jobs.Config{
+		Workers: 1, QueueSize: 10, ResultTTL: time.Hour, Notifier: notifier,
+	})
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { manager.Stop(context.Background()) })
+
+	handler := NewJobHandler(manager)
+	app := fiber.New()
+	app.Post("/v1/jobs", middleware.AuthenticateToken, handler.CreateJob)
+	app.Get("/v1/jobs/:id", middleware.AuthenticateToken, handler.GetJob)
+	app.Get("/v1/jobs/:id/deliveries", middleware.AuthenticateToken, handler.GetJobDeliveries)
+	app.Delete("/v1/jobs/:id", middleware.AuthenticateToken, handler.CancelJob)
+	return app
+}
+
+// createUserToken crea un token JWT de prueba para el usuario con el ID dado
+func createUserToken(t *testing.T, secret string, id int) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{Username: "user", ID: id, Role: "user"})
+	tokenString, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("Error al crear token de prueba: %v", err)
+	}
+	return tokenString
+}
+
+// doJobRequest ejecuta una petición autenticada y decodifica la respuesta JSON
+func doJobRequest(t *testing.T, app *fiber.App, method, path, token string, body interface{}) (*http.Response, map[string]interface{}) {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Error al hacer request: %v", err)
+	}
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	return resp, result
+}
+
+func TestJobHandler_Lifecycle(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+
+	app := newJobTestApp(t)
+	owner := createUserToken(t, "test-secret-key", 1)
+	other := createUserToken(t, "test-secret-key", 2)
+
+	// Crear job
+	resp, created := doJobRequest(t, app, "POST", "/v1/jobs", owner, models.JobRequest{
+		Operation: models.JobOperationQR,
+		Matrix:    [][]float64{{1, 2}, {3, 4}},
+	})
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST status = %d, want 202 (%v)", resp.StatusCode, created)
+	}
+	id, _ := created["id"].(string)
+	if location := resp.Header.Get("Location"); location != "/v1/jobs/"+id {
+		t.Errorf("Location = %q, want /v1/jobs/%s", location, id)
+	}
+
+	// Consultar hasta que termine
+	var job map[string]interface{}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		resp, job = doJobRequest(t, app, "GET", "/v1/jobs/"+id, owner, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET status = %d, want 200", resp.StatusCode)
+		}
+		if job["status"] == models.JobStatusSucceeded {
+			break
+		}
+	}
+	if job["status"] != models.JobStatusSucceeded || job["result"] == nil {
+		t.Fatalf("job = %v, want succeeded con resultado", job)
+	}
+
+	// Un sondeo con el ETag del job terminado recibe 304 sin cuerpo
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("GET sin ETag")
+	}
+	req := httptest.NewRequest("GET", "/v1/jobs/"+id, nil)
+	req.Header.Set("Authorization", "Bearer "+owner)
+	req.Header.Set("If-None-Match", etag)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Error al hacer request: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("GET con If-None-Match: status = %d, want 304", resp.StatusCode)
+	}
+
+	// Otro usuario no puede ver ni cancelar el job
+	if resp, body := doJobRequest(t, app, "GET", "/v1/jobs/"+id, other, nil); resp.StatusCode != http.StatusNotFound || body["code"] != "JOB_NOT_FOUND" {
+		t.Errorf("GET de otro usuario: status = %d, code = %v, want 404 JOB_NOT_FOUND", resp.StatusCode, body["code"])
+	}
+	if resp, _ := doJobRequest(t, app, "DELETE", "/v1/jobs/"+id, other, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("DELETE de otro usuario: status = %d, want 404", resp.StatusCode)
+	}
+
+	// Un job terminado no se puede cancelar
+	if resp, body := doJobRequest(t, app, "DELETE", "/v1/jobs/"+id, owner, nil); resp.StatusCode != http.StatusConflict || body["code"] != "JOB_NOT_CANCELABLE" {
+		t.Errorf("DELETE terminado: status = %d, code = %v, want 409 JOB_NOT_CANCELABLE", resp.StatusCode, body["code"])
+	}
+}
+
+func TestJobHandler_CreateErrors(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+
+	app := newJobTestApp(t)
+	token := createUserToken(t, "test-secret-key", 1)
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "operación inválida",
+			body:           map[string]interface{}{"operation": "svd", "matrix": [][]float64{{1}}},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "JOB_INVALID_OPERATION",
+		},
+		{
+			name:           "matriz no rectangular",
+			body:           map[string]interface{}{"matrix": [][]float64{{1, 2}, {3}}},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "MATRIX_NOT_RECTANGULAR",
+		},
+		{
+			name:           "callbackUrl no absoluta",
+			body:           map[string]interface{}{"matrix": [][]float64{{1}}, "callbackUrl": "/hooks/jobs"},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "JOB_INVALID_CALLBACK",
+		},
+		{
+			name:           "callbackUrl con esquema no soportado",
+			body:           map[string]interface{}{"matrix": [][]float64{{1}}, "callbackUrl": "ftp://example.com/hook"},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "JOB_INVALID_CALLBACK",
+		},
+		{
+			name:           "callbackUrl a la metadata del proveedor cloud",
+			body:           map[string]interface{}{"matrix": [][]float64{{1}}, "callbackUrl": "http://169.254.169.254/latest/meta-data/"},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "JOB_INVALID_CALLBACK",
+		},
+		{
+			name:           "cuerpo inválido",
+			body:           "no es un objeto",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_BODY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := doJobRequest(t, app, "POST", "/v1/jobs", token, tt.body)
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if body["code"] != tt.expectedCode {
+				t.Errorf("code = %v, want %s", body["code"], tt.expectedCode)
+			}
+		})
+	}
+}
+
+// webhookCall petición recibida por el receptor de webhooks de prueba
+type webhookCall struct {
+	event, jobID string
+	validSig     bool
+	job          models.Job
+}
+
+func TestJobHandler_WebhookCallback(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+
+	// Receptor local: falla el primer intento con 503 y acepta el segundo
+	calls := make(chan webhookCall, 10)
+	attempts := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		call := webhookCall{
+			event:    r.Header.Get(jobs.EventHeader),
+			jobID:    r.Header.Get(jobs.JobIDHeader),
+			validSig: jobs.VerifySignature(testWebhookSecret, r.Header.Get(jobs.TimestampHeader), body, r.Header.Get(jobs.SignatureHeader)),
+		}
+		json.Unmarshal(body, &call.job)
+		calls <- call
+
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	app := newJobTestApp(t)
+	token := createUserToken(t, "test-secret-key", 1)
+
+	resp, created := doJobRequest(t, app, "POST", "/v1/jobs", token, models.JobRequest{
+		Operation:   models.JobOperationQR,
+		Matrix:      [][]float64{{1, 2}, {3, 4}},
+		CallbackURL: receiver.URL + "/hooks/jobs",
+	})
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST status = %d, want 202 (%v)", resp.StatusCode, created)
+	}
+	id, _ := created["id"].(string)
+
+	for i := 1; i <= 2; i++ {
+		select {
+		case call := <-calls:
+			if !call.validSig {
+				t.Errorf("intento %d: firma HMAC inválida", i)
+			}
+			if call.event != "job.succeeded" || call.jobID != id {
+				t.Errorf("intento %d: event = %q, jobID = %q, want job.succeeded y %s", i, call.event, call.jobID, id)
+			}
+			if call.job.Status != models.JobStatusSucceeded || call.job.Result == nil {
+				t.Errorf("intento %d: payload = %+v, want job terminado con resultado", i, call.job)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("el receptor no recibió el intento %d", i)
+		}
+	}
+
+	// El registro de entregas refleja el fallo y el reintento exitoso
+	var deliveries []models.WebhookDelivery
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline) && len(deliveries) < 2; time.Sleep(10 * time.Millisecond) {
+		req := httptest.NewRequest("GET", "/v1/jobs/"+id+"/deliveries", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Error al hacer request: %v", err)
+		}
+		deliveries = nil
+		json.NewDecoder(resp.Body).Decode(&deliveries)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("deliveries = %+v, want 2 intentos", deliveries)
+	}
+	if deliveries[0].Success || deliveries[0].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("primer intento = %+v, want fallido con 503", deliveries[0])
+	}
+	if !deliveries[1].Success || deliveries[1].StatusCode != http.StatusNoContent || deliveries[1].Attempt != 2 {
+		t.Errorf("segundo intento = %+v, want exitoso con 204", deliveries[1])
+	}
+
+	// Otro usuario no puede ver el registro
+	if resp, _ := doJobRequest(t, app, "GET", "/v1/jobs/"+id+"/deliveries", createUserToken(t, "test-secret-key", 2), nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET deliveries de otro usuario: status = %d, want 404", resp.StatusCode)
+	}
+}
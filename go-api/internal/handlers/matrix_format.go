@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/codec"
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseMatrixBody decodifica el cuerpo de una petición con matriz. Los formatos tabulares
+// (CSV, TSV, Matrix Market, .npy, float64 crudo) solo traen la matriz y se guardan en matrix;
+// el resto (JSON, MessagePack, CBOR) se decodifica en out. tabular indica cuál de los dos
+// caminos se usó.
+func parseMatrixBody(c *fiber.Ctx, out interface{}, matrix *[][]float64) (tabular bool, err error) {
+	mediaType, params, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	var m [][]float64
+	switch mediaType {
+	case codec.MIMECSV, codec.MIMETSV:
+		opts, optsErr := csvOptions(c, mediaType, params["header"])
+		if optsErr != nil {
+			return true, optsErr
+		}
+		m, err = codec.DecodeCSV(bytes.NewReader(c.Body()), opts)
+	case codec.MIMEMatrixMarket:
+		m, err = codec.DecodeMatrixMarket(bytes.NewReader(c.Body()))
+	case codec.MIMENPY:
+		m, err = codec.DecodeNPY(c.Body())
+	case codec.MIMEFloat64:
+		m, err = codec.DecodeFloat64(c.Body())
+	case codec.MIMEMsgPack, codec.MIMEXMsgPack, codec.MIMECBOR:
+		decode := codec.DecodeCBOR
+		if mediaType != codec.MIMECBOR {
+			decode = codec.DecodeMsgPack
+		}
+		if err := decode(c.Body(), out); err != nil {
+			return false, apperrors.Wrap(apperrors.CodeInvalidBody, err)
+		}
+		if err := codec.CheckFinite(*matrix); err != nil {
+			return false, nonFiniteError(err)
+		}
+		return false, nil
+	default:
+		if err := c.BodyParser(out); err != nil {
+			return false, apperrors.Wrap(apperrors.CodeInvalidBody, err)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return true, matrixParseError(err)
+	}
+	*matrix = m
+	return true, nil
+}
+
+// csvOptions lee las opciones de texto delimitado de la query:
+// delimiter (un carácter o "tab"), header (true/false) y decimal (comma/point).
+// El header también se puede indicar con el parámetro del media type (text/csv; header=present).
+// Con decimal=comma el separador por defecto del CSV es ';', como en las planillas en español.
+func csvOptions(c *fiber.Ctx, mediaType, headerParam string) (codec.CSVOptions, error) {
+	opts := codec.CSVOptions{Delimiter: ',', Header: headerParam == "present"}
+	if mediaType == codec.MIMETSV {
+		opts.Delimiter = '\t'
+	}
+
+	switch decimal := c.Query("decimal"); decimal {
+	case "", "point":
+	case "comma":
+		opts.DecimalComma = true
+		if mediaType != codec.MIMETSV {
+			opts.Delimiter = ';'
+		}
+	default:
+		return opts, invalidFormatOption("decimal", decimal, "comma, point")
+	}
+
+	switch delimiter := c.Query("delimiter"); {
+	case delimiter == "":
+	case delimiter == "tab":
+		opts.Delimiter = '\t'
+	case utf8.RuneCountInString(delimiter) == 1 && !strings.ContainsAny(delimiter, "\"#\r\n"):
+		opts.Delimiter, _ = utf8.DecodeRuneInString(delimiter)
+	default:
+		return opts, apperrors.New(apperrors.CodeInvalidDelimiter, map[string]interface{}{"value": delimiter})
+	}
+	if opts.DecimalComma && opts.Delimiter == ',' {
+		return opts, apperrors.New(apperrors.CodeOptionsConflict, map[string]interface{}{
+			"option": "delimiter=,", "other": "decimal=comma",
+		})
+	}
+
+	if header := c.Query("header"); header != "" {
+		switch header {
+		case "true", "present":
+			opts.Header = true
+		case "false", "absent":
+			opts.Header = false
+		default:
+			return opts, invalidFormatOption("header", header, "true, false")
+		}
+	}
+	return opts, nil
+}
+
+// matrixParseError convierte un error de formato en MATRIX_PARSE_ERROR con su posición,
+// el código del motivo (reasonCode) y sus valores
+func matrixParseError(err error) error {
+	var parseErr *codec.ParseError
+	if errors.As(err, &parseErr) {
+		details := map[string]interface{}{
+			"line":       parseErr.Line,
+			"column":     parseErr.Column,
+			"reasonCode": parseErr.Reason,
+		}
+		for key, value := range parseErr.Params {
+			details[key] = value
+		}
+		return apperrors.New(apperrors.CodeMatrixParseError, details)
+	}
+	return apperrors.Wrap(apperrors.CodeInvalidBody, err)
+}
+
+// nonFiniteError convierte un *codec.NonFiniteError en NON_FINITE_VALUE con su posición;
+// cualquier otro error es INVALID_BODY
+func nonFiniteError(err error) error {
+	var nonFinite *codec.NonFiniteError
+	if errors.As(err, &nonFinite) {
+		return apperrors.New(apperrors.CodeNonFiniteValue, map[string]interface{}{
+			"row":    nonFinite.Row,
+			"column": nonFinite.Column,
+		})
+	}
+	return apperrors.Wrap(apperrors.CodeInvalidBody, err)
+}
+
+// invalidFormatOption error INVALID_FORMAT_OPTION: value no es uno de los valores allowed
+// de la query option
+func invalidFormatOption(option, value, allowed string) error {
+	return apperrors.New(apperrors.CodeInvalidFormatOption, map[string]interface{}{
+		"option": option, "value": value, "allowed": allowed,
+	})
+}
+
+// resultFormats media types en los que se puede responder el resultado de procesar una matriz;
+// el primero (JSON) es el que se usa si Accept no incluye ninguno
+var resultFormats = []string{
+	fiber.MIMEApplicationJSON, codec.MIMECSV, codec.MIMETSV, codec.MIMEZip,
+	codec.MIMEMatrixMarket, codec.MIMENPY, codec.MIMENPZ,
+	codec.MIMEMsgPack, codec.MIMEXMsgPack, codec.MIMECBOR, codec.MIMEFloat64,
+}
+
+// encodeResult serializa el resultado en el formato pedido en Accept.
+// MessagePack y CBOR llevan el resultado completo con las mismas claves que el JSON.
+// CSV y TSV llevan rotated, q y r como secciones de un mismo texto; zip y .npz llevan un
+// archivo por matriz; el float64 crudo, las tres matrices una detrás de otra. Matrix Market
+// y .npy guardan una sola matriz, elegida con la query matrix. Los formatos tabulares
+// densifican las matrices que vengan dispersas. Si ya se tiene el JSON (body) se responde
+// sin volver a serializar, y solo se decodifica cuando falta result (cache) y se pide otro formato.
+func encodeResult(c *fiber.Ctx, result *models.MatrixProcessResponse, body []byte) ([]byte, string, error) {
+	format := c.Accepts(resultFormats...)
+	if format == "" || format == fiber.MIMEApplicationJSON {
+		if body == nil {
+			var err error
+			if body, err = json.Marshal(result); err != nil {
+				return nil, "", err
+			}
+		}
+		return body, fiber.MIMEApplicationJSON, nil
+	}
+
+	if result == nil {
+		result = new(models.MatrixProcessResponse)
+		if err := json.Unmarshal(body, result); err != nil {
+			return nil, "", err
+		}
+	}
+	sections := []codec.Section{
+		{Name: "rotated", Matrix: denseSection(result.Rotated, result.RotatedSparse)},
+		{Name: "q", Matrix: denseSection(result.Q, result.QSparse)},
+		{Name: "r", Matrix: denseSection(result.R, result.RSparse)},
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case codec.MIMEMsgPack, codec.MIMEXMsgPack:
+		if err := codec.EncodeMsgPack(&buf, result); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), format, nil
+
+	case codec.MIMECBOR:
+		if err := codec.EncodeCBOR(&buf, result); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), codec.MIMECBOR, nil
+
+	case codec.MIMEFloat64:
+		if err := codec.EncodeFloat64Sections(&buf, sections); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), codec.MIMEFloat64, nil
+
+	case codec.MIMEMatrixMarket, codec.MIMENPY:
+		matrix, err := selectSection(c, sections)
+		if err != nil {
+			return nil, "", err
+		}
+		if format == codec.MIMENPY {
+			err = codec.EncodeNPY(&buf, matrix)
+		} else {
+			coordinate, layoutErr := mtxLayout(c)
+			if layoutErr != nil {
+				return nil, "", layoutErr
+			}
+			err = codec.EncodeMatrixMarket(&buf, matrix, coordinate)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), format, nil
+
+	case codec.MIMENPZ:
+		if err := codec.EncodeZip(&buf, sections, ".npy", codec.EncodeNPY); err != nil {
+			return nil, "", err
+		}
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="result.npz"`)
+		return buf.Bytes(), codec.MIMENPZ, nil
+
+	case codec.MIMEZip:
+		extension, encode, err := zipEntryFormat(c)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := codec.EncodeZip(&buf, sections, extension, encode); err != nil {
+			return nil, "", err
+		}
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="result.zip"`)
+		return buf.Bytes(), codec.MIMEZip, nil
+	}
+
+	opts, err := csvOptions(c, format, "")
+	if err != nil {
+		return nil, "", err
+	}
+	if err := codec.EncodeCSVSections(&buf, sections, opts); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), format + "; charset=utf-8", nil
+}
+
+// denseSection la matriz densa del resultado, o la dispersa densificada si vino en ese formato
+func denseSection(dense [][]float64, sparse *models.SparseMatrix) [][]float64 {
+	if sparse != nil {
+		return services.DenseFromSparse(sparse)
+	}
+	return dense
+}
+
+// selectSection matriz pedida en la query matrix (rotated, q o r) para los formatos de una sola matriz
+func selectSection(c *fiber.Ctx, sections []codec.Section) ([][]float64, error) {
+	name := c.Query("matrix")
+	for _, section := range sections {
+		if section.Name == name {
+			return section.Matrix, nil
+		}
+	}
+	if name == "" {
+		return nil, apperrors.New(apperrors.CodeMatrixSelectionRequired, map[string]interface{}{"allowed": "rotated, q, r"})
+	}
+	return nil, invalidFormatOption("matrix", name, "rotated, q, r")
+}
+
+// mtxLayout lee la query layout de Matrix Market: array (denso, por defecto) o coordinate (disperso)
+func mtxLayout(c *fiber.Ctx) (coordinate bool, err error) {
+	switch layout := c.Query("layout"); layout {
+	case "", "array":
+		return false, nil
+	case "coordinate":
+		return true, nil
+	default:
+		return false, invalidFormatOption("layout", layout, "array, coordinate")
+	}
+}
+
+// zipEntryFormat formato de los archivos dentro del zip según la query format
+// (csv por defecto, tsv, mtx o npy). En CSV, delimiter=tab genera archivos .tsv.
+func zipEntryFormat(c *fiber.Ctx) (string, func(io.Writer, [][]float64) error, error) {
+	switch format := c.Query("format"); format {
+	case "", "csv", "tsv":
+		mediaType := codec.MIMECSV
+		if format == "tsv" {
+			mediaType = codec.MIMETSV
+		}
+		opts, err := csvOptions(c, mediaType, "")
+		if err != nil {
+			return "", nil, err
+		}
+		extension := ".csv"
+		if opts.Delimiter == '\t' {
+			extension = ".tsv"
+		}
+		return extension, func(w io.Writer, matrix [][]float64) error {
+			return codec.EncodeCSV(w, matrix, opts)
+		}, nil
+	case "mtx":
+		coordinate, err := mtxLayout(c)
+		if err != nil {
+			return "", nil, err
+		}
+		return ".mtx", func(w io.Writer, matrix [][]float64) error {
+			return codec.EncodeMatrixMarket(w, matrix, coordinate)
+		}, nil
+	case "npy":
+		return ".npy", codec.EncodeNPY, nil
+	default:
+		return "", nil, invalidFormatOption("format", format, "csv, tsv, mtx, npy")
+	}
+}
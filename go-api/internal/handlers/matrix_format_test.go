@@ -0,0 +1,532 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api/internal/cache"
+	"go-api/internal/codec"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestProcessMatrix_Formats(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	var calls int32
+	app := newCacheTestApp(t, &calls)
+	token := createTestToken(t, "test-secret-key")
+	var npyBody, msgpackBody, nanBody, complexInfBody, float64Body bytes.Buffer
+	codec.EncodeNPY(&npyBody, [][]float64{{1, 2}, {3, 4}})
+	codec.EncodeMsgPack(&msgpackBody, map[string]interface{}{"matrix": [][]float64{{1, 2}, {3, 4}}})
+	codec.EncodeMsgPack(&nanBody, map[string]interface{}{"matrix": [][]float64{{1, math.NaN()}}})
+	codec.EncodeMsgPack(&complexInfBody, map[string]interface{}{"complex": [][]models.Complex{{{Re: 1}, {Re: 2, Im: math.Inf(1)}}}})
+	codec.EncodeFloat64(&float64Body, [][]float64{{1, 2}, {3, 4}})
+
+	tests := []struct {
+		name            string
+		query           string
+		contentType     string
+		accept          string
+		body            string
+		expectedStatus  int
+		expectedType    string
+		check           func(*testing.T, []byte)
+		expectedDetails map[string]interface{}
+	}{
+		{
+			name: "CSV a JSON", contentType: "text/csv", body: "1,2\n3,4\n",
+			expectedStatus: http.StatusOK, expectedType: "application/json",
+			check: func(t *testing.T, body []byte) {
+				var result map[string]interface{}
+				json.Unmarshal(body, &result)
+				if result["rotated"] == nil || result["q"] == nil || result["r"] == nil {
+					t.Errorf("resultado incompleto: %s", body)
+				}
+			},
+		},
+		{
+			name: "TSV con títulos y coma decimal a CSV", query: "?header=true&decimal=comma", contentType: "text/tab-separated-values",
+			accept: "text/csv", body: "a\tb\n1\t2,5\n3\t4\n",
+			expectedStatus: http.StatusOK, expectedType: "text/csv; charset=utf-8",
+			check: func(t *testing.T, body []byte) {
+				if !strings.HasPrefix(string(body), "# rotated\n3;1\n4;2,5\n\n# q\n") || !strings.Contains(string(body), "\n\n# r\n") {
+					t.Errorf("CSV inesperado:\n%s", body)
+				}
+			},
+		},
+		{
+			name: "header=present en el media type", contentType: "text/csv; header=present", accept: "text/tab-separated-values",
+			body: "x,y\n1,2\n3,4\n", expectedStatus: http.StatusOK, expectedType: "text/tab-separated-values; charset=utf-8",
+			check: func(t *testing.T, body []byte) {
+				if !strings.HasPrefix(string(body), "# rotated\n3\t1\n4\t2\n") {
+					t.Errorf("TSV inesperado:\n%s", body)
+				}
+			},
+		},
+		{
+			name: "JSON a zip", contentType: "application/json", accept: "application/zip",
+			body: `{"matrix": [[1, 2], [3, 4]]}`, expectedStatus: http.StatusOK, expectedType: "application/zip",
+			check: func(t *testing.T, body []byte) {
+				archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+				if err != nil {
+					t.Fatalf("zip inválido: %v", err)
+				}
+				var names []string
+				for _, file := range archive.File {
+					names = append(names, file.Name)
+				}
+				if strings.Join(names, ",") != "rotated.csv,q.csv,r.csv" {
+					t.Errorf("archivos = %v, want rotated.csv, q.csv, r.csv", names)
+				}
+			},
+		},
+		{
+			name: ".npy a Matrix Market", query: "?matrix=rotated", contentType: "application/x-npy", accept: "text/x-matrix-market",
+			body: npyBody.String(), expectedStatus: http.StatusOK, expectedType: "text/x-matrix-market",
+			check: func(t *testing.T, body []byte) {
+				if string(body) != "%%MatrixMarket matrix array real general\n2 2\n3\n4\n1\n2\n" {
+					t.Errorf("Matrix Market inesperado:\n%s", body)
+				}
+			},
+		},
+		{
+			name: "Matrix Market disperso a .npy", query: "?matrix=rotated", contentType: "text/x-matrix-market", accept: "application/x-npy",
+			body: "%%MatrixMarket matrix coordinate real general\n2 2 3\n1 1 1\n2 1 3\n2 2 4\n", expectedStatus: http.StatusOK, expectedType: "application/x-npy",
+			check: func(t *testing.T, body []byte) {
+				matrix, err := codec.DecodeNPY(body)
+				if err != nil || !reflect.DeepEqual(matrix, [][]float64{{3, 1}, {4, 0}}) {
+					t.Errorf("rotated = %v (err %v), want [[3 1] [4 0]]", matrix, err)
+				}
+			},
+		},
+		{
+			name: "Matrix Market en formato coordinate", query: "?matrix=rotated&layout=coordinate", contentType: "text/csv", accept: "text/x-matrix-market",
+			body: "1,0\n3,4\n", expectedStatus: http.StatusOK, expectedType: "text/x-matrix-market",
+			check: func(t *testing.T, body []byte) {
+				if !strings.HasPrefix(string(body), "%%MatrixMarket matrix coordinate real general\n2 2 3\n") {
+					t.Errorf("Matrix Market inesperado:\n%s", body)
+				}
+			},
+		},
+		{
+			name: "JSON a .npz", contentType: "application/json", accept: "application/x-npz",
+			body: `{"matrix": [[1, 2], [3, 4]]}`, expectedStatus: http.StatusOK, expectedType: "application/x-npz",
+			check: func(t *testing.T, body []byte) {
+				archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+				if err != nil || len(archive.File) != 3 || archive.File[0].Name != "rotated.npy" {
+					t.Fatalf(".npz inválido: %v", err)
+				}
+				file, _ := archive.File[0].Open()
+				data, _ := io.ReadAll(file)
+				if matrix, err := codec.DecodeNPY(data); err != nil || !reflect.DeepEqual(matrix, [][]float64{{3, 1}, {4, 2}}) {
+					t.Errorf("rotated.npy = %v (err %v)", matrix, err)
+				}
+			},
+		},
+		{
+			name: "zip con archivos Matrix Market", query: "?format=mtx", contentType: "application/json", accept: "application/zip",
+			body: `{"matrix": [[1, 2], [3, 4]]}`, expectedStatus: http.StatusOK, expectedType: "application/zip",
+			check: func(t *testing.T, body []byte) {
+				archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+				if err != nil || len(archive.File) != 3 || archive.File[2].Name != "r.mtx" {
+					t.Errorf("zip inesperado: %v", err)
+				}
+			},
+		},
+		{
+			name: "formato de una matriz sin elegir cuál", accept: "application/x-npy", contentType: "text/csv", body: "1,2\n3,4\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_SELECTION_REQUIRED"},
+		},
+		{
+			name: "matriz inválida para un formato de una matriz", query: "?matrix=x", accept: "application/x-npy", contentType: "text/csv", body: "1,2\n3,4\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "INVALID_FORMAT_OPTION", "option": "matrix", "value": "x"},
+		},
+		{
+			name: "layout inválido", query: "?matrix=q&layout=dense", accept: "text/x-matrix-market", contentType: "text/csv", body: "1,2\n3,4\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "INVALID_FORMAT_OPTION", "option": "layout", "value": "dense"},
+		},
+		{
+			name: ".npy con dtype no soportado", contentType: "application/x-npy",
+			body:           strings.Replace(npyBody.String(), "<f8", "<i8", 1),
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_PARSE_ERROR", "line": float64(1), "column": float64(11), "reasonCode": "NPY_UNSUPPORTED_DTYPE"},
+		},
+		{
+			name: "Matrix Market con índice fuera de rango", contentType: "text/x-matrix-market",
+			body:           "%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_PARSE_ERROR", "line": float64(3), "column": float64(1), "reasonCode": "MTX_INDEX_OUT_OF_RANGE", "value": "3", "limit": float64(2)},
+		},
+		{
+			name: "MessagePack a CBOR", contentType: "application/msgpack", accept: "application/cbor",
+			body: msgpackBody.String(), expectedStatus: http.StatusOK, expectedType: "application/cbor",
+			check: func(t *testing.T, body []byte) {
+				var result models.MatrixProcessResponse
+				if err := codec.DecodeCBOR(body, &result); err != nil || !reflect.DeepEqual(result.Rotated, [][]float64{{3, 1}, {4, 2}}) || result.NodeStats == nil {
+					t.Errorf("resultado = %+v (err %v)", result, err)
+				}
+			},
+		},
+		{
+			name: "float64 crudo ida y vuelta", contentType: "application/x-float64-matrix", accept: "application/x-float64-matrix",
+			body: float64Body.String(), expectedStatus: http.StatusOK, expectedType: "application/x-float64-matrix",
+			check: func(t *testing.T, body []byte) {
+				matrices, err := codec.DecodeFloat64Sections(body)
+				if err != nil || len(matrices) != 3 || !reflect.DeepEqual(matrices[0], [][]float64{{3, 1}, {4, 2}}) {
+					t.Errorf("secciones = %v (err %v)", matrices, err)
+				}
+			},
+		},
+		{
+			name: "x-msgpack en Accept", contentType: "application/json", accept: "application/x-msgpack",
+			body: `{"matrix": [[1, 2], [3, 4]]}`, expectedStatus: http.StatusOK, expectedType: "application/x-msgpack",
+		},
+		{
+			name: "MessagePack con NaN", contentType: "application/msgpack", body: nanBody.String(),
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "NON_FINITE_VALUE"},
+		},
+		{
+			name: "CBOR ilegible", contentType: "application/cbor", body: "\xff\x00",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "INVALID_BODY"},
+		},
+		{
+			name: "float64 crudo truncado", contentType: "application/x-float64-matrix", body: float64Body.String()[:20],
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_PARSE_ERROR", "line": float64(1), "column": float64(21)},
+		},
+		{
+			name: "Accept sin formato soportado responde JSON", contentType: "text/csv", accept: "image/png",
+			body: "1,2\n3,4\n", expectedStatus: http.StatusOK, expectedType: "application/json",
+		},
+		{
+			name: "error de formato con línea y columna", contentType: "text/csv", body: "1,2\n3,abc\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_PARSE_ERROR", "line": float64(2), "column": float64(2), "reasonCode": "INVALID_NUMBER", "value": "abc"},
+		},
+		{
+			name: "fila con menos celdas", contentType: "text/csv", body: "1,2\n3\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_PARSE_ERROR", "line": float64(2), "column": float64(2), "reasonCode": "ROW_LENGTH"},
+			check: func(t *testing.T, body []byte) {
+				var problem models.Problem
+				json.Unmarshal(body, &problem)
+				if want := "error de formato en la línea 2, columna 2: la fila tiene 1 celdas, se esperaban 2"; problem.Detail != want {
+					t.Errorf("detail = %q, want %q", problem.Detail, want)
+				}
+			},
+		},
+		{
+			name: "delimitador inválido", query: "?delimiter=ab", contentType: "text/csv", body: "1\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "INVALID_DELIMITER", "value": "ab"},
+		},
+		{
+			name: "coma como delimitador y decimal", query: "?delimiter=,&decimal=comma", contentType: "text/csv", body: "1\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "OPTIONS_CONFLICT"},
+		},
+		{
+			name: "decimal inválido", query: "?decimal=dot", contentType: "text/csv", body: "1\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "INVALID_FORMAT_OPTION", "option": "decimal", "value": "dot"},
+		},
+		{
+			name: "CSV vacío", contentType: "text/csv", body: "# sin datos\n",
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_EMPTY"},
+		},
+		{
+			name: "COO a JSON con resultados dispersos", contentType: "application/json",
+			body:           `{"sparse": {"format": "coo", "rows": 4, "cols": 4, "row": [3, 0, 2, 1], "col": [3, 0, 2, 1], "data": [4, 1, 3, 2]}}`,
+			expectedStatus: http.StatusOK, expectedType: "application/json",
+			check: func(t *testing.T, body []byte) {
+				var result map[string]interface{}
+				json.Unmarshal(body, &result)
+				rSparse, _ := result["rSparse"].(map[string]interface{})
+				rotatedSparse, _ := result["rotatedSparse"].(map[string]interface{})
+				if rSparse["format"] != "coo" || rotatedSparse["rows"] != float64(4) || result["r"] != nil || result["rotated"] != nil {
+					t.Errorf("resultado inesperado: %s", body)
+				}
+			},
+		},
+		{
+			name: "CSR a CSV densifica el resultado", contentType: "application/json", accept: "text/csv",
+			body:           `{"sparse": {"format": "csr", "rows": 3, "cols": 3, "indptr": [0, 1, 2, 3], "indices": [0, 1, 2], "data": [1, 2, 3]}}`,
+			expectedStatus: http.StatusOK, expectedType: "text/csv; charset=utf-8",
+			check: func(t *testing.T, body []byte) {
+				if !strings.HasPrefix(string(body), "# rotated\n0,0,1\n0,2,0\n3,0,0\n") {
+					t.Errorf("CSV inesperado:\n%s", body)
+				}
+			},
+		},
+		{
+			name: "dispersa sin QR: solo la rotación", contentType: "application/json",
+			body:           `{"sparse": {"format": "coo", "rows": 2000, "cols": 1, "row": [7], "col": [0], "data": [5]}}`,
+			expectedStatus: http.StatusOK, expectedType: "application/json",
+			check: func(t *testing.T, body []byte) {
+				var result map[string]interface{}
+				json.Unmarshal(body, &result)
+				rotatedSparse, _ := result["rotatedSparse"].(map[string]interface{})
+				if result["errorCode"] != "SPARSE_TOO_LARGE" || rotatedSparse["cols"] != float64(2000) || result["q"] != nil || result["qSparse"] != nil {
+					t.Errorf("resultado inesperado: %s", body)
+				}
+			},
+		},
+		{
+			name: "dispersa sin QR a CSV", contentType: "application/json", accept: "text/csv",
+			body:           `{"sparse": {"format": "coo", "rows": 2000, "cols": 1, "row": [7], "col": [0], "data": [5]}}`,
+			expectedStatus: http.StatusRequestEntityTooLarge, expectedDetails: map[string]interface{}{"code": "SPARSE_TOO_LARGE", "elements": float64(4_000_000)},
+		},
+		{
+			name: "matriz dispersa con posición repetida", contentType: "application/json",
+			body:           `{"sparse": {"format": "coo", "rows": 2, "cols": 2, "row": [0, 0], "col": [1, 1], "data": [1, 2]}}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "SPARSE_DUPLICATE_ENTRY", "entry": float64(1), "col": float64(1)},
+		},
+		{
+			name: "matriz compleja con pares y objetos", contentType: "application/json",
+			body:           `{"complex": [[[1, 1], {"re": 2, "im": 0}], [{"re": 0, "im": -3}, [4, 4]]]}`,
+			expectedStatus: http.StatusOK, expectedType: "application/json",
+			check: func(t *testing.T, body []byte) {
+				var result map[string]interface{}
+				json.Unmarshal(body, &result)
+				rotated, _ := result["rotatedComplex"].([]interface{})
+				first, _ := rotated[0].([]interface{})
+				if !reflect.DeepEqual(first[0], map[string]interface{}{"re": float64(0), "im": float64(-3)}) || result["qComplex"] == nil || result["q"] != nil {
+					t.Errorf("resultado inesperado: %s", body)
+				}
+			},
+		},
+		{
+			name: "par complejo de tres elementos", contentType: "application/json",
+			body:           `{"complex": [[[1, 2, 3]]]}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "INVALID_BODY"},
+		},
+		{
+			name: "matriz compleja a CSV", contentType: "application/json", accept: "text/csv",
+			body:           `{"complex": [[[1, 1]]]}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "RESULT_FORMAT_UNSUPPORTED"},
+		},
+		{
+			name: "matriz compleja con infinito en MessagePack", contentType: "application/msgpack", body: complexInfBody.String(),
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "NON_FINITE_VALUE", "row": float64(1), "column": float64(2)},
+		},
+		{
+			name: "matrix y complex a la vez", contentType: "application/json",
+			body:           `{"matrix": [[1]], "complex": [[[1, 1]]]}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_INPUT_CONFLICT"},
+		},
+		{
+			name: "precision devuelve Q y R decimales", contentType: "application/json",
+			body:           `{"matrix": [[3, 1], [4, 2]], "precision": 128}`,
+			expectedStatus: http.StatusOK, expectedType: "application/json",
+			check: func(t *testing.T, body []byte) {
+				var result map[string]interface{}
+				json.Unmarshal(body, &result)
+				r, _ := result["rDecimal"].([]interface{})
+				first, _ := r[0].([]interface{})
+				if first[0] != "-5" || result["precision"] != float64(128) || result["residuals"] == nil || result["r"] != nil {
+					t.Errorf("resultado inesperado: %s", body)
+				}
+			},
+		},
+		{
+			name: "precision a CSV", contentType: "application/json", accept: "text/csv",
+			body:           `{"matrix": [[1]], "precision": 128}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "RESULT_FORMAT_UNSUPPORTED"},
+		},
+		{
+			name: "precision fuera de rango", contentType: "application/json",
+			body:           `{"matrix": [[1]], "precision": 8}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "PRECISION_INVALID", "min": float64(53)},
+		},
+		{
+			name: "precision con matriz dispersa", contentType: "application/json",
+			body:           `{"sparse": {"format": "coo", "rows": 1, "cols": 1, "row": [0], "col": [0], "data": [1]}, "precision": 128}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "OPTION_NOT_APPLICABLE"},
+		},
+		{
+			name: "matriz más ancha que alta", contentType: "application/json",
+			body:           `{"matrix": [[1, 2, 3], [4, 5, 6]]}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_WIDER_THAN_TALL", "rows": float64(2), "cols": float64(3)},
+		},
+		{
+			name: "explain devuelve los pasos de Gram-Schmidt", contentType: "application/json",
+			body:           `{"matrix": [[3, 1], [4, 2]], "explain": true, "explainMethod": "modified"}`,
+			expectedStatus: http.StatusOK, expectedType: "application/json",
+			check: func(t *testing.T, body []byte) {
+				var result struct {
+					Q           [][]float64                    `json:"q"`
+					R           [][]float64                    `json:"r"`
+					Explanation *models.GramSchmidtExplanation `json:"explanation"`
+				}
+				json.Unmarshal(body, &result)
+				if result.Q == nil || result.Explanation == nil || result.Explanation.Method != "modified" ||
+					len(result.Explanation.Steps) != 5 || result.R[0][0] != 5 {
+					t.Errorf("resultado inesperado: %s", body)
+				}
+			},
+		},
+		{
+			name: "explain con método inválido", contentType: "application/json",
+			body:           `{"matrix": [[1]], "explain": true, "explainMethod": "householder"}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "INVALID_EXPLAIN_METHOD"},
+		},
+		{
+			name: "explainMethod sin explain", contentType: "application/json",
+			body:           `{"matrix": [[1]], "explainMethod": "classical"}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "OPTION_NOT_APPLICABLE"},
+		},
+		{
+			name: "explain con precision", contentType: "application/json",
+			body:           `{"matrix": [[1]], "explain": true, "precision": 128}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "OPTIONS_CONFLICT"},
+		},
+		{
+			name: "explain a CSV", contentType: "application/json", accept: "text/csv",
+			body:           `{"matrix": [[1]], "explain": true}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "RESULT_FORMAT_UNSUPPORTED"},
+		},
+		{
+			name: "matrix y sparse a la vez", contentType: "application/json",
+			body:           `{"matrix": [[1]], "sparse": {"format": "coo", "rows": 1, "cols": 1, "data": []}}`,
+			expectedStatus: http.StatusBadRequest, expectedDetails: map[string]interface{}{"code": "MATRIX_INPUT_CONFLICT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/matrix/process"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("Authorization", "Bearer "+token)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Status code = %d, want %d (body %s)", resp.StatusCode, tt.expectedStatus, body)
+			}
+			if tt.expectedType != "" && resp.Header.Get("Content-Type") != tt.expectedType {
+				t.Errorf("Content-Type = %q, want %q", resp.Header.Get("Content-Type"), tt.expectedType)
+			}
+			if tt.check != nil {
+				tt.check(t, body)
+			}
+			if tt.expectedDetails != nil {
+				var problem map[string]interface{}
+				json.Unmarshal(body, &problem)
+				details, _ := problem["details"].(map[string]interface{})
+				for key, want := range tt.expectedDetails {
+					got := details[key]
+					if key == "code" {
+						got = problem["code"]
+					}
+					if got != want {
+						t.Errorf("%s = %v, want %v (body %s)", key, got, want, body)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreateJob_CSV(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	app := newJobTestApp(t)
+	token := createTestToken(t, "test-secret-key")
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs?operation=qr&delimiter=%3B", strings.NewReader("1;2\n3;4\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Error al hacer request: %v", err)
+	}
+	var job map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&job)
+	if resp.StatusCode != http.StatusAccepted || job["operation"] != "qr" {
+		t.Errorf("status %d, job %v; want 202 con operation qr", resp.StatusCode, job)
+	}
+}
+
+func TestCreateJob_CBOR(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	app := newJobTestApp(t)
+	token := createTestToken(t, "test-secret-key")
+
+	// A diferencia de CSV, CBOR lleva el pedido completo: operation va en el cuerpo
+	var body bytes.Buffer
+	codec.EncodeCBOR(&body, models.JobRequest{Matrix: [][]float64{{1, 2}, {3, 4}}, Operation: models.JobOperationQR})
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs", &body)
+	req.Header.Set("Content-Type", "application/cbor")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Error al hacer request: %v", err)
+	}
+	var job map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&job)
+	if resp.StatusCode != http.StatusAccepted || job["operation"] != "qr" {
+		t.Errorf("status %d, job %v; want 202 con operation qr", resp.StatusCode, job)
+	}
+}
+
+// BenchmarkProcessMatrix mide /v1/matrix/process de punta a punta (parseo, QR, Node.js
+// falso y serialización) para una matriz de 100x100 en cada formato de respuesta, con el
+// resultado calculado (sin cache) y leído del cache
+func BenchmarkProcessMatrix(b *testing.B) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	token := createTestToken(b, "test-secret-key")
+	rng := rand.New(rand.NewSource(1))
+	matrix := make([][]float64, 100)
+	for i := range matrix {
+		matrix[i] = make([]float64, 100)
+		for j := range matrix[i] {
+			matrix[i][j] = rng.NormFloat64()
+		}
+	}
+	body, _ := json.Marshal(models.MatrixRequest{Matrix: matrix})
+
+	var calls int32
+	for _, cached := range []bool{false, true} {
+		matrixHandler := NewMatrixHandler(services.NewMatrixProcessor(newNodeStub(b, &calls)))
+		name := "sin cache"
+		if cached {
+			matrixHandler.Cache = cache.New(cache.Config{MaxEntries: 10, TTL: time.Hour}, nil)
+			name = "cache"
+		}
+		app := fiber.New()
+		app.Post("/v1/matrix/process", middleware.AuthenticateToken, matrixHandler.ProcessMatrix)
+
+		for _, accept := range []string{fiber.MIMEApplicationJSON, codec.MIMEMsgPack, codec.MIMECBOR, codec.MIMEFloat64} {
+			b.Run(name+"/"+accept, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					req := httptest.NewRequest(http.MethodPost, "/v1/matrix/process", bytes.NewReader(body))
+					req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+					req.Header.Set("Accept", accept)
+					req.Header.Set("Authorization", "Bearer "+token)
+					resp, err := app.Test(req, -1)
+					if err != nil {
+						b.Fatal(err)
+					}
+					io.Copy(io.Discard, resp.Body)
+					if resp.StatusCode != http.StatusOK {
+						b.Fatalf("status %d", resp.StatusCode)
+					}
+				}
+			})
+		}
+	}
+}
@@ -1,8 +1,17 @@
 package handlers
 
 import (
-	"log"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strings"
 
+	"go-api/internal/apperrors"
+	"go-api/internal/cache"
+	"go-api/internal/codec"
+	"go-api/internal/metrics"
+	"go-api/internal/middleware"
 	"go-api/internal/models"
 	"go-api/internal/services"
 
@@ -11,13 +20,15 @@ import (
 
 // MatrixHandler maneja las peticiones relacionadas con matrices
 type MatrixHandler struct {
-	NodeClient *services.NodeClient
+	Processor *services.MatrixProcessor
+	// Cache guarda los resultados completos por contenido de la matriz (opcional)
+	Cache *cache.Cache
 }
 
 // NewMatrixHandler crea un nuevo handler de matrices
-func NewMatrixHandler(nodeClient *services.NodeClient) *MatrixHandler {
+func NewMatrixHandler(processor *services.MatrixProcessor) *MatrixHandler {
 	return &MatrixHandler{
-		NodeClient: nodeClient,
+		Processor: processor,
 	}
 }
 
@@ -25,63 +36,236 @@ func NewMatrixHandler(nodeClient *services.NodeClient) *MatrixHandler {
 func (h *MatrixHandler) ProcessMatrix(c *fiber.Ctx) error {
 	var req models.MatrixRequest
 
-	// Parsear request
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "formato JSON inválido: " + err.Error(),
-		})
+	// Parsear request (JSON, MessagePack, CBOR o formatos de solo matriz)
+	if _, err := parseMatrixBody(c, &req, &req.Matrix); err != nil {
+		metrics.MatrixProcessTotal.WithLabelValues("invalid_body").Inc()
+		return middleware.WriteProblem(c, err)
+	}
+	if (req.Matrix != nil && req.Sparse != nil) || (req.Matrix != nil && req.Complex != nil) || (req.Sparse != nil && req.Complex != nil) {
+		return middleware.WriteProblem(c, apperrors.New(apperrors.CodeMatrixInputConflict, nil))
+	}
+	if req.Precision != 0 && req.Matrix == nil {
+		return middleware.WriteProblem(c, optionNotApplicable("precision", "matrix"))
+	}
+	if req.Explain && req.Matrix == nil {
+		return middleware.WriteProblem(c, optionNotApplicable("explain", "matrix"))
+	}
+	if req.Explain && req.Precision != 0 {
+		return middleware.WriteProblem(c, apperrors.New(apperrors.CodeOptionsConflict, map[string]interface{}{
+			"option": "explain", "other": "precision",
+		}))
+	}
+	if req.ExplainMethod != "" && !req.Explain {
+		return middleware.WriteProblem(c, optionNotApplicable("explainMethod", "explain"))
+	}
+	if req.Sparse != nil {
+		return h.processSparse(c, &req)
+	}
+	if req.Complex != nil {
+		return h.processComplex(c, &req)
+	}
+	if req.Precision != 0 {
+		return h.processPrecise(c, &req)
+	}
+	if req.Explain {
+		return h.processExplain(c, &req)
+	}
+
+	// Las matrices repetidas se responden desde el cache sin rotar, factorizar ni llamar a Node.js
+	var key string
+	if h.Cache != nil {
+		key = cache.Key(models.JobOperationProcess, req.Matrix)
+		if body, ok := h.Cache.Get(c.UserContext(), key); ok {
+			c.Set(HeaderCache, "HIT")
+			return sendResult(c, nil, body)
+		}
+		c.Set(HeaderCache, "MISS")
+	}
+
+	response, err := h.Processor.Process(c.UserContext(), req.Matrix, services.ProcessOptions{
+		Token:    bearerToken(c),
+		Language: middleware.Language(c),
+	})
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+
+	// Las respuestas parciales (Node.js no disponible) no se guardan para reintentar la próxima vez
+	var body []byte
+	if h.Cache != nil && response.ErrorCode == "" {
+		if body, err = json.Marshal(response); err != nil {
+			return middleware.WriteProblem(c, err)
+		}
+		h.Cache.Set(c.UserContext(), key, body)
+	}
+	return sendResult(c, response, body)
+}
+
+// processSparse procesa una matriz en formato disperso (COO o CSR). No usa el cache:
+// sus claves se calculan sobre la matriz densa.
+func (h *MatrixHandler) processSparse(c *fiber.Ctx, req *models.MatrixRequest) error {
+	response, err := h.Processor.ProcessSparse(c.UserContext(), req.Sparse, services.ProcessOptions{
+		Token:    bearerToken(c),
+		Language: middleware.Language(c),
+	})
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	// Sin QR queda solo la rotación dispersa, que los formatos tabulares tendrían que densificar
+	if response.ErrorCode == string(apperrors.CodeSparseTooLarge) && !structuredResult(c) {
+		return middleware.WriteProblem(c, services.CheckSparseSize(req.Sparse, h.Processor.SparseMaxElements))
+	}
+	return sendResult(c, response, nil)
+}
+
+// processComplex procesa una matriz compleja. Tampoco usa el cache, y el resultado solo
+// se puede pedir en JSON, MessagePack o CBOR: los formatos tabulares guardan matrices reales.
+func (h *MatrixHandler) processComplex(c *fiber.Ctx, req *models.MatrixRequest) error {
+	if err := requireStructuredResult(c, "complex"); err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	// MessagePack y CBOR pueden traer NaN e infinitos, como en CheckFinite
+	for i, row := range req.Complex {
+		for j, value := range row {
+			if !isFinite(value.Re) || !isFinite(value.Im) {
+				return middleware.WriteProblem(c, nonFiniteError(&codec.NonFiniteError{Row: i + 1, Column: j + 1}))
+			}
+		}
 	}
 
-	// Validar matriz
-	if err := services.ValidateMatrix(req.Matrix); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+	response, err := h.Processor.ProcessComplex(c.UserContext(), req.Complex, services.ProcessOptions{
+		Token:    bearerToken(c),
+		Language: middleware.Language(c),
+	})
+	if err != nil {
+		return middleware.WriteProblem(c, err)
 	}
+	return sendResult(c, response, nil)
+}
 
-	// Rotar matriz 90° en sentido horario
-	rotated := services.RotateMatrix90Clockwise(req.Matrix)
+// processPrecise procesa la matriz con QR en big.Float a req.Precision bits. Como las
+// complejas, no usa el cache y su resultado (Q y R como strings decimales) solo se puede
+// pedir en JSON, MessagePack o CBOR.
+func (h *MatrixHandler) processPrecise(c *fiber.Ctx, req *models.MatrixRequest) error {
+	if err := requireStructuredResult(c, "precision"); err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	response, err := h.Processor.ProcessPrecise(c.UserContext(), req.Matrix, req.Precision, services.ProcessOptions{
+		Token:    bearerToken(c),
+		Language: middleware.Language(c),
+	})
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	return sendResult(c, response, nil)
+}
 
-	// Calcular factorización QR de la matriz original (no rotada)
-	// Decisión técnica: calculamos QR de la matriz original para mantener
-	// la relación matemática estándar A = Q * R
-	Q, R, err := services.QRDecomposition(req.Matrix)
+// processExplain procesa la matriz y agrega la traza de Gram-Schmidt en la variante
+// req.ExplainMethod (clásica por defecto). No usa el cache y, como la traza no es tabular,
+// solo se puede pedir en JSON, MessagePack o CBOR.
+func (h *MatrixHandler) processExplain(c *fiber.Ctx, req *models.MatrixRequest) error {
+	method := req.ExplainMethod
+	switch method {
+	case "":
+		method = models.GramSchmidtClassical
+	case models.GramSchmidtClassical, models.GramSchmidtModified:
+	default:
+		return middleware.WriteProblem(c, apperrors.New(apperrors.CodeInvalidExplainMethod, map[string]interface{}{
+			"method":    method,
+			"classical": models.GramSchmidtClassical,
+			"modified":  models.GramSchmidtModified,
+		}))
+	}
+	if err := requireStructuredResult(c, "explain"); err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	response, err := h.Processor.ProcessExplained(c.UserContext(), req.Matrix, method, services.ProcessOptions{
+		Token:    bearerToken(c),
+		Language: middleware.Language(c),
+	})
 	if err != nil {
-		log.Printf("Error en factorización QR: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "error al calcular factorización QR: " + err.Error(),
-		})
+		return middleware.WriteProblem(c, err)
+	}
+	return sendResult(c, response, nil)
+}
+
+// requireStructuredResult rechaza un Accept tabular para los resultados que no son
+// matrices float64 (RESULT_FORMAT_UNSUPPORTED)
+func requireStructuredResult(c *fiber.Ctx, mode string) error {
+	if structuredResult(c) {
+		return nil
 	}
+	return apperrors.New(apperrors.CodeResultFormatUnsupported, map[string]interface{}{"mode": mode})
+}
+
+// optionNotApplicable error OPTION_NOT_APPLICABLE: option solo se puede usar con requires
+func optionNotApplicable(option, requires string) error {
+	return apperrors.New(apperrors.CodeOptionNotApplicable, map[string]interface{}{
+		"option": option, "requires": requires,
+	})
+}
 
-	// Obtener token JWT del header Authorization
-	// El middleware ya validó que existe, así que podemos extraerlo directamente
-	authHeader := c.Get("Authorization")
-	tokenJWT := ""
-	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		tokenJWT = authHeader[7:]
+// structuredResult indica si Accept pide el resultado completo (JSON, MessagePack o CBOR)
+// en vez de sus matrices densas
+func structuredResult(c *fiber.Ctx) bool {
+	switch c.Accepts(resultFormats...) {
+	case "", fiber.MIMEApplicationJSON, codec.MIMEMsgPack, codec.MIMEXMsgPack, codec.MIMECBOR:
+		return true
 	}
+	return false
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
 
-	// Obtener estadísticas de Node.js
-	var nodeStats *models.MatrixStatsResponse
-	nodeStats, err = h.NodeClient.GetMatrixStats(Q, R, rotated, tokenJWT)
+// HeaderCache indica si el resultado salió del cache (HIT) o se calculó (MISS)
+const HeaderCache = "X-Cache"
+
+// sendResult responde el resultado en el formato pedido en Accept (JSON, MessagePack, CBOR,
+// CSV, TSV, Matrix Market, .npy, .npz, float64 crudo o zip) con su ETag (ver sendWithETag).
+// body es el resultado ya serializado como JSON (el del cache) o nil; response puede ser
+// nil si body no lo es.
+func sendResult(c *fiber.Ctx, response *models.MatrixProcessResponse, body []byte) error {
+	payload, contentType, err := encodeResult(c, response, body)
 	if err != nil {
-		log.Printf("Error al obtener estadísticas de Node.js: %v", err)
-		// Continuamos sin las estadísticas de Node, pero las incluimos en el error
-		return c.Status(fiber.StatusOK).JSON(models.MatrixProcessResponse{
-			Rotated: rotated,
-			Q:       Q,
-			R:       R,
-			Error:   "No se pudieron obtener estadísticas de Node.js: " + err.Error(),
-		})
+		return middleware.WriteProblem(c, err)
+	}
+	c.Vary(fiber.HeaderAccept)
+	return sendWithETag(c, payload, contentType)
+}
+
+// sendWithETag responde payload con status 200 y su ETag (hash del contenido). Si el ETag
+// coincide con If-None-Match, GET y HEAD responden 304 sin cuerpo y el resto de los métodos
+// 412 PRECONDITION_FAILED, como pide RFC 9110.
+func sendWithETag(c *fiber.Ctx, payload []byte, contentType string) error {
+	sum := sha256.Sum256(payload)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Set(fiber.HeaderETag, etag)
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+		return middleware.WriteProblem(c, apperrors.New(apperrors.CodePreconditionFailed, nil))
 	}
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Status(fiber.StatusOK).Send(payload)
+}
 
-	// Respuesta exitosa
-	response := models.MatrixProcessResponse{
-		Rotated:   rotated,
-		Q:         Q,
-		R:         R,
-		NodeStats: nodeStats,
+// etagMatches indica si el header If-None-Match (lista separada por comas, "*" o
+// ETags débiles W/"...") incluye etag
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
 	}
+	return false
+}
 
-	return c.Status(fiber.StatusOK).JSON(response)
+// bearerToken obtiene el token JWT del header Authorization para reenviarlo a Node.js
+// El middleware ya validó que existe, así que podemos extraerlo directamente
+func bearerToken(c *fiber.Ctx) string {
+	return strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
 }
@@ -74,8 +74,15 @@ func TestProcessMatrix(t *testing.T) {
 			checkResponse: func(t *testing.T, resp *http.Response) {
 				var result map[string]interface{}
 				json.NewDecoder(resp.Body).Decode(&result)
-				if result["error"] == nil {
-					t.Error("Expected 'error' in response")
+				if result["code"] != "MATRIX_NOT_RECTANGULAR" {
+					t.Errorf("code = %v, want MATRIX_NOT_RECTANGULAR", result["code"])
+				}
+				details, _ := result["details"].(map[string]interface{})
+				if details["row"] != float64(1) {
+					t.Errorf("details.row = %v, want 1", details["row"])
+				}
+				if ct := resp.Header.Get("Content-Type"); ct != "application/problem+json" {
+					t.Errorf("Content-Type = %q, want application/problem+json", ct)
 				}
 			},
 		},
@@ -95,7 +102,7 @@ func TestProcessMatrix(t *testing.T) {
 			},
 			authToken:      token,
 			nodeURL:        "http://localhost:9999", // URL que no existe para simular error
-			expectedStatus: http.StatusOK,            // El handler devuelve 200 pero con error en el campo Error
+			expectedStatus: http.StatusOK,           // El handler devuelve 200 pero con error en el campo Error
 			checkResponse: func(t *testing.T, resp *http.Response) {
 				var result map[string]interface{}
 				json.NewDecoder(resp.Body).Decode(&result)
@@ -116,7 +123,7 @@ func TestProcessMatrix(t *testing.T) {
 
 			// Crear cliente Node.js con la URL especificada en el test
 			nodeClient := services.NewNodeClient(tt.nodeURL)
-			handler := NewMatrixHandler(nodeClient)
+			handler := NewMatrixHandler(services.NewMatrixProcessor(nodeClient))
 
 			// Configurar ruta con middleware de autenticación
 			// El middleware leerá JWT_SECRET del entorno
@@ -153,11 +160,11 @@ func TestProcessMatrix(t *testing.T) {
 }
 
 // createTestToken crea un token JWT válido para pruebas
-func createTestToken(t *testing.T, secret string) string {
+func createTestToken(t testing.TB, secret string) string {
 	claims := &middleware.Claims{
-		Username: "admin",
-		ID:       1,
-		Role:     "admin",
+		Username:         "admin",
+		ID:               1,
+		Role:             "admin",
 		RegisteredClaims: jwt.RegisteredClaims{},
 	}
 
@@ -168,4 +175,3 @@ func createTestToken(t *testing.T, secret string) string {
 	}
 	return tokenString
 }
-
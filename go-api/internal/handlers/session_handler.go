@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/logging"
+	"go-api/internal/metrics"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// sessionWriteTimeout espera máxima para escribir un evento en el WebSocket
+const sessionWriteTimeout = 10 * time.Second
+
+// SessionHandler maneja las sesiones interactivas de edición de matrices por WebSocket
+type SessionHandler struct {
+	Processor *services.MatrixProcessor
+	// Debounce espera sin cambios antes de recalcular
+	Debounce time.Duration
+	// MaxMessageBytes tamaño máximo de un mensaje del cliente
+	MaxMessageBytes int64
+}
+
+// NewSessionHandler crea un handler de sesiones con debounce de 150ms y mensajes de hasta 4MB
+func NewSessionHandler(processor *services.MatrixProcessor) *SessionHandler {
+	return &SessionHandler{
+		Processor:       processor,
+		Debounce:        150 * time.Millisecond,
+		MaxMessageBytes: fiber.DefaultBodyLimit,
+	}
+}
+
+// Connect abre una sesión interactiva: el cliente envía la matriz completa o cambios de
+// celdas (models.SessionMessage) y recibe el resultado recalculado (models.SessionEvent).
+// El token se valida antes del upgrade (middleware.AuthenticateWebSocket) y la sesión se
+// cierra cuando expira.
+// GET /v1/matrix/session
+func (h *SessionHandler) Connect(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return middleware.WriteProblem(c, fiber.ErrUpgradeRequired)
+	}
+
+	// La sesión sigue después de que el handler retorna: capturar ahora lo que se usa de c
+	ctx := logging.WithRequestID(context.Background(), middleware.GetRequestID(c))
+	opts := services.ProcessOptions{Token: bearerToken(c), Language: middleware.Language(c)}
+	var expiresAt time.Time
+	if claims, ok := c.Locals("user").(*middleware.Claims); ok && claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	return websocket.New(func(conn *websocket.Conn) {
+		h.serve(ctx, conn, opts, expiresAt)
+	})(c)
+}
+
+// serve atiende los mensajes de una sesión hasta que el cliente cierra o el token expira
+func (h *SessionHandler) serve(ctx context.Context, conn *websocket.Conn, opts services.ProcessOptions, expiresAt time.Time) {
+	metrics.SessionsActive.Inc()
+	defer metrics.SessionsActive.Dec()
+	slog.DebugContext(ctx, "matrix session opened")
+
+	conn.SetReadLimit(h.MaxMessageBytes)
+
+	// Los eventos llegan desde la sesión y desde este loop: serializar las escrituras
+	var writeMu sync.Mutex
+	send := func(event models.SessionEvent) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(sessionWriteTimeout))
+		if err := conn.WriteJSON(event); err != nil {
+			slog.DebugContext(ctx, "matrix session write failed", "error", err)
+		}
+	}
+	fail := func(err error) {
+		problem := apperrors.From(err).Problem(opts.Language)
+		send(models.SessionEvent{Type: models.SessionEventError, Problem: &problem})
+	}
+
+	session := services.NewMatrixSession(ctx, h.Processor, opts, h.Debounce, send)
+	defer session.Close()
+
+	// Al expirar el token se cierra la sesión: cerrar la conexión desbloquea el loop de lectura
+	if !expiresAt.IsZero() {
+		expiry := time.AfterFunc(time.Until(expiresAt), func() {
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, string(apperrors.CodeTokenExpired))
+			conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(sessionWriteTimeout))
+			conn.Close()
+		})
+		defer expiry.Stop()
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				slog.DebugContext(ctx, "matrix session closed", "error", err)
+			}
+			return
+		}
+
+		var msg models.SessionMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			fail(apperrors.Wrap(apperrors.CodeInvalidBody, err))
+			continue
+		}
+		switch msg.Type {
+		case models.SessionMessageMatrix:
+			_, err = session.SetMatrix(msg.Matrix)
+		case models.SessionMessageUpdate:
+			_, err = session.Update(msg.Cells)
+		default:
+			err = apperrors.New(apperrors.CodeSessionInvalidMessage, map[string]interface{}{"type": msg.Type})
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
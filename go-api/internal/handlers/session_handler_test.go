@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/services"
+)
+
+// newSessionTestServer levanta la app en un puerto local (el upgrade a WebSocket necesita
+// una conexión real) y retorna la URL base ws://
+func newSessionTestServer(t *testing.T) string {
+	t.Helper()
+	nodeClient := services.NewNodeClient("http://localhost:9999")
+	nodeClient.MaxRetries = 0
+	handler := NewSessionHandler(services.NewMatrixProcessor(nodeClient))
+	handler.Debounce = 10 * time.Millisecond
+
+	app := fiber.New()
+	app.Get("/v1/matrix/session", middleware.AuthenticateWebSocket, handler.Connect)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() = %v", err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+	return "ws://" + ln.Addr().String() + "/v1/matrix/session"
+}
+
+func TestSessionHandler_Auth(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	token := createTestToken(t, "test-secret-key")
+	url := newSessionTestServer(t)
+
+	tests := []struct {
+		name           string
+		url            string
+		header         http.Header
+		expectedStatus int
+	}{
+		{name: "sin token", url: url, expectedStatus: http.StatusUnauthorized},
+		{name: "token inválido", url: url + "?access_token=abc", expectedStatus: http.StatusForbidden},
+		{name: "token en el header", url: url, header: http.Header{"Authorization": {"Bearer " + token}}, expectedStatus: http.StatusSwitchingProtocols},
+		{name: "token en el query param", url: url + "?access_token=" + token, expectedStatus: http.StatusSwitchingProtocols},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, resp, err := fastws.DefaultDialer.Dial(tt.url, tt.header)
+			if conn != nil {
+				conn.Close()
+			}
+			if resp == nil {
+				t.Fatalf("Dial() = %v, sin respuesta", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestSessionHandler_Messages(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	token := createTestToken(t, "test-secret-key")
+
+	conn, _, err := fastws.DefaultDialer.Dial(newSessionTestServer(t)+"?access_token="+token, nil)
+	if err != nil {
+		t.Fatalf("Dial() = %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	tests := []struct {
+		name     string
+		message  interface{}
+		wantType string
+		check    func(*testing.T, models.SessionEvent)
+	}{
+		{
+			name:     "matriz completa",
+			message:  models.SessionMessage{Type: models.SessionMessageMatrix, Matrix: [][]float64{{1, 2}, {3, 4}}},
+			wantType: models.SessionEventResult,
+			check: func(t *testing.T, event models.SessionEvent) {
+				if event.Version != 1 || event.Result.Rotated[0][0] != 3 {
+					t.Errorf("evento = %+v, want versión 1 con rotated[0][0] = 3", event)
+				}
+			},
+		},
+		{
+			name:     "cambio de celda",
+			message:  models.SessionMessage{Type: models.SessionMessageUpdate, Cells: []models.CellUpdate{{Row: 1, Col: 0, Value: 8}}},
+			wantType: models.SessionEventResult,
+			check: func(t *testing.T, event models.SessionEvent) {
+				if event.Version != 2 || event.Result.Rotated[0][0] != 8 {
+					t.Errorf("evento = %+v, want versión 2 con rotated[0][0] = 8", event)
+				}
+			},
+		},
+		{
+			name:     "tipo de mensaje desconocido",
+			message:  map[string]string{"type": "otro"},
+			wantType: models.SessionEventError,
+			check: func(t *testing.T, event models.SessionEvent) {
+				if event.Problem == nil || event.Problem.Code != "SESSION_INVALID_MESSAGE" {
+					t.Errorf("problem = %+v, want SESSION_INVALID_MESSAGE", event.Problem)
+				}
+			},
+		},
+		{
+			name:     "celda fuera de rango",
+			message:  models.SessionMessage{Type: models.SessionMessageUpdate, Cells: []models.CellUpdate{{Row: 5, Col: 0, Value: 1}}},
+			wantType: models.SessionEventError,
+			check: func(t *testing.T, event models.SessionEvent) {
+				if event.Problem == nil || event.Problem.Code != "SESSION_CELL_OUT_OF_RANGE" {
+					t.Errorf("problem = %+v, want SESSION_CELL_OUT_OF_RANGE", event.Problem)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := conn.WriteJSON(tt.message); err != nil {
+				t.Fatalf("WriteJSON() = %v", err)
+			}
+			var event models.SessionEvent
+			if err := conn.ReadJSON(&event); err != nil {
+				t.Fatalf("ReadJSON() = %v", err)
+			}
+			if event.Type != tt.wantType {
+				t.Fatalf("type = %q, want %q (evento %+v)", event.Type, tt.wantType, event)
+			}
+			tt.check(t, event)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"go-api/internal/apperrors"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/workspaces"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WorkspaceHandler maneja los workspaces: matrices cuya factorización QR se guarda en el
+// servidor y se actualiza por operaciones (agregar/eliminar filas o columnas, rango 1)
+type WorkspaceHandler struct {
+	Manager *workspaces.Manager
+}
+
+// NewWorkspaceHandler crea un nuevo handler de workspaces
+func NewWorkspaceHandler(manager *workspaces.Manager) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		Manager: manager,
+	}
+}
+
+// CreateWorkspace factoriza la matriz (JSON, CSV o TSV) y responde 201 con el workspace creado (sin Q)
+// POST /v1/workspaces
+func (h *WorkspaceHandler) CreateWorkspace(c *fiber.Ctx) error {
+	var req models.WorkspaceRequest
+	if _, err := parseMatrixBody(c, &req, &req.Matrix); err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+
+	ws, err := h.Manager.Create(c.UserContext(), userID(c), req)
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+
+	c.Location(c.Path() + "/" + ws.ID)
+	return c.Status(fiber.StatusCreated).JSON(ws)
+}
+
+// GetWorkspace retorna la matriz y su factorización Q, R
+// GET /v1/workspaces/:id
+func (h *WorkspaceHandler) GetWorkspace(c *fiber.Ctx) error {
+	ws, err := h.Manager.Get(c.UserContext(), userID(c), c.Params("id"))
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	return c.JSON(ws)
+}
+
+// UpdateWorkspace aplica una lista de operaciones de forma atómica y retorna el R actualizado
+// POST /v1/workspaces/:id/updates
+func (h *WorkspaceHandler) UpdateWorkspace(c *fiber.Ctx) error {
+	var req models.WorkspaceUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return middleware.WriteProblem(c, apperrors.Wrap(apperrors.CodeInvalidBody, err))
+	}
+
+	ws, err := h.Manager.Apply(c.UserContext(), userID(c), c.Params("id"), req.Operations)
+	if err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	return c.JSON(ws)
+}
+
+// DeleteWorkspace elimina el workspace
+// DELETE /v1/workspaces/:id
+func (h *WorkspaceHandler) DeleteWorkspace(c *fiber.Ctx) error {
+	if err := h.Manager.Delete(c.UserContext(), userID(c), c.Params("id")); err != nil {
+		return middleware.WriteProblem(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go-api/internal/middleware"
+	"go-api/internal/models"
+	"go-api/internal/workspaces"
+)
+
+// newWorkspaceTestApp crea una app con las rutas de workspaces sobre un manager en memoria
+func newWorkspaceTestApp() *fiber.App {
+	handler := NewWorkspaceHandler(workspaces.NewManager(workspaces.Config{
+		MaxPerUser: 2, MaxElements: 100, IdleTTL: time.Hour, RefactorEvery: 50,
+	}))
+	app := fiber.New()
+	app.Post("/v1/workspaces", middleware.AuthenticateToken, handler.CreateWorkspace)
+	app.Get("/v1/workspaces/:id", middleware.AuthenticateToken, handler.GetWorkspace)
+	app.Post("/v1/workspaces/:id/updates", middleware.AuthenticateToken, handler.UpdateWorkspace)
+	app.Delete("/v1/workspaces/:id", middleware.AuthenticateToken, handler.DeleteWorkspace)
+	return app
+}
+
+func TestWorkspaceHandler_Lifecycle(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+	app := newWorkspaceTestApp()
+	owner := createUserToken(t, "test-secret-key", 1)
+	other := createUserToken(t, "test-secret-key", 2)
+
+	resp, created := doJobRequest(t, app, http.MethodPost, "/v1/workspaces", owner, models.WorkspaceRequest{Matrix: [][]float64{{3, 0}, {4, 5}}})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST status = %d, body = %v", resp.StatusCode, created)
+	}
+	id, _ := created["id"].(string)
+	if resp.Header.Get("Location") != "/v1/workspaces/"+id {
+		t.Errorf("Location = %q", resp.Header.Get("Location"))
+	}
+	if _, ok := created["q"]; ok {
+		t.Error("la respuesta de creación no debe incluir q")
+	}
+	path := "/v1/workspaces/" + id
+	tall := make([][]float64, 101)
+	for i := range tall {
+		tall[i] = []float64{1}
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		body           interface{}
+		expectedStatus int
+		expectedCode   string
+		check          func(*testing.T, map[string]interface{})
+	}{
+		{
+			name:   "agregar fila",
+			method: http.MethodPost, path: path + "/updates", token: owner,
+			body: models.WorkspaceUpdateRequest{Operations: []models.WorkspaceOperation{
+				{Type: models.WorkspaceOpInsertRow, Values: []float64{0, 1}},
+			}},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if body["rows"] != float64(3) || body["version"] != float64(2) {
+					t.Errorf("rows = %v, version = %v; want 3 y 2", body["rows"], body["version"])
+				}
+			},
+		},
+		{
+			name:   "operación inválida",
+			method: http.MethodPost, path: path + "/updates", token: owner,
+			body: models.WorkspaceUpdateRequest{Operations: []models.WorkspaceOperation{
+				{Type: models.WorkspaceOpInsertRow, Values: []float64{1, 1}},
+				{Type: models.WorkspaceOpDeleteColumn},
+			}},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "WORKSPACE_INVALID_OPERATION",
+			check: func(t *testing.T, body map[string]interface{}) {
+				details, _ := body["details"].(map[string]interface{})
+				if details["index"] != float64(1) {
+					t.Errorf("details = %v, want index 1", details)
+				}
+			},
+		},
+		{
+			name:   "sin operaciones",
+			method: http.MethodPost, path: path + "/updates", token: owner,
+			body:           models.WorkspaceUpdateRequest{},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_BODY",
+		},
+		{
+			name:   "consultar con Q y matriz",
+			method: http.MethodGet, path: path, token: owner,
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				q, _ := body["q"].([]interface{})
+				matrix, _ := body["matrix"].([]interface{})
+				if len(q) != 3 || len(matrix) != 3 {
+					t.Errorf("q = %v, matrix = %v; want 3 filas", q, matrix)
+				}
+			},
+		},
+		{
+			name:   "otro usuario",
+			method: http.MethodGet, path: path, token: other,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "WORKSPACE_NOT_FOUND",
+		},
+		{
+			name:   "matriz demasiado grande",
+			method: http.MethodPost, path: "/v1/workspaces", token: owner,
+			body:           models.WorkspaceRequest{Matrix: tall},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedCode:   "WORKSPACE_TOO_LARGE",
+		},
+		{
+			name:   "eliminar",
+			method: http.MethodDelete, path: path, token: owner,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "consultar eliminado",
+			method: http.MethodGet, path: path, token: owner,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "WORKSPACE_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := doJobRequest(t, app, tt.method, tt.path, tt.token, tt.body)
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Status code = %d, want %d (body %v)", resp.StatusCode, tt.expectedStatus, body)
+			}
+			if tt.expectedCode != "" && body["code"] != tt.expectedCode {
+				t.Errorf("code = %v, want %s", body["code"], tt.expectedCode)
+			}
+			if tt.check != nil {
+				tt.check(t, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package idempotency
+
+import (
+	"container/list"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/metrics"
+)
+
+// Config expiración y límites de las claves; un límite en 0 es ilimitado
+type Config struct {
+	// TTL tiempo durante el cual una clave reproduce la primera respuesta
+	TTL time.Duration
+	// JanitorInterval cada cuánto se eliminan las claves expiradas
+	JanitorInterval time.Duration
+	// MaxEntries cantidad máxima de claves guardadas (en curso o con respuesta)
+	MaxEntries int
+	// MaxBytes tamaño máximo en bytes de los cuerpos de las respuestas guardadas
+	MaxBytes int64
+}
+
+// Response respuesta guardada para reproducirla en los reintentos
+type Response struct {
+	Status  int
+	Headers map[string]string
+	Body    []byte
+}
+
+// recordKey las claves se aíslan por usuario: dos usuarios pueden usar la misma clave
+type recordKey struct {
+	userID int
+	key    string
+}
+
+// record estado de una clave; response es nil mientras la primera petición está en curso.
+// Las claves con respuesta están en Store.done (el: su elemento, size: bytes del cuerpo).
+type record struct {
+	fingerprint string
+	response    *Response
+	expiresAt   time.Time
+	el          *list.Element
+	size        int64
+}
+
+// Store guarda en memoria la primera respuesta de cada Idempotency-Key por usuario.
+// Al llegar a MaxEntries o MaxBytes se desalojan las respuestas más antiguas; las claves
+// en curso no se desalojan.
+type Store struct {
+	cfg Config
+	now func() time.Time
+
+	mu    sync.Mutex
+	items map[recordKey]*record
+	// done claves con respuesta, de la más antigua a la más reciente
+	done  *list.List
+	bytes int64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewStore crea un store; el limpiador de claves expiradas no arranca hasta llamar a Start
+func NewStore(cfg Config) *Store {
+	if cfg.JanitorInterval <= 0 {
+		cfg.JanitorInterval = time.Minute
+	}
+	return &Store{
+		cfg:   cfg,
+		now:   time.Now,
+		items: make(map[recordKey]*record),
+		done:  list.New(),
+		quit:  make(chan struct{}),
+	}
+}
+
+// Start arranca el limpiador de claves expiradas
+func (s *Store) Start() {
+	s.wg.Add(1)
+	go s.janitor()
+}
+
+// Stop detiene el limpiador
+func (s *Store) Stop() {
+	close(s.quit)
+	s.wg.Wait()
+}
+
+// Begin reserva la clave de userID para una petición con la huella dada.
+// Retorna la respuesta guardada si la clave ya se completó con la misma huella, o nil si
+// la petición es nueva: en ese caso el llamador debe terminar con Complete o Release.
+// Los errores son IDEMPOTENCY_KEY_MISMATCH (otra huella) e IDEMPOTENCY_KEY_IN_USE
+// (la primera petición todavía no terminó), e IDEMPOTENCY_STORE_FULL si hay MaxEntries
+// claves en curso.
+func (s *Store) Begin(userID int, key, fingerprint string) (*Response, error) {
+	now := s.now()
+	k := recordKey{userID, key}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[k]
+	if ok && r.expiresAt.After(now) {
+		switch {
+		case r.fingerprint != fingerprint:
+			metrics.IdempotencyRequestsTotal.WithLabelValues("mismatch").Inc()
+			return nil, apperrors.New(apperrors.CodeIdempotencyKeyMismatch, nil)
+		case r.response == nil:
+			metrics.IdempotencyRequestsTotal.WithLabelValues("in_progress").Inc()
+			return nil, apperrors.New(apperrors.CodeIdempotencyKeyInUse, nil)
+		default:
+			metrics.IdempotencyRequestsTotal.WithLabelValues("replayed").Inc()
+			return r.response, nil
+		}
+	}
+	if ok {
+		s.remove(k, r)
+	}
+	if s.cfg.MaxEntries > 0 {
+		for len(s.items) >= s.cfg.MaxEntries && s.done.Len() > 0 {
+			s.evictOldest()
+		}
+		if len(s.items) >= s.cfg.MaxEntries {
+			metrics.IdempotencyRequestsTotal.WithLabelValues("rejected").Inc()
+			return nil, apperrors.New(apperrors.CodeIdempotencyStoreFull, map[string]interface{}{"max": s.cfg.MaxEntries})
+		}
+	}
+	s.items[k] = &record{fingerprint: fingerprint, expiresAt: now.Add(s.cfg.TTL)}
+	metrics.IdempotencyRequestsTotal.WithLabelValues("new").Inc()
+	metrics.IdempotencyKeys.Set(float64(len(s.items)))
+	return nil, nil
+}
+
+// Complete guarda la respuesta de una clave reservada con Begin; la expiración
+// se cuenta desde este momento. Una respuesta más grande que MaxBytes no se guarda:
+// la clave se libera como con Release.
+func (s *Store) Complete(userID int, key string, resp *Response) {
+	k := recordKey{userID, key}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[k]
+	if !ok || r.response != nil {
+		return
+	}
+	size := int64(len(resp.Body))
+	if s.cfg.MaxBytes > 0 && size > s.cfg.MaxBytes {
+		s.remove(k, r)
+		return
+	}
+	r.response = resp
+	r.expiresAt = s.now().Add(s.cfg.TTL)
+	r.size = size
+	r.el = s.done.PushBack(k)
+	s.bytes += size
+	for s.cfg.MaxBytes > 0 && s.bytes > s.cfg.MaxBytes {
+		s.evictOldest()
+	}
+}
+
+// Release libera una clave reservada sin guardar respuesta (ej: error del servidor),
+// así un reintento vuelve a ejecutar la petición
+func (s *Store) Release(userID int, key string) {
+	k := recordKey{userID, key}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.items[k]; ok {
+		s.remove(k, r)
+	}
+}
+
+// janitor elimina periódicamente las claves expiradas
+func (s *Store) janitor() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.cfg.JanitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-ticker.C:
+			s.deleteExpired()
+		}
+	}
+}
+
+// deleteExpired elimina las claves expiradas y retorna cuántas eliminó.
+// Las claves en curso también expiran: una petición que nunca terminó no bloquea la clave.
+func (s *Store) deleteExpired() int {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for k, r := range s.items {
+		if !r.expiresAt.After(now) {
+			s.remove(k, r)
+			deleted++
+		}
+	}
+	if deleted > 0 {
+		slog.Debug("deleted expired idempotency keys", "count", deleted)
+	}
+	return deleted
+}
+
+// evictOldest desaloja la respuesta guardada más antigua. Requiere mu y done no vacía.
+func (s *Store) evictOldest() {
+	k := s.done.Front().Value.(recordKey)
+	s.remove(k, s.items[k])
+}
+
+// remove elimina la clave k y su respuesta, si la tiene. Requiere mu.
+func (s *Store) remove(k recordKey, r *record) {
+	delete(s.items, k)
+	if r.el != nil {
+		s.done.Remove(r.el)
+		s.bytes -= r.size
+	}
+	metrics.IdempotencyKeys.Set(float64(len(s.items)))
+}
@@ -0,0 +1,134 @@
+package idempotency
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-api/internal/apperrors"
+)
+
+func TestStore_Begin(t *testing.T) {
+	s := NewStore(Config{TTL: time.Hour})
+	first := &Response{Status: 200, Body: []byte(`{"ok":true}`)}
+
+	if resp, err := s.Begin(1, "k", "h1"); resp != nil || err != nil {
+		t.Fatalf("Begin nueva = (%v, %v), want (nil, nil)", resp, err)
+	}
+	if _, err := s.Begin(1, "k", "h1"); !errors.Is(err, apperrors.New(apperrors.CodeIdempotencyKeyInUse, nil)) {
+		t.Errorf("Begin en curso: err = %v, want IDEMPOTENCY_KEY_IN_USE", err)
+	}
+	s.Complete(1, "k", first)
+
+	tests := []struct {
+		name        string
+		userID      int
+		key         string
+		fingerprint string
+		want        *Response
+		wantCode    apperrors.Code
+	}{
+		{name: "misma huella reproduce", userID: 1, key: "k", fingerprint: "h1", want: first},
+		{name: "otra huella", userID: 1, key: "k", fingerprint: "h2", wantCode: apperrors.CodeIdempotencyKeyMismatch},
+		{name: "otro usuario", userID: 2, key: "k", fingerprint: "h2"},
+		{name: "otra clave", userID: 1, key: "k2", fingerprint: "h2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.Begin(tt.userID, tt.key, tt.fingerprint)
+			if tt.wantCode != "" {
+				if !errors.Is(err, apperrors.New(tt.wantCode, nil)) {
+					t.Fatalf("err = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if resp != tt.want {
+				t.Errorf("respuesta = %v, want %v", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_ReleaseAndExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewStore(Config{TTL: time.Minute})
+	s.now = func() time.Time { return now }
+
+	s.Begin(1, "liberada", "h")
+	s.Release(1, "liberada")
+	if resp, err := s.Begin(1, "liberada", "otra"); resp != nil || err != nil {
+		t.Errorf("Begin tras Release = (%v, %v), want (nil, nil)", resp, err)
+	}
+
+	s.Begin(1, "expira", "h")
+	s.Complete(1, "expira", &Response{Status: 201})
+	now = now.Add(30 * time.Second)
+	if resp, _ := s.Begin(1, "expira", "h"); resp == nil {
+		t.Fatal("la respuesta debería seguir guardada antes del TTL")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if deleted := s.deleteExpired(); deleted != 2 {
+		t.Errorf("deleteExpired = %d, want 2", deleted)
+	}
+	if resp, err := s.Begin(1, "expira", "otra"); resp != nil || err != nil {
+		t.Errorf("Begin tras expirar = (%v, %v), want (nil, nil)", resp, err)
+	}
+}
+
+func TestStore_Limits(t *testing.T) {
+	t.Run("MaxEntries desaloja la respuesta más antigua", func(t *testing.T) {
+		s := NewStore(Config{TTL: time.Hour, MaxEntries: 2})
+		for _, key := range []string{"a", "b"} {
+			s.Begin(1, key, "h")
+			s.Complete(1, key, &Response{Status: 201})
+		}
+		if resp, err := s.Begin(1, "c", "h"); resp != nil || err != nil {
+			t.Fatalf("Begin c = (%v, %v), want (nil, nil)", resp, err)
+		}
+		if _, ok := s.items[recordKey{1, "a"}]; ok || len(s.items) != 2 {
+			t.Errorf("claves = %d (a presente %v), want 2 sin a", len(s.items), ok)
+		}
+		if resp, _ := s.Begin(1, "b", "h"); resp == nil {
+			t.Error("b debería seguir guardada")
+		}
+	})
+
+	t.Run("MaxEntries con todas las claves en curso", func(t *testing.T) {
+		s := NewStore(Config{TTL: time.Hour, MaxEntries: 1})
+		s.Begin(1, "a", "h")
+		if _, err := s.Begin(1, "b", "h"); !errors.Is(err, apperrors.New(apperrors.CodeIdempotencyStoreFull, nil)) {
+			t.Errorf("err = %v, want IDEMPOTENCY_STORE_FULL", err)
+		}
+		s.Release(1, "a")
+		if _, err := s.Begin(1, "b", "h"); err != nil {
+			t.Errorf("Begin tras Release: err = %v", err)
+		}
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		s := NewStore(Config{TTL: time.Hour, MaxBytes: 10})
+		for _, key := range []string{"a", "b"} {
+			s.Begin(1, key, "h")
+			s.Complete(1, key, &Response{Status: 200, Body: []byte("12345")})
+		}
+		s.Begin(1, "c", "h")
+		s.Complete(1, "c", &Response{Status: 200, Body: []byte("123")})
+		if _, ok := s.items[recordKey{1, "a"}]; ok || s.bytes != 8 {
+			t.Errorf("bytes = %d (a presente %v), want 8 sin a", s.bytes, ok)
+		}
+
+		s.Begin(1, "grande", "h")
+		s.Complete(1, "grande", &Response{Status: 200, Body: make([]byte, 11)})
+		if resp, err := s.Begin(1, "grande", "otra"); resp != nil || err != nil {
+			t.Errorf("una respuesta más grande que MaxBytes no se guarda: (%v, %v)", resp, err)
+		}
+		if s.bytes != 8 {
+			t.Errorf("bytes = %d, want 8", s.bytes)
+		}
+	})
+}
@@ -0,0 +1,416 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/metrics"
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// Config configuración del pool de workers y la expiración de resultados
+type Config struct {
+	// Workers cantidad de jobs que se procesan en paralelo
+	Workers int
+	// QueueSize cantidad máxima de jobs en cola; al superarla se rechazan con JOB_QUEUE_FULL
+	QueueSize int
+	// ResultTTL tiempo que se conserva un job (y su resultado) después de terminar
+	ResultTTL time.Duration
+	// JanitorInterval cada cuánto se eliminan los jobs expirados
+	JanitorInterval time.Duration
+	// Notifier envía los webhooks de los jobs con callbackUrl; sin él se rechazan los callbacks
+	Notifier *Notifier
+}
+
+// task trabajo pendiente en la cola; la matriz y el token solo viven en memoria
+type task struct {
+	ctx    context.Context
+	id     string
+	matrix [][]float64
+	opts   services.ProcessOptions
+}
+
+// running job en ejecución y cómo cancelarlo
+type running struct {
+	cancel context.CancelFunc
+	// interrupted indica que la cancelación vino del apagado del servidor y no del usuario
+	interrupted bool
+}
+
+// Manager ejecuta jobs de matrices en un pool acotado de workers y guarda su estado en un Store
+type Manager struct {
+	store     Store
+	processor *services.MatrixProcessor
+	cfg       Config
+	now       func() time.Time
+
+	queue chan task
+	quit  chan struct{}
+	wg    sync.WaitGroup
+	// ctx se cancela si Stop vence antes de terminar (corta los reintentos de webhooks)
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mu serializa las transiciones de estado (encolar, iniciar, progresar, terminar, cancelar)
+	mu      sync.Mutex
+	running map[string]*running
+	// stopping se activa (con mu) antes de que Stop espere en wg: desde entonces solo los
+	// workers, que mantienen wg > 0, pueden agregar entregas de webhooks
+	stopping bool
+}
+
+// NewManager crea un manager; los workers no arrancan hasta llamar a Start
+func NewManager(store Store, processor *services.MatrixProcessor, cfg Config) *Manager {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = 1
+	}
+	if cfg.JanitorInterval <= 0 {
+		cfg.JanitorInterval = time.Minute
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		store:     store,
+		processor: processor,
+		cfg:       cfg,
+		now:       time.Now,
+		queue:     make(chan task, cfg.QueueSize),
+		quit:      make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+		running:   make(map[string]*running),
+	}
+}
+
+// Store retorna el store de jobs (usado por el readiness check)
+func (m *Manager) Store() Store {
+	return m.store
+}
+
+// Start marca como fallidos los jobs que quedaron a medias en una ejecución anterior
+// y arranca los workers y el limpiador de jobs expirados
+func (m *Manager) Start(ctx context.Context) error {
+	stale, err := m.store.ListByStatus(ctx, models.JobStatusQueued, models.JobStatusRunning)
+	if err != nil {
+		return err
+	}
+	for _, job := range stale {
+		m.finish(job, nil, apperrors.New(apperrors.CodeJobInterrupted, nil), apperrors.DefaultLanguage)
+		if err := m.store.Update(ctx, job); err != nil {
+			return err
+		}
+		m.notify(job)
+	}
+	if len(stale) > 0 {
+		slog.Warn("marked interrupted jobs as failed", "count", len(stale))
+	}
+
+	for i := 0; i < m.cfg.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	m.wg.Add(1)
+	go m.janitor()
+	return nil
+}
+
+// Stop deja de tomar jobs de la cola y espera a que terminen los que están en ejecución
+// y las entregas de webhooks pendientes. Si ctx expira antes, cancela los jobs (quedan
+// como fallidos con JOB_INTERRUPTED) y los reintentos de webhooks.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	m.stopping = true
+	m.mu.Unlock()
+	close(m.quit)
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		m.cancel()
+		m.mu.Lock()
+		for _, r := range m.running {
+			r.interrupted = true
+			r.cancel()
+		}
+		m.mu.Unlock()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Submit valida la petición y encola un job nuevo del usuario ownerID.
+// token se reenvía a Node.js y lang define el idioma del error si el job falla.
+func (m *Manager) Submit(ctx context.Context, ownerID int, req models.JobRequest, token, lang string) (*models.Job, error) {
+	operation := req.Operation
+	if operation == "" {
+		operation = models.JobOperationProcess
+	}
+	if operation != models.JobOperationProcess && operation != models.JobOperationQR {
+		return nil, apperrors.New(apperrors.CodeJobInvalidOperation, map[string]interface{}{"operation": operation})
+	}
+	// La matriz se valida al encolar para rechazar con 400 en vez de crear un job condenado a fallar
+	if err := services.ValidateMatrix(req.Matrix); err != nil {
+		return nil, err
+	}
+	if err := services.ValidateQRShape(len(req.Matrix), len(req.Matrix[0])); err != nil {
+		return nil, err
+	}
+	if req.CallbackURL != "" {
+		if m.cfg.Notifier == nil || len(m.cfg.Notifier.Secret) == 0 {
+			return nil, apperrors.Wrap(apperrors.CodeServerMisconfigured, errors.New("WEBHOOK_SECRET no está configurado"))
+		}
+		if err := m.cfg.Notifier.ValidateCallbackURL(req.CallbackURL); err != nil {
+			return nil, apperrors.Wrap(apperrors.CodeJobInvalidCallback, err)
+		}
+	}
+
+	now := m.now().UTC()
+	job := &models.Job{
+		ID:          uuid.NewString(),
+		OwnerID:     ownerID,
+		Operation:   operation,
+		Status:      models.JobStatusQueued,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(m.cfg.ResultTTL),
+		CallbackURL: req.CallbackURL,
+	}
+
+	// Solo Submit escribe en la cola y lo hace con mu tomado, así que si hay
+	// lugar al comprobarlo el envío no bloquea
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.queue) == cap(m.queue) {
+		return nil, apperrors.New(apperrors.CodeJobQueueFull, nil)
+	}
+	if err := m.store.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	m.queue <- task{
+		// El job sobrevive a la petición, pero conserva sus valores (request ID para los logs)
+		ctx:    context.WithoutCancel(ctx),
+		id:     job.ID,
+		matrix: req.Matrix,
+		opts: services.ProcessOptions{
+			Token:         token,
+			Language:      lang,
+			SkipNodeStats: operation == models.JobOperationQR,
+		},
+	}
+	metrics.JobsQueueDepth.Set(float64(len(m.queue)))
+	return job, nil
+}
+
+// Get retorna el job si existe, no expiró y pertenece a ownerID; un job en cola o en
+// ejecución no expira aunque supere ResultTTL.
+// Los jobs de otros usuarios se reportan como inexistentes para no revelar sus ids.
+func (m *Manager) Get(ctx context.Context, ownerID int, id string) (*models.Job, error) {
+	job, err := m.store.Get(ctx, id)
+	if errors.Is(err, ErrNotFound) || (err == nil && (job.OwnerID != ownerID || (job.Finished() && job.ExpiresAt.Before(m.now())))) {
+		return nil, apperrors.New(apperrors.CodeJobNotFound, map[string]interface{}{"id": id})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Cancel cancela un job en cola o en ejecución; los jobs terminados retornan JOB_NOT_CANCELABLE
+func (m *Manager) Cancel(ctx context.Context, ownerID int, id string) (*models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.Get(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Finished() {
+		return nil, apperrors.New(apperrors.CodeJobNotCancelable, map[string]interface{}{"id": id, "status": job.Status})
+	}
+
+	if r, ok := m.running[id]; ok {
+		r.cancel()
+	}
+	m.finish(job, nil, apperrors.New(apperrors.CodeJobCanceled, nil), apperrors.DefaultLanguage)
+	if err := m.store.Update(ctx, job); err != nil {
+		return nil, err
+	}
+	// Con Stop ya esperando en wg, un wg.Add desde aquí (fuera de un worker) podría ocurrir
+	// con el contador en cero, lo que viola el contrato de sync.WaitGroup
+	if m.stopping {
+		if job.CallbackURL != "" {
+			slog.WarnContext(ctx, "webhook skipped: job manager is stopping", "job_id", job.ID)
+		}
+		return job, nil
+	}
+	m.notify(job)
+	return job, nil
+}
+
+// worker toma jobs de la cola hasta que se llame a Stop
+func (m *Manager) worker() {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.quit:
+			return
+		case t := <-m.queue:
+			metrics.JobsQueueDepth.Set(float64(len(m.queue)))
+			m.run(t)
+		}
+	}
+}
+
+// run ejecuta un job y guarda su resultado
+func (m *Manager) run(t task) {
+	ctx, cancel := context.WithCancel(t.ctx)
+	defer cancel()
+
+	// Pasar a running; si fue cancelado mientras esperaba en cola se descarta
+	m.mu.Lock()
+	job, err := m.store.Get(ctx, t.id)
+	if err != nil || job.Status != models.JobStatusQueued {
+		m.mu.Unlock()
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			slog.ErrorContext(ctx, "failed to load job", "job_id", t.id, "error", err)
+		}
+		return
+	}
+	started := m.now().UTC()
+	job.Status = models.JobStatusRunning
+	job.StartedAt = &started
+	r := &running{cancel: cancel}
+	m.running[t.id] = r
+	err = m.store.Update(ctx, job)
+	m.mu.Unlock()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update job", "job_id", t.id, "error", err)
+	}
+
+	opts := t.opts
+	opts.Progress = func(progress float64) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		job.Progress = progress
+		if err := m.store.Update(ctx, job); err != nil {
+			slog.ErrorContext(ctx, "failed to update job progress", "job_id", t.id, "error", err)
+		}
+	}
+	result, procErr := m.processor.Process(ctx, t.matrix, opts)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.running, t.id)
+	if ctx.Err() != nil && !r.interrupted {
+		// Cancel ya guardó el estado canceled
+		return
+	}
+	if r.interrupted {
+		procErr = apperrors.New(apperrors.CodeJobInterrupted, nil)
+		result = nil
+	}
+	m.finish(job, result, procErr, opts.Language)
+	// ctx puede estar cancelado (apagado): el estado final se guarda igual
+	if err := m.store.Update(context.WithoutCancel(ctx), job); err != nil {
+		slog.ErrorContext(ctx, "failed to save job result", "job_id", t.id, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "job finished", "job_id", t.id, "status", job.Status)
+	m.notify(job)
+}
+
+// notify envía en segundo plano el job terminado a su callbackUrl y guarda
+// cada intento en job.Deliveries. Se llama con mu tomado después de guardar el estado
+// final, y después de Stop solo desde un worker (ver stopping).
+func (m *Manager) notify(job *models.Job) {
+	if job.CallbackURL == "" || m.cfg.Notifier == nil {
+		return
+	}
+	payload, err := json.Marshal(job)
+	if err != nil {
+		slog.Error("failed to encode webhook payload", "job_id", job.ID, "error", err)
+		return
+	}
+
+	id, callbackURL, event := job.ID, job.CallbackURL, "job."+job.Status
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.cfg.Notifier.Deliver(m.ctx, callbackURL, event, id, payload, func(delivery models.WebhookDelivery) {
+			if !delivery.Success {
+				slog.Warn("webhook delivery failed", "job_id", id, "attempt", delivery.Attempt, "error", delivery.Error)
+			}
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			// El job puede haber expirado mientras se reintentaba
+			stored, err := m.store.Get(context.Background(), id)
+			if err != nil {
+				return
+			}
+			stored.Deliveries = append(stored.Deliveries, delivery)
+			if err := m.store.Update(context.Background(), stored); err != nil {
+				slog.Error("failed to save webhook delivery", "job_id", id, "error", err)
+			}
+		})
+	}()
+}
+
+// finish completa los campos de un job terminado: estado, resultado o error y nueva expiración
+func (m *Manager) finish(job *models.Job, result *models.MatrixProcessResponse, err error, lang string) {
+	finished := m.now().UTC()
+	job.FinishedAt = &finished
+	job.ExpiresAt = finished.Add(m.cfg.ResultTTL)
+
+	switch {
+	case err == nil:
+		job.Status = models.JobStatusSucceeded
+		job.Progress = 1
+		job.Result = result
+	case errors.Is(err, apperrors.New(apperrors.CodeJobCanceled, nil)):
+		job.Status = models.JobStatusCanceled
+	default:
+		job.Status = models.JobStatusFailed
+		problem := apperrors.From(err).Problem(lang)
+		job.Error = &problem
+	}
+	metrics.JobsTotal.WithLabelValues(job.Status).Inc()
+}
+
+// janitor elimina periódicamente los jobs expirados
+func (m *Manager) janitor() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.cfg.JanitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.quit:
+			return
+		case <-ticker.C:
+			deleted, err := m.store.DeleteExpired(context.Background(), m.now())
+			if err != nil {
+				slog.Error("failed to delete expired jobs", "error", err)
+			} else if deleted > 0 {
+				slog.Debug("deleted expired jobs", "count", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+	"go-api/internal/services"
+)
+
+// newTestManager crea un manager en memoria con un worker; nodeURL puede ser un servidor de prueba
+func newTestManager(t *testing.T, nodeURL string, cfg Config) (*Manager, Store) {
+	t.Helper()
+	store := NewMemoryStore()
+	nodeClient := services.NewNodeClient(nodeURL)
+	nodeClient.MaxRetries = 0
+	m := NewManager(store, services.NewMatrixProcessor(nodeClient), cfg)
+	return m, store
+}
+
+// waitForStatus espera a que el job llegue a alguno de los estados dados
+func waitForStatus(t *testing.T, m *Manager, ownerID int, id string, statuses ...string) *models.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := m.Get(context.Background(), ownerID, id)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		for _, status := range statuses {
+			if job.Status == status {
+				return job
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("el job %s no llegó a %v", id, statuses)
+	return nil
+}
+
+// blockingNode simula un Node.js que no responde hasta que se cancela la petición
+func blockingNode(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Leer el cuerpo para que el servidor detecte el cierre de la conexión
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestManager_SubmitAndComplete(t *testing.T) {
+	m, _ := newTestManager(t, "http://localhost:9999", Config{Workers: 2, QueueSize: 10, ResultTTL: time.Hour})
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(context.Background())
+
+	job, err := m.Submit(context.Background(), 1, models.JobRequest{
+		Operation: models.JobOperationQR,
+		Matrix:    [][]float64{{1, 2}, {3, 4}},
+	}, "token", "es")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if job.Status != models.JobStatusQueued {
+		t.Errorf("Status inicial = %s, want queued", job.Status)
+	}
+
+	done := waitForStatus(t, m, 1, job.ID, models.JobStatusSucceeded, models.JobStatusFailed)
+	if done.Status != models.JobStatusSucceeded {
+		t.Fatalf("Status = %s, error = %+v", done.Status, done.Error)
+	}
+	if done.Progress != 1 || done.Result == nil || len(done.Result.Q) != 2 {
+		t.Errorf("resultado incompleto: %+v", done)
+	}
+	if done.Result.NodeStats != nil {
+		t.Error("la operación qr no debe llamar a Node.js")
+	}
+	if done.StartedAt == nil || done.FinishedAt == nil {
+		t.Error("StartedAt y FinishedAt deben estar definidos")
+	}
+}
+
+func TestManager_SubmitErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      models.JobRequest
+		wantCode apperrors.Code
+	}{
+		{
+			name:     "operación desconocida",
+			req:      models.JobRequest{Operation: "svd", Matrix: [][]float64{{1}}},
+			wantCode: apperrors.CodeJobInvalidOperation,
+		},
+		{
+			name:     "matriz vacía",
+			req:      models.JobRequest{},
+			wantCode: apperrors.CodeMatrixEmpty,
+		},
+		{
+			name:     "matriz no rectangular",
+			req:      models.JobRequest{Matrix: [][]float64{{1, 2}, {3}}},
+			wantCode: apperrors.CodeMatrixNotRectangular,
+		},
+		{
+			name:     "matriz más ancha que alta",
+			req:      models.JobRequest{Operation: models.JobOperationQR, Matrix: [][]float64{{1, 2}}},
+			wantCode: apperrors.CodeMatrixWiderThanTall,
+		},
+	}
+
+	m, _ := newTestManager(t, "http://localhost:9999", Config{Workers: 1, QueueSize: 1, ResultTTL: time.Hour})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.Submit(context.Background(), 1, tt.req, "", "es")
+			if !errors.Is(err, apperrors.New(tt.wantCode, nil)) {
+				t.Errorf("err = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestManager_QueueFull(t *testing.T) {
+	// Sin Start no hay workers: la cola se llena
+	m, _ := newTestManager(t, "http://localhost:9999", Config{Workers: 1, QueueSize: 2, ResultTTL: time.Hour})
+	req := models.JobRequest{Matrix: [][]float64{{1}}}
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.Submit(context.Background(), 1, req, "", "es"); err != nil {
+			t.Fatalf("Submit %d: %v", i, err)
+		}
+	}
+	_, err := m.Submit(context.Background(), 1, req, "", "es")
+	if !errors.Is(err, apperrors.New(apperrors.CodeJobQueueFull, nil)) {
+		t.Errorf("err = %v, want JOB_QUEUE_FULL", err)
+	}
+}
+
+func TestManager_Ownership(t *testing.T) {
+	m, _ := newTestManager(t, "http://localhost:9999", Config{Workers: 1, QueueSize: 1, ResultTTL: time.Hour})
+	job, err := m.Submit(context.Background(), 1, models.JobRequest{Matrix: [][]float64{{1}}}, "", "es")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	notFound := apperrors.New(apperrors.CodeJobNotFound, nil)
+	if _, err := m.Get(context.Background(), 2, job.ID); !errors.Is(err, notFound) {
+		t.Errorf("Get de otro usuario: err = %v, want JOB_NOT_FOUND", err)
+	}
+	if _, err := m.Cancel(context.Background(), 2, job.ID); !errors.Is(err, notFound) {
+		t.Errorf("Cancel de otro usuario: err = %v, want JOB_NOT_FOUND", err)
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	t.Run("job en cola", func(t *testing.T) {
+		m, _ := newTestManager(t, "http://localhost:9999", Config{Workers: 1, QueueSize: 1, ResultTTL: time.Hour})
+		job, _ := m.Submit(context.Background(), 1, models.JobRequest{Matrix: [][]float64{{1}}}, "", "es")
+
+		canceled, err := m.Cancel(context.Background(), 1, job.ID)
+		if err != nil {
+			t.Fatalf("Cancel: %v", err)
+		}
+		if canceled.Status != models.JobStatusCanceled {
+			t.Errorf("Status = %s, want canceled", canceled.Status)
+		}
+
+		// Al arrancar, el worker descarta el job cancelado
+		m.Start(context.Background())
+		defer m.Stop(context.Background())
+		time.Sleep(20 * time.Millisecond)
+		if got, _ := m.Get(context.Background(), 1, job.ID); got.Status != models.JobStatusCanceled {
+			t.Errorf("Status tras arrancar = %s, want canceled", got.Status)
+		}
+
+		// Un job terminado no se puede cancelar otra vez
+		if _, err := m.Cancel(context.Background(), 1, job.ID); !errors.Is(err, apperrors.New(apperrors.CodeJobNotCancelable, nil)) {
+			t.Errorf("err = %v, want JOB_NOT_CANCELABLE", err)
+		}
+	})
+
+	t.Run("job en ejecución", func(t *testing.T) {
+		node := blockingNode(t)
+		m, _ := newTestManager(t, node.URL, Config{Workers: 1, QueueSize: 1, ResultTTL: time.Hour})
+		m.Start(context.Background())
+		defer m.Stop(context.Background())
+
+		job, _ := m.Submit(context.Background(), 1, models.JobRequest{Matrix: [][]float64{{1}}}, "", "es")
+		waitForStatus(t, m, 1, job.ID, models.JobStatusRunning)
+
+		if _, err := m.Cancel(context.Background(), 1, job.ID); err != nil {
+			t.Fatalf("Cancel: %v", err)
+		}
+		// Esperar a que el worker termine y verificar que no sobrescribió el estado
+		time.Sleep(50 * time.Millisecond)
+		got := waitForStatus(t, m, 1, job.ID, models.JobStatusCanceled, models.JobStatusSucceeded)
+		if got.Status != models.JobStatusCanceled || got.Result != nil {
+			t.Errorf("job = %+v, want canceled sin resultado", got)
+		}
+	})
+}
+
+func TestManager_CancelWhileStopping(t *testing.T) {
+	delivered := make(chan struct{}, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+	}))
+	defer receiver.Close()
+
+	notifier := NewNotifier([]byte("secreto"))
+	notifier.AllowedNetworks = loopback
+	m, _ := newTestManager(t, "http://localhost:9999", Config{Workers: 1, QueueSize: 1, ResultTTL: time.Hour, Notifier: notifier})
+	job, err := m.Submit(context.Background(), 1, models.JobRequest{Matrix: [][]float64{{1}}, CallbackURL: receiver.URL}, "", "es")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	// Sin Start no hay workers: Stop espera con el contador de wg en cero
+	if err := m.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	canceled, err := m.Cancel(context.Background(), 1, job.ID)
+	if err != nil || canceled.Status != models.JobStatusCanceled {
+		t.Fatalf("Cancel = %+v, %v, want canceled", canceled, err)
+	}
+	select {
+	case <-delivered:
+		t.Error("no se debería enviar el webhook después de Stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestManager_StartMarksInterruptedJobs(t *testing.T) {
+	m, store := newTestManager(t, "http://localhost:9999", Config{Workers: 1, QueueSize: 1, ResultTTL: time.Hour})
+	store.Create(context.Background(), &models.Job{
+		ID: "huerfano", OwnerID: 1, Status: models.JobStatusRunning, ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(context.Background())
+
+	job, err := m.Get(context.Background(), 1, "huerfano")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if job.Status != models.JobStatusFailed || job.Error == nil || job.Error.Code != string(apperrors.CodeJobInterrupted) {
+		t.Errorf("job = %+v, want failed con JOB_INTERRUPTED", job)
+	}
+}
+
+func TestManager_StopInterruptsRunningJobs(t *testing.T) {
+	node := blockingNode(t)
+	m, _ := newTestManager(t, node.URL, Config{Workers: 1, QueueSize: 1, ResultTTL: time.Hour})
+	m.Start(context.Background())
+
+	job, _ := m.Submit(context.Background(), 1, models.JobRequest{Matrix: [][]float64{{1}}}, "", "es")
+	waitForStatus(t, m, 1, job.ID, models.JobStatusRunning)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop: err = %v, want DeadlineExceeded", err)
+	}
+
+	got, _ := m.Get(context.Background(), 1, job.ID)
+	if got.Status != models.JobStatusFailed || got.Error == nil || got.Error.Code != string(apperrors.CodeJobInterrupted) {
+		t.Errorf("job = %+v, want failed con JOB_INTERRUPTED", got)
+	}
+}
+
+func TestManager_Expiry(t *testing.T) {
+	m, store := newTestManager(t, "http://localhost:9999", Config{Workers: 1, QueueSize: 1, ResultTTL: time.Minute})
+	job, _ := m.Submit(context.Background(), 1, models.JobRequest{Matrix: [][]float64{{1}}}, "", "es")
+
+	// Un job en cola que supera el TTL sigue visible y el janitor no lo elimina
+	m.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, err := m.Get(context.Background(), 1, job.ID); err != nil {
+		t.Fatalf("Get del job en cola: %v", err)
+	}
+	if deleted, _ := store.DeleteExpired(context.Background(), m.now()); deleted != 0 {
+		t.Errorf("DeleteExpired = %d, want 0", deleted)
+	}
+
+	// Al terminar, el TTL se cuenta desde FinishedAt
+	if _, err := m.Cancel(context.Background(), 1, job.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if _, err := m.Get(context.Background(), 1, job.ID); err != nil {
+		t.Errorf("Get recién terminado: %v", err)
+	}
+
+	// Avanzar el reloj más allá del TTL: el job deja de ser visible aunque el janitor no haya corrido
+	m.now = func() time.Time { return time.Now().Add(4 * time.Minute) }
+	if _, err := m.Get(context.Background(), 1, job.ID); !errors.Is(err, apperrors.New(apperrors.CodeJobNotFound, nil)) {
+		t.Errorf("err = %v, want JOB_NOT_FOUND", err)
+	}
+}
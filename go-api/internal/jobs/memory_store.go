@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go-api/internal/models"
+)
+
+// MemoryStore implementación en memoria de Store (los jobs se pierden al reiniciar)
+type MemoryStore struct {
+	mu   sync.RWMutex
+	jobs map[string][]byte
+}
+
+// NewMemoryStore crea un store en memoria
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs: make(map[string][]byte),
+	}
+}
+
+// Los jobs se guardan serializados para que el llamador nunca comparta
+// memoria (matrices del resultado) con el store
+
+func (s *MemoryStore) Create(ctx context.Context, job *models.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = data
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id string) (*models.Job, error) {
+	s.mu.RLock()
+	data, ok := s.jobs[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var job models.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, job *models.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.ID]; !ok {
+		return ErrNotFound
+	}
+	s.jobs[job.ID] = data
+	return nil
+}
+
+func (s *MemoryStore) ListByStatus(ctx context.Context, statuses ...string) ([]*models.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []*models.Job
+	for _, data := range s.jobs {
+		var job models.Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			return nil, err
+		}
+		for _, status := range statuses {
+			if job.Status == status {
+				result = append(result, &job)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for id, data := range s.jobs {
+		var job models.Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			return deleted, err
+		}
+		if job.Finished() && job.ExpiresAt.Before(now) {
+			delete(s.jobs, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
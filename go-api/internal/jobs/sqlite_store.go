@@ -0,0 +1,142 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-api/internal/models"
+
+	_ "modernc.org/sqlite" // driver SQLite en Go puro (compatible con CGO_ENABLED=0)
+)
+
+// sqliteSchema tabla de jobs: el job completo se guarda como JSON en data;
+// status y expires_at se duplican en columnas para poder filtrar
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id         TEXT PRIMARY KEY,
+	status     TEXT NOT NULL,
+	expires_at INTEGER NOT NULL,
+	data       BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS jobs_expires_at ON jobs (expires_at);
+CREATE INDEX IF NOT EXISTS jobs_status ON jobs (status);
+`
+
+// SQLiteStore implementación de Store sobre SQLite (los jobs sobreviven a reinicios)
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore abre (o crea) la base de datos en path y aplica el esquema
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path))
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir SQLite: %w", err)
+	}
+	// SQLite admite un único escritor: serializar el acceso evita errores SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al crear esquema de jobs: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Create(ctx context.Context, job *models.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO jobs (id, status, expires_at, data) VALUES (?, ?, ?, ?)`,
+		job.ID, job.Status, job.ExpiresAt.UnixNano(), data)
+	return err
+}
+
+func (s *SQLiteStore) Get(ctx context.Context, id string) (*models.Job, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM jobs WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job models.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *SQLiteStore) Update(ctx context.Context, job *models.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = ?, expires_at = ?, data = ? WHERE id = ?`,
+		job.Status, job.ExpiresAt.UnixNano(), data, job.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListByStatus(ctx context.Context, statuses ...string) ([]*models.Job, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(statuses)), ",")
+	args := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		args[i] = status
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM jobs WHERE status IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.Job
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var job models.Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			return nil, err
+		}
+		result = append(result, &job)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteStore) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE expires_at < ? AND status NOT IN (?, ?)`,
+		now.UnixNano(), models.JobStatusQueued, models.JobStatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
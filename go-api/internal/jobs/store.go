@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-api/internal/models"
+)
+
+// ErrNotFound se retorna cuando el job no existe (o ya expiró)
+var ErrNotFound = errors.New("job no encontrado")
+
+// Store persiste el estado de los jobs. Implementaciones: MemoryStore y SQLiteStore.
+// Las implementaciones deben ser seguras para uso concurrente y no compartir
+// memoria con el llamador (Get retorna copias).
+type Store interface {
+	// Create guarda un job nuevo
+	Create(ctx context.Context, job *models.Job) error
+	// Get retorna el job con el id dado o ErrNotFound
+	Get(ctx context.Context, id string) (*models.Job, error)
+	// Update reemplaza el estado de un job existente o retorna ErrNotFound
+	Update(ctx context.Context, job *models.Job) error
+	// ListByStatus retorna los jobs en alguno de los estados dados
+	ListByStatus(ctx context.Context, statuses ...string) ([]*models.Job, error)
+	// DeleteExpired elimina los jobs terminados con ExpiresAt anterior a now y retorna cuántos
+	// eliminó; los jobs en cola o en ejecución se conservan aunque hayan superado ExpiresAt
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+	// Ping verifica que el store esté disponible (usado por /readyz)
+	Ping(ctx context.Context) error
+	// Close libera los recursos del store
+	Close() error
+}
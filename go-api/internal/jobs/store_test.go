@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-api/internal/models"
+)
+
+// stores retorna una instancia nueva de cada implementación de Store
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+	sqlite, err := NewSQLiteStore(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { sqlite.Close() })
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": sqlite,
+	}
+}
+
+func TestStore_CRUD(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			job := &models.Job{
+				ID:        "job-1",
+				OwnerID:   7,
+				Operation: models.JobOperationQR,
+				Status:    models.JobStatusQueued,
+				CreatedAt: now,
+				ExpiresAt: now.Add(time.Hour),
+			}
+			if err := store.Create(ctx, job); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			job.Status = models.JobStatusSucceeded
+			job.Progress = 1
+			job.Result = &models.MatrixProcessResponse{Q: [][]float64{{1}}, R: [][]float64{{2}}}
+			if err := store.Update(ctx, job); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+
+			got, err := store.Get(ctx, "job-1")
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if got.OwnerID != 7 || got.Status != models.JobStatusSucceeded || got.Result == nil || got.Result.R[0][0] != 2 {
+				t.Errorf("Get = %+v, no coincide con lo guardado", got)
+			}
+			if !got.CreatedAt.Equal(now) {
+				t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, now)
+			}
+
+			// Modificar la copia retornada no debe afectar al store
+			got.Status = models.JobStatusFailed
+			again, _ := store.Get(ctx, "job-1")
+			if again.Status != models.JobStatusSucceeded {
+				t.Error("Get debe retornar una copia independiente")
+			}
+
+			if _, err := store.Get(ctx, "no-existe"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get inexistente: err = %v, want ErrNotFound", err)
+			}
+			if err := store.Update(ctx, &models.Job{ID: "no-existe"}); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Update inexistente: err = %v, want ErrNotFound", err)
+			}
+			if err := store.Ping(ctx); err != nil {
+				t.Errorf("Ping: %v", err)
+			}
+		})
+	}
+}
+
+func TestStore_ListByStatusAndDeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			jobs := []*models.Job{
+				{ID: "queued", Status: models.JobStatusQueued, ExpiresAt: now.Add(time.Hour)},
+				{ID: "running", Status: models.JobStatusRunning, ExpiresAt: now.Add(-time.Minute)},
+				{ID: "expired", Status: models.JobStatusSucceeded, ExpiresAt: now.Add(-time.Minute)},
+			}
+			for _, job := range jobs {
+				if err := store.Create(ctx, job); err != nil {
+					t.Fatalf("Create: %v", err)
+				}
+			}
+
+			pending, err := store.ListByStatus(ctx, models.JobStatusQueued, models.JobStatusRunning)
+			if err != nil {
+				t.Fatalf("ListByStatus: %v", err)
+			}
+			if len(pending) != 2 {
+				t.Errorf("ListByStatus retornó %d jobs, want 2", len(pending))
+			}
+
+			deleted, err := store.DeleteExpired(ctx, now)
+			if err != nil {
+				t.Fatalf("DeleteExpired: %v", err)
+			}
+			if deleted != 1 {
+				t.Errorf("DeleteExpired = %d, want 1", deleted)
+			}
+			if _, err := store.Get(ctx, "expired"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("el job expirado sigue en el store (err = %v)", err)
+			}
+			if _, err := store.Get(ctx, "running"); err != nil {
+				t.Errorf("un job en ejecución no expira (err = %v)", err)
+			}
+		})
+	}
+}
+
+// TestSQLiteStore_Persistence verifica que los jobs sobrevivan a reabrir la base de datos
+func TestSQLiteStore_Persistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs.db")
+
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	if err := store.Create(ctx, &models.Job{ID: "persistente", Status: models.JobStatusQueued, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	store.Close()
+
+	reopened, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore (reabrir): %v", err)
+	}
+	defer reopened.Close()
+	if _, err := reopened.Get(ctx, "persistente"); err != nil {
+		t.Errorf("Get después de reabrir: %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go-api/internal/metrics"
+	"go-api/internal/models"
+)
+
+// Headers de las notificaciones webhook
+const (
+	// SignatureHeader firma HMAC-SHA256 en hexadecimal con el formato "sha256=<hex>"
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader momento del envío en segundos Unix (forma parte de lo firmado)
+	TimestampHeader = "X-Webhook-Timestamp"
+	// EventHeader evento notificado: job.succeeded, job.failed o job.canceled
+	EventHeader = "X-Webhook-Event"
+	// JobIDHeader id del job notificado
+	JobIDHeader = "X-Job-ID"
+)
+
+// Sign calcula la firma de un webhook: HMAC-SHA256 de "<timestamp>.<body>" con el secreto.
+// Incluir el timestamp permite al receptor rechazar envíos repetidos antiguos.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature comprueba en tiempo constante que signature corresponda al timestamp y body
+func VerifySignature(secret []byte, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// ErrForbiddenAddress se retorna al intentar entregar un webhook a una dirección de red
+// interna (loopback, privada, link-local, etc.) que no está en AllowedNetworks
+var ErrForbiddenAddress = errors.New("la dirección de destino no está permitida")
+
+// forbiddenNetworks rangos no enrutables públicamente que no cubren los métodos de netip.Addr
+var forbiddenNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// Notifier envía el resultado de los jobs terminados a su callbackUrl,
+// firmado con HMAC-SHA256 y con reintentos con backoff exponencial
+type Notifier struct {
+	Secret     []byte
+	HTTPClient *http.Client
+	// MaxAttempts cantidad máxima de envíos (incluye el primero)
+	MaxAttempts int
+	// Backoff espera antes del primer reintento; se duplica en cada intento
+	Backoff time.Duration
+	// AllowedNetworks redes internas a las que sí se permite entregar (desarrollo y tests);
+	// el resto de las direcciones no públicas se rechaza para evitar SSRF
+	AllowedNetworks []netip.Prefix
+}
+
+// NewNotifier crea un notifier con 5 intentos y backoff inicial de 1s. Su cliente HTTP no
+// usa proxy y verifica cada IP a la que se conecta, ya resuelta: así cubre también las
+// redirecciones y un DNS que cambie entre la validación y la conexión (DNS rebinding).
+func NewNotifier(secret []byte) *Notifier {
+	n := &Notifier{
+		Secret:      secret,
+		MaxAttempts: 5,
+		Backoff:     time.Second,
+	}
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			return n.checkAddr(addr)
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	n.HTTPClient = &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+	}
+	return n
+}
+
+// ValidateCallbackURL verifica que la URL de callback sea absoluta y http(s) y, si el host
+// es una IP literal, que sea pública o de AllowedNetworks. Los nombres se verifican al
+// conectar, con la IP resuelta.
+func (n *Notifier) ValidateCallbackURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("se esperaba una URL http(s) absoluta")
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil {
+		return n.checkAddr(addr)
+	}
+	return nil
+}
+
+// checkAddr rechaza con ErrForbiddenAddress las direcciones que no son unicast públicas
+// (loopback, privadas, link-local como 169.254.169.254, etc.) salvo las de AllowedNetworks
+func (n *Notifier) checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	for _, network := range n.AllowedNetworks {
+		if network.Contains(addr) {
+			return nil
+		}
+	}
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+	for _, network := range forbiddenNetworks {
+		if network.Contains(addr) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+		}
+	}
+	return nil
+}
+
+// Deliver envía payload a callbackURL hasta obtener una respuesta 2xx, agotar los intentos,
+// recibir un error permanente (4xx salvo 408 y 429) o cancelarse ctx.
+// record se invoca después de cada intento para guardar el registro de entregas.
+func (n *Notifier) Deliver(ctx context.Context, callbackURL, event, jobID string, payload []byte, record func(models.WebhookDelivery)) {
+	backoff := n.Backoff
+	for attempt := 1; attempt <= n.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		delivery, retry := n.send(ctx, callbackURL, event, jobID, payload)
+		delivery.Attempt = attempt
+		record(delivery)
+
+		outcome := "error"
+		if delivery.Success {
+			outcome = "ok"
+		}
+		metrics.WebhookDeliveriesTotal.WithLabelValues(outcome).Inc()
+		if !retry {
+			return
+		}
+	}
+}
+
+// send realiza un intento de entrega; retry indica si vale la pena reintentar
+func (n *Notifier) send(ctx context.Context, callbackURL, event, jobID string, payload []byte) (delivery models.WebhookDelivery, retry bool) {
+	now := time.Now().UTC()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	delivery.Timestamp = now
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(payload))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery, false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(n.Secret, timestamp, payload))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(JobIDHeader, jobID)
+
+	resp, err := n.HTTPClient.Do(req)
+	delivery.DurationMs = float64(time.Since(now).Microseconds()) / 1000
+	if err != nil {
+		delivery.Error = err.Error()
+		// Una dirección prohibida no cambia con los reintentos
+		return delivery, ctx.Err() == nil && !errors.Is(err, ErrForbiddenAddress)
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		delivery.Success = true
+		return delivery, false
+	}
+	delivery.Error = fmt.Sprintf("el receptor respondió %d", resp.StatusCode)
+	retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return delivery, retryable
+}
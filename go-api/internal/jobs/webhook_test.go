@@ -0,0 +1,148 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api/internal/models"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("secreto")
+	body := []byte(`{"id":"job-1"}`)
+	signature := Sign(secret, "1700000000", body)
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		timestamp string
+		body      []byte
+		want      bool
+	}{
+		{name: "firma válida", secret: secret, timestamp: "1700000000", body: body, want: true},
+		{name: "secreto distinto", secret: []byte("otro"), timestamp: "1700000000", body: body, want: false},
+		{name: "timestamp alterado", secret: secret, timestamp: "1700000001", body: body, want: false},
+		{name: "cuerpo alterado", secret: secret, timestamp: "1700000000", body: []byte(`{"id":"job-2"}`), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(tt.secret, tt.timestamp, tt.body, signature); got != tt.want {
+				t.Errorf("VerifySignature = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifier_Deliver(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int // respuesta del receptor en cada intento
+		wantAttempts int
+		wantSuccess  bool
+	}{
+		{name: "éxito al primer intento", statuses: []int{200}, wantAttempts: 1, wantSuccess: true},
+		{name: "reintenta ante 5xx y 429", statuses: []int{500, 429, 202}, wantAttempts: 3, wantSuccess: true},
+		{name: "no reintenta ante 4xx", statuses: []int{400}, wantAttempts: 1, wantSuccess: false},
+		{name: "agota los intentos", statuses: []int{502, 502, 502}, wantAttempts: 3, wantSuccess: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[calls])
+				calls++
+			}))
+			defer receiver.Close()
+
+			notifier := NewNotifier([]byte("secreto"))
+			notifier.AllowedNetworks = loopback
+			notifier.MaxAttempts = 3
+			notifier.Backoff = time.Millisecond
+
+			var log []models.WebhookDelivery
+			notifier.Deliver(context.Background(), receiver.URL, "job.succeeded", "job-1", []byte(`{}`), func(d models.WebhookDelivery) {
+				log = append(log, d)
+			})
+
+			if len(log) != tt.wantAttempts {
+				t.Fatalf("intentos = %d, want %d", len(log), tt.wantAttempts)
+			}
+			last := log[len(log)-1]
+			if last.Success != tt.wantSuccess || last.Attempt != tt.wantAttempts {
+				t.Errorf("último intento = %+v, want success %v", last, tt.wantSuccess)
+			}
+		})
+	}
+}
+
+// loopback redes de los receptores httptest, permitidas explícitamente en los tests
+var loopback = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+
+func TestNotifier_ValidateCallbackURL(t *testing.T) {
+	notifier := NewNotifier([]byte("secreto"))
+	tests := []struct {
+		url       string
+		forbidden bool
+		invalid   bool
+	}{
+		{url: "https://example.com/hooks"},
+		{url: "http://93.184.216.34:8080/hooks"},
+		{url: "/hooks", invalid: true},
+		{url: "ftp://example.com/hook", invalid: true},
+		{url: "http://127.0.0.1:3000/hooks", forbidden: true},
+		{url: "http://[::1]/hooks", forbidden: true},
+		{url: "http://169.254.169.254/latest/meta-data/", forbidden: true},
+		{url: "http://10.0.0.5/hooks", forbidden: true},
+		{url: "http://192.168.1.10/hooks", forbidden: true},
+		{url: "http://[fd00:ec2::254]/hooks", forbidden: true},
+		{url: "http://[::ffff:127.0.0.1]/hooks", forbidden: true},
+		{url: "http://0.0.0.0/hooks", forbidden: true},
+		{url: "http://100.64.0.1/hooks", forbidden: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := notifier.ValidateCallbackURL(tt.url)
+			if got := errors.Is(err, ErrForbiddenAddress); got != tt.forbidden || (err != nil) != (tt.forbidden || tt.invalid) {
+				t.Errorf("ValidateCallbackURL(%q) = %v, want prohibida %v, inválida %v", tt.url, err, tt.forbidden, tt.invalid)
+			}
+		})
+	}
+
+	notifier.AllowedNetworks = loopback
+	if err := notifier.ValidateCallbackURL("http://127.0.0.1:3000/hooks"); err != nil {
+		t.Errorf("con loopback permitida: err = %v, want nil", err)
+	}
+}
+
+func TestNotifier_DeliverBlocksInternalAddresses(t *testing.T) {
+	calls := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer receiver.Close()
+
+	// localhost no es una IP literal: pasa la validación pero se bloquea al conectar
+	callbackURL := strings.Replace(receiver.URL, "127.0.0.1", "localhost", 1)
+	notifier := NewNotifier([]byte("secreto"))
+	notifier.MaxAttempts = 3
+	notifier.Backoff = time.Millisecond
+	if err := notifier.ValidateCallbackURL(callbackURL); err != nil {
+		t.Fatalf("ValidateCallbackURL(%q) = %v", callbackURL, err)
+	}
+
+	var log []models.WebhookDelivery
+	notifier.Deliver(context.Background(), callbackURL, "job.succeeded", "job-1", []byte(`{}`), func(d models.WebhookDelivery) {
+		log = append(log, d)
+	})
+	if calls != 0 || len(log) != 1 || log[0].Success || !strings.Contains(log[0].Error, ErrForbiddenAddress.Error()) {
+		t.Errorf("entregas = %+v (receptor llamado %d veces), want un intento rechazado sin reintentos", log, calls)
+	}
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{input: "", want: slog.LevelInfo},
+		{input: "debug", want: slog.LevelDebug},
+		{input: "WARN", want: slog.LevelWarn},
+		{input: "error", want: slog.LevelError},
+		{input: "verbose", want: slog.LevelInfo, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLevel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_AddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo).With("component", "test")
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	logger.InfoContext(ctx, "hello")
+	logger.DebugContext(ctx, "filtered")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Se esperaba una única línea JSON, got %q: %v", buf.String(), err)
+	}
+	if line["request_id"] != "req-123" {
+		t.Errorf("request_id = %v, want req-123", line["request_id"])
+	}
+	if line["component"] != "test" {
+		t.Errorf("component = %v, want test (atributos de With deben conservarse)", line["component"])
+	}
+}
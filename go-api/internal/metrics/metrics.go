@@ -0,0 +1,211 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "goapi"
+
+// Registry contiene todas las métricas expuestas en /metrics.
+// Se usa un registry propio (en vez del global) para controlar exactamente qué se expone.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// HTTPRequestsTotal cuenta las peticiones HTTP por ruta, método y código de estado
+	HTTPRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total de peticiones HTTP por ruta, método y código de estado.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestDuration mide la latencia de las peticiones HTTP por ruta, método y código de estado
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latencia de las peticiones HTTP en segundos.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestsInFlight indica cuántas peticiones se están atendiendo en este momento
+	HTTPRequestsInFlight = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Peticiones HTTP en curso.",
+	})
+
+	// MatrixProcessTotal cuenta las matrices procesadas por resultado
+	// (ok, invalid_body, invalid_matrix, qr_error, node_error, qr_skipped)
+	MatrixProcessTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "matrix_process_total",
+		Help:      "Total de matrices procesadas por resultado.",
+	}, []string{"outcome"})
+
+	// QRDuration mide la duración de la factorización QR agrupada por tamaño de matriz
+	QRDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "qr_decomposition_duration_seconds",
+		Help:      "Duración de la factorización QR en segundos, por tamaño (cantidad de elementos).",
+		Buckets:   prometheus.ExponentialBuckets(0.00001, 4, 12),
+	}, []string{"size"})
+
+	// NodeRequestDuration mide la latencia de cada llamada a la API de Node.js
+	NodeRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "node_client_request_duration_seconds",
+		Help:      "Latencia de las llamadas a la API de Node.js en segundos.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	// NodeRequestErrors cuenta los errores de llamadas a Node.js por motivo
+	NodeRequestErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "node_client_errors_total",
+		Help:      "Total de errores en llamadas a la API de Node.js por motivo.",
+	}, []string{"operation", "reason"})
+
+	// NodeRequestRetries cuenta los reintentos de llamadas a Node.js
+	NodeRequestRetries = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "node_client_retries_total",
+		Help:      "Total de reintentos de llamadas a la API de Node.js.",
+	}, []string{"operation"})
+
+	// AuthFailuresTotal cuenta los fallos de autenticación JWT por motivo
+	AuthFailuresTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Total de fallos de autenticación por motivo.",
+	}, []string{"reason"})
+
+	// JobsQueueDepth indica cuántos jobs esperan un worker libre
+	JobsQueueDepth = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jobs_queue_depth",
+		Help:      "Jobs asíncronos en cola esperando un worker.",
+	})
+
+	// JobsTotal cuenta los jobs asíncronos terminados por estado final
+	// (succeeded, failed, canceled)
+	JobsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_total",
+		Help:      "Total de jobs asíncronos terminados por estado final.",
+	}, []string{"status"})
+
+	// WebhookDeliveriesTotal cuenta los intentos de entrega de webhooks por resultado (ok, error)
+	WebhookDeliveriesTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Total de intentos de entrega de webhooks por resultado.",
+	}, []string{"outcome"})
+
+	// SessionsActive indica cuántas sesiones interactivas (WebSocket) están abiertas
+	SessionsActive = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sessions_active",
+		Help:      "Sesiones interactivas de edición de matrices abiertas.",
+	})
+
+	// WorkspacesActive indica cuántos workspaces (factorizaciones guardadas en el servidor) existen
+	WorkspacesActive = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "workspaces_active",
+		Help:      "Workspaces con factorización QR guardada en el servidor.",
+	})
+
+	// WorkspaceOperationsTotal cuenta las operaciones aplicadas a workspaces por tipo
+	WorkspaceOperationsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workspace_operations_total",
+		Help:      "Total de operaciones de actualización aplicadas a workspaces por tipo.",
+	}, []string{"type"})
+
+	// CacheRequestsTotal cuenta las búsquedas en el cache de resultados por resultado (hit, miss)
+	CacheRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Total de búsquedas en el cache de resultados por resultado.",
+	}, []string{"result"})
+
+	// CacheEntries indica cuántos resultados hay en el cache en memoria
+	CacheEntries = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cache_entries",
+		Help:      "Resultados guardados en el cache en memoria.",
+	})
+
+	// CacheBytes indica el tamaño de los resultados del cache en memoria
+	CacheBytes = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cache_bytes",
+		Help:      "Tamaño en bytes de los resultados del cache en memoria.",
+	})
+
+	// IdempotencyRequestsTotal cuenta las peticiones con Idempotency-Key por resultado
+	// (new, replayed, mismatch, in_progress, rejected)
+	IdempotencyRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "idempotency_requests_total",
+		Help:      "Total de peticiones con Idempotency-Key por resultado.",
+	}, []string{"result"})
+
+	// IdempotencyKeys indica cuántas Idempotency-Key están guardadas
+	IdempotencyKeys = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "idempotency_keys",
+		Help:      "Idempotency-Key guardadas (en curso o con respuesta).",
+	})
+
+	// GRPCRequestsTotal cuenta las llamadas gRPC por método y código de estado gRPC
+	GRPCRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "Total de llamadas gRPC por método y código de estado.",
+	}, []string{"method", "code"})
+
+	// GRPCRequestDuration mide la duración de las llamadas gRPC (en los streams, la del stream completo)
+	GRPCRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Duración de las llamadas gRPC en segundos.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler retorna el handler HTTP que expone las métricas en formato Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// SizeBucket agrupa una matriz por cantidad de elementos para usarla como label
+// (evita una serie por cada combinación filas x columnas)
+func SizeBucket(rows, cols int) string {
+	n := rows * cols
+	switch {
+	case n <= 16:
+		return "le16"
+	case n <= 256:
+		return "le256"
+	case n <= 4096:
+		return "le4096"
+	case n <= 65536:
+		return "le65536"
+	default:
+		return "gt65536"
+	}
+}
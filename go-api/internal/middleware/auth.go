@@ -5,6 +5,9 @@ import (
 	"os"
 	"strings"
 
+	"go-api/internal/apperrors"
+	"go-api/internal/metrics"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -35,31 +38,41 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-// AuthenticateToken middleware para verificar token JWT
-func AuthenticateToken(c *fiber.Ctx) error {
+// authFailureReasons label de métrica para cada código de error de autenticación
+var authFailureReasons = map[apperrors.Code]string{
+	apperrors.CodeServerMisconfigured: "config",
+	apperrors.CodeTokenMissing:        "missing_token",
+	apperrors.CodeTokenMalformed:      "invalid_format",
+	apperrors.CodeTokenExpired:        "expired",
+	apperrors.CodeTokenInvalid:        "invalid_token",
+}
+
+// ParseBearerToken valida el valor de un header Authorization ("Bearer <token>") y retorna los claims.
+// Contiene la lógica de AuthenticateToken para poder reutilizarla en otros transportes.
+// Los errores son *apperrors.Error con códigos TOKEN_* o SERVER_MISCONFIGURED.
+func ParseBearerToken(authHeader string) (*Claims, error) {
+	claims, err := parseBearerToken(authHeader)
+	if err != nil {
+		metrics.AuthFailuresTotal.WithLabelValues(authFailureReasons[err.Code]).Inc()
+		return nil, err
+	}
+	return claims, nil
+}
+
+func parseBearerToken(authHeader string) (*Claims, *apperrors.Error) {
 	// Validar configuración antes de intentar verificar tokens
 	if _, err := getJWTSecret(); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Error de configuración del servidor",
-			"message": err.Error(),
-		})
+		return nil, apperrors.Wrap(apperrors.CodeServerMisconfigured, err)
 	}
 
-	authHeader := c.Get("Authorization")
 	if authHeader == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "Token de acceso requerido",
-			"message": "Agrega el header: Authorization: Bearer <token>",
-		})
+		return nil, apperrors.New(apperrors.CodeTokenMissing, nil)
 	}
 
 	// Extraer token del header "Bearer TOKEN"
 	parts := strings.Split(authHeader, " ")
 	if len(parts) != 2 || parts[0] != "Bearer" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "Formato de token inválido",
-			"message": "El formato debe ser: Bearer <token>",
-		})
+		return nil, apperrors.New(apperrors.CodeTokenMalformed, nil)
 	}
 
 	tokenString := parts[1]
@@ -71,11 +84,24 @@ func AuthenticateToken(c *fiber.Ctx) error {
 		return getJWTSecretBytes()
 	})
 
-	if err != nil || !token.Valid {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error":   "Token inválido o expirado",
-			"message": err.Error(),
-		})
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, apperrors.Wrap(apperrors.CodeTokenExpired, err)
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeTokenInvalid, err)
+	}
+	if !token.Valid {
+		return nil, apperrors.Wrap(apperrors.CodeTokenInvalid, errors.New("token no válido"))
+	}
+
+	return claims, nil
+}
+
+// AuthenticateToken middleware para verificar token JWT
+func AuthenticateToken(c *fiber.Ctx) error {
+	claims, err := ParseBearerToken(c.Get("Authorization"))
+	if err != nil {
+		return WriteProblem(c, err)
 	}
 
 	// Guardar información del usuario en el contexto
@@ -83,3 +109,26 @@ func AuthenticateToken(c *fiber.Ctx) error {
 
 	return c.Next()
 }
+
+// AuthenticateWebSocket variante de AuthenticateToken para el upgrade a WebSocket.
+// Los navegadores no pueden enviar headers en el handshake, así que el token también
+// se acepta en el query param access_token; se valida con la misma lógica.
+func AuthenticateWebSocket(c *fiber.Ctx) error {
+	if c.Get("Authorization") == "" && c.Query("access_token") != "" {
+		// Copiarlo al header deja el token disponible para reenviarlo a Node.js
+		c.Request().Header.Set("Authorization", "Bearer "+c.Query("access_token"))
+	}
+	return AuthenticateToken(c)
+}
+
+// RequireRole middleware que permite el acceso solo a usuarios con el rol dado.
+// Va después de AuthenticateToken (usa los claims guardados en el contexto).
+func RequireRole(role string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, _ := c.Locals("user").(*Claims)
+		if claims == nil || claims.Role != role {
+			return WriteProblem(c, apperrors.New(apperrors.CodeForbidden, map[string]interface{}{"role": role}))
+		}
+		return c.Next()
+	}
+}
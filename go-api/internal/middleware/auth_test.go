@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
@@ -133,3 +135,100 @@ func createTokenWithWrongSecret(t *testing.T) string {
 	return tokenString
 }
 
+func TestAuthenticateToken_ErrorCodes(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+
+	app := fiber.New()
+	app.Get("/protected", AuthenticateToken, func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	expired := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		Username: "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		},
+	})
+	expiredToken, err := expired.SignedString([]byte("test-secret-key"))
+	if err != nil {
+		t.Fatalf("Error al crear token: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		authHeader string
+		wantCode   string
+	}{
+		{name: "sin token", authHeader: "", wantCode: "TOKEN_MISSING"},
+		{name: "formato inválido", authHeader: "Token abc", wantCode: "TOKEN_MALFORMED"},
+		{name: "token inválido", authHeader: "Bearer abc", wantCode: "TOKEN_INVALID"},
+		{name: "token expirado", authHeader: "Bearer " + expiredToken, wantCode: "TOKEN_EXPIRED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/protected", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+
+			var problem map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&problem)
+			if problem["code"] != tt.wantCode {
+				t.Errorf("code = %v, want %s", problem["code"], tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+
+	app := fiber.New()
+	app.Get("/admin", AuthenticateToken, RequireRole("admin"), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	user := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Username: "user", ID: 2, Role: "user"})
+	userToken, err := user.SignedString([]byte("test-secret-key"))
+	if err != nil {
+		t.Fatalf("Error al crear token: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+		wantCode       string
+	}{
+		{name: "rol admin", token: createValidToken(t), expectedStatus: http.StatusOK},
+		{name: "otro rol", token: userToken, expectedStatus: http.StatusForbidden, wantCode: "FORBIDDEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if tt.wantCode != "" {
+				var problem map[string]interface{}
+				json.NewDecoder(resp.Body).Decode(&problem)
+				if problem["code"] != tt.wantCode {
+					t.Errorf("code = %v, want %s", problem["code"], tt.wantCode)
+				}
+			}
+		})
+	}
+}
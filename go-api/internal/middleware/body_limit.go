@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"io"
+	"strings"
+
+	"go-api/internal/apperrors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NDJSONContentType content type de JSON delimitado por líneas (una petición o resultado por línea)
+const NDJSONContentType = "application/x-ndjson"
+
+// IsNDJSON indica si el cuerpo de la petición es NDJSON
+func IsNDJSON(c *fiber.Ctx) bool {
+	return strings.HasPrefix(strings.ToLower(c.Get(fiber.HeaderContentType)), NDJSONContentType)
+}
+
+// BodyLimit limita el tamaño del cuerpo cuando la app usa StreamRequestBody.
+// Con streaming, Fiber entrega como stream los cuerpos que superan su BodyLimit en vez de
+// rechazarlos; este middleware los lee hasta limit bytes y responde 413 si lo superan, así
+// los handlers que usan BodyParser conservan el límite. Las peticiones para las que
+// streaming retorna true reciben el stream intacto (ej: NDJSON por lotes).
+func BodyLimit(limit int, streaming func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		stream := c.Context().RequestBodyStream()
+		if stream == nil || streaming(c) {
+			return c.Next()
+		}
+
+		body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+		if err != nil {
+			return WriteProblem(c, apperrors.Wrap(apperrors.CodeInvalidBody, err))
+		}
+		if len(body) > limit {
+			// El resto del cuerpo queda sin leer: la conexión no se puede reutilizar
+			c.Context().SetConnectionClose()
+			return WriteProblem(c, apperrors.New(apperrors.CodePayloadTooLarge, nil))
+		}
+		c.Request().SetBodyRaw(body)
+		return c.Next()
+	}
+}
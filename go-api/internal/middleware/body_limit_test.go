@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestBodyLimit(t *testing.T) {
+	// Con BodyLimit de Fiber en 16 los cuerpos mayores llegan como stream
+	app := fiber.New(fiber.Config{StreamRequestBody: true, BodyLimit: 16})
+	app.Use(BodyLimit(32, IsNDJSON))
+	app.Post("/", func(c *fiber.Ctx) error {
+		if stream := c.Context().RequestBodyStream(); stream != nil && IsNDJSON(c) {
+			body, err := io.ReadAll(stream)
+			if err != nil {
+				return err
+			}
+			return c.Send(body)
+		}
+		return c.Send(c.Body())
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		contentType    string
+		expectedStatus int
+	}{
+		{name: "cuerpo pequeño", body: `{"a":1}`, contentType: "application/json", expectedStatus: 200},
+		{name: "cuerpo en stream dentro del límite", body: strings.Repeat("a", 30), contentType: "application/json", expectedStatus: 200},
+		{name: "cuerpo en stream sobre el límite", body: strings.Repeat("a", 40), contentType: "application/json", expectedStatus: 413},
+		{name: "NDJSON sin límite", body: strings.Repeat("a", 100), contentType: NDJSONContentType, expectedStatus: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if tt.expectedStatus != 200 {
+				var problem map[string]interface{}
+				json.Unmarshal(body, &problem)
+				if problem["code"] != "PAYLOAD_TOO_LARGE" {
+					t.Errorf("code = %v, want PAYLOAD_TOO_LARGE", problem["code"])
+				}
+				return
+			}
+			if string(body) != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deprecated middleware para rutas obsoletas que siguen funcionando como alias.
+// Agrega los headers Deprecation (RFC 9745), Sunset (RFC 8594) y un Link a la ruta
+// equivalente en la versión vigente (successorPrefix + ruta, ej: /v1/matrix/process).
+func Deprecated(deprecatedAt, sunset time.Time, successorPrefix string) fiber.Handler {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunsetValue := sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *fiber.Ctx) error {
+		c.Set("Deprecation", deprecation)
+		c.Set("Sunset", sunsetValue)
+		c.Set(fiber.HeaderLink, fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, c.Route().Path))
+		return c.Next()
+	}
+}
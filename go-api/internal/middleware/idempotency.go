@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/idempotency"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// HeaderIdempotencyKey header con el que el cliente identifica una petición que puede reintentar
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed marca las respuestas reproducidas desde el store
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	// MaxIdempotencyKeyLength largo máximo de una Idempotency-Key
+	MaxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders headers de la respuesta que se guardan junto al cuerpo
+var replayedHeaders = []string{
+	fiber.HeaderContentType,
+	fiber.HeaderContentLanguage,
+	fiber.HeaderLocation,
+	fiber.HeaderETag,
+	fiber.HeaderContentDisposition,
+	fiber.HeaderVary,
+	"X-Cache",
+}
+
+// Idempotency middleware para rutas que modifican estado; va después de AuthenticateToken.
+// Las peticiones con header Idempotency-Key guardan su primera respuesta por usuario y
+// los reintentos con la misma clave, ruta, cuerpo, Accept y Content-Type la reciben de nuevo
+// sin volver a ejecutar el handler (con Idempotent-Replayed: true). Otra ruta, cuerpo o
+// formato con la misma clave se rechaza con 422 y un reintento mientras la primera sigue en curso con 409.
+// Las respuestas 5xx, 304, 412 y en streaming no se guardan: el reintento se vuelve a ejecutar.
+func Idempotency(store *idempotency.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return c.Next()
+		}
+		if !validIdempotencyKey(key) {
+			return WriteProblem(c, apperrors.New(apperrors.CodeIdempotencyKeyInvalid, map[string]interface{}{"max": MaxIdempotencyKeyLength}))
+		}
+		userID := 0
+		if claims, ok := c.Locals("user").(*Claims); ok {
+			userID = claims.ID
+		}
+
+		saved, err := store.Begin(userID, key, requestFingerprint(c))
+		if err != nil {
+			return WriteProblem(c, err)
+		}
+		if saved != nil {
+			slog.InfoContext(c.UserContext(), "idempotent request replayed", "path", c.Path(), "status", saved.Status)
+			for name, value := range saved.Headers {
+				c.Set(name, value)
+			}
+			c.Set(HeaderIdempotentReplayed, "true")
+			return c.Status(saved.Status).Send(saved.Body)
+		}
+
+		// Si el handler falla o entra en panic la clave se libera para permitir el reintento
+		completed := false
+		defer func() {
+			if !completed {
+				store.Release(userID, key)
+			}
+		}()
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		resp := c.Response()
+		status := resp.StatusCode()
+		if status >= fiber.StatusInternalServerError || status == fiber.StatusNotModified || status == fiber.StatusPreconditionFailed || resp.IsBodyStream() {
+			return nil
+		}
+		saved = &idempotency.Response{
+			Status:  status,
+			Headers: make(map[string]string),
+			Body:    append([]byte(nil), resp.Body()...),
+		}
+		for _, name := range replayedHeaders {
+			if value := resp.Header.Peek(name); len(value) > 0 {
+				saved.Headers[name] = string(value)
+			}
+		}
+		store.Complete(userID, key, saved)
+		completed = true
+		return nil
+	}
+}
+
+// validIdempotencyKey acepta entre 1 y MaxIdempotencyKeyLength caracteres ASCII visibles
+func validIdempotencyKey(key string) bool {
+	if len(key) > MaxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint huella de la petición: método, ruta, query, cuerpo y los headers que
+// eligen el formato del cuerpo y de la respuesta (un reintento con otro Accept no debe
+// recibir la respuesta guardada en el formato anterior)
+func requestFingerprint(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method()))
+	h.Write([]byte{0})
+	h.Write([]byte(c.Path()))
+	h.Write([]byte{0})
+	h.Write(c.Request().URI().QueryString())
+	h.Write([]byte{0})
+	h.Write(c.Request().Header.Peek(fiber.HeaderAccept))
+	h.Write([]byte{0})
+	h.Write(c.Request().Header.ContentType())
+	h.Write([]byte{0})
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api/internal/idempotency"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestIdempotency(t *testing.T) {
+	store := idempotency.NewStore(idempotency.Config{TTL: time.Hour})
+	calls := 0
+	app := fiber.New()
+	// El usuario autenticado se simula con el header X-User
+	app.Use(func(c *fiber.Ctx) error {
+		id, _ := strconv.Atoi(c.Get("X-User"))
+		c.Locals("user", &Claims{ID: id})
+		return c.Next()
+	})
+	app.Post("/jobs", Idempotency(store), func(c *fiber.Ctx) error {
+		calls++
+		if string(c.Body()) == "falla" {
+			return c.SendStatus(fiber.StatusServiceUnavailable)
+		}
+		c.Location("/jobs/" + strconv.Itoa(calls))
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"call": calls})
+	})
+
+	tests := []struct {
+		name           string
+		user           string
+		key            string
+		body           string
+		accept         string
+		contentType    string
+		expectedStatus int
+		expectedCall   float64
+		expectedCalls  int
+		replayed       bool
+		wantCode       string
+	}{
+		{name: "sin clave", user: "1", body: "a", expectedStatus: 202, expectedCall: 1, expectedCalls: 1},
+		{name: "clave nueva", user: "1", key: "k1", body: "a", expectedStatus: 202, expectedCall: 2, expectedCalls: 2},
+		{name: "reintento reproduce la respuesta", user: "1", key: "k1", body: "a", expectedStatus: 202, expectedCall: 2, expectedCalls: 2, replayed: true},
+		{name: "misma clave con otro cuerpo", user: "1", key: "k1", body: "b", expectedStatus: 422, expectedCalls: 2, wantCode: "IDEMPOTENCY_KEY_MISMATCH"},
+		{name: "misma clave con otro Accept", user: "1", key: "k1", body: "a", accept: "application/msgpack", expectedStatus: 422, expectedCalls: 2, wantCode: "IDEMPOTENCY_KEY_MISMATCH"},
+		{name: "misma clave con otro Content-Type", user: "1", key: "k1", body: "a", contentType: "application/cbor", expectedStatus: 422, expectedCalls: 2, wantCode: "IDEMPOTENCY_KEY_MISMATCH"},
+		{name: "misma clave de otro usuario", user: "2", key: "k1", body: "b", expectedStatus: 202, expectedCall: 3, expectedCalls: 3},
+		{name: "error del servidor no se guarda", user: "1", key: "k2", body: "falla", expectedStatus: 503, expectedCalls: 4},
+		{name: "reintento tras error del servidor", user: "1", key: "k2", body: "falla", expectedStatus: 503, expectedCalls: 5},
+		{name: "clave demasiado larga", user: "1", key: strings.Repeat("k", MaxIdempotencyKeyLength+1), body: "a", expectedStatus: 400, expectedCalls: 5, wantCode: "IDEMPOTENCY_KEY_INVALID"},
+		{name: "clave con espacios", user: "1", key: "k 3", body: "a", expectedStatus: 400, expectedCalls: 5, wantCode: "IDEMPOTENCY_KEY_INVALID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/jobs", strings.NewReader(tt.body))
+			req.Header.Set("X-User", tt.user)
+			if tt.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, tt.accept)
+			}
+			if tt.contentType != "" {
+				req.Header.Set(fiber.HeaderContentType, tt.contentType)
+			}
+			if tt.key != "" {
+				req.Header.Set(HeaderIdempotencyKey, tt.key)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("llamadas al handler = %d, want %d", calls, tt.expectedCalls)
+			}
+			if got := resp.Header.Get(HeaderIdempotentReplayed) == "true"; got != tt.replayed {
+				t.Errorf("Idempotent-Replayed = %v, want %v", got, tt.replayed)
+			}
+
+			var body map[string]interface{}
+			data, _ := io.ReadAll(resp.Body)
+			json.Unmarshal(data, &body)
+			if tt.wantCode != "" {
+				if body["code"] != tt.wantCode {
+					t.Errorf("code = %v, want %s", body["code"], tt.wantCode)
+				}
+				return
+			}
+			if tt.expectedCall != 0 {
+				if body["call"] != tt.expectedCall {
+					t.Errorf("call = %v, want %v", body["call"], tt.expectedCall)
+				}
+				if want := "/jobs/" + strconv.Itoa(int(tt.expectedCall)); resp.Header.Get("Location") != want {
+					t.Errorf("Location = %q, want %q", resp.Header.Get("Location"), want)
+				}
+			}
+		})
+	}
+}
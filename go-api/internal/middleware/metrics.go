@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"go-api/internal/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics middleware que registra peticiones en curso, cantidad y latencia por ruta y estado.
+// Usa el patrón de la ruta (ej: /jobs/:id) como label para no crear una serie por URL.
+func Metrics(c *fiber.Ctx) error {
+	metrics.HTTPRequestsInFlight.Inc()
+	defer metrics.HTTPRequestsInFlight.Dec()
+
+	// Si ninguna ruta coincide, c.Route() sigue siendo la de este middleware después de Next
+	// (Fiber une en una sola ruta los app.Use consecutivos sobre el mismo prefijo). Un 404
+	// que retorna el handler de una ruta existente se registra con el patrón de esa ruta.
+	own := c.Route()
+	start := time.Now()
+	err := c.Next()
+
+	route := c.Route().Path
+	status := responseStatus(c, err)
+	if c.Route() == own {
+		route = "unmatched"
+	}
+
+	labels := []string{route, c.Method(), strconv.Itoa(status)}
+	metrics.HTTPRequestsTotal.WithLabelValues(labels...).Inc()
+	metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// responseStatus retorna el código de estado de la respuesta.
+// Si el handler retornó un error, el ErrorHandler todavía no escribió la respuesta,
+// así que el código se deriva del error.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return fe.Code
+	}
+	return fiber.StatusInternalServerError
+}
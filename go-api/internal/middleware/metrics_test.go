@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go-api/internal/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	app := fiber.New()
+	app.Use(Metrics)
+	app.Use(func(c *fiber.Ctx) error { return c.Next() })
+	app.Get("/items/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "missing" {
+			return fiber.ErrNotFound
+		}
+		return c.SendString("ok")
+	})
+
+	okCounter := metrics.HTTPRequestsTotal.WithLabelValues("/items/:id", "GET", "200")
+	notFoundCounter := metrics.HTTPRequestsTotal.WithLabelValues("unmatched", "GET", "404")
+	routeNotFoundCounter := metrics.HTTPRequestsTotal.WithLabelValues("/items/:id", "GET", "404")
+	beforeOK := testutil.ToFloat64(okCounter)
+	beforeNotFound := testutil.ToFloat64(notFoundCounter)
+	beforeRouteNotFound := testutil.ToFloat64(routeNotFoundCounter)
+
+	for _, path := range []string{"/items/1", "/items/2", "/nope", "/items/missing"} {
+		if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+			t.Fatalf("Error al hacer request: %v", err)
+		}
+	}
+
+	// Las dos peticiones a /items/:id deben agruparse bajo el patrón de la ruta
+	if got := testutil.ToFloat64(okCounter) - beforeOK; got != 2 {
+		t.Errorf("http_requests_total{route=/items/:id} incrementó %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(notFoundCounter) - beforeNotFound; got != 1 {
+		t.Errorf("http_requests_total{route=unmatched} incrementó %v, want 1", got)
+	}
+	// Un 404 del handler de una ruta existente se registra con el patrón de la ruta
+	if got := testutil.ToFloat64(routeNotFoundCounter) - beforeRouteNotFound; got != 1 {
+		t.Errorf("http_requests_total{route=/items/:id,status=404} incrementó %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.HTTPRequestsInFlight); got != 0 {
+		t.Errorf("http_requests_in_flight = %v, want 0", got)
+	}
+}
+
+func TestAuthenticateToken_FailureMetrics(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	defer os.Unsetenv("JWT_SECRET")
+
+	app := fiber.New()
+	app.Get("/protected", AuthenticateToken, func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		authHeader string
+		reason     string
+	}{
+		{name: "sin token", authHeader: "", reason: "missing_token"},
+		{name: "formato inválido", authHeader: "Token abc", reason: "invalid_format"},
+		{name: "token inválido", authHeader: "Bearer abc", reason: "invalid_token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.AuthFailuresTotal.WithLabelValues(tt.reason)
+			before := testutil.ToFloat64(counter)
+
+			req := httptest.NewRequest("GET", "/protected", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("auth_failures_total{reason=%s} incrementó %v, want 1", tt.reason, got)
+			}
+		})
+	}
+}
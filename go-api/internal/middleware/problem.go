@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+
+	"go-api/internal/apperrors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProblemContentType content type de las respuestas de error (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Language retorna el idioma negociado a partir del header Accept-Language
+func Language(c *fiber.Ctx) string {
+	return apperrors.NegotiateLanguage(c.Get(fiber.HeaderAcceptLanguage))
+}
+
+// WriteProblem responde con un cuerpo application/problem+json localizado según Accept-Language.
+// Los *apperrors.Error conservan su código; los *fiber.Error se mapean por código HTTP
+// y cualquier otro error se reporta como INTERNAL_ERROR. Los 5xx se registran en el log
+// con su causa.
+func WriteProblem(c *fiber.Ctx, err error) error {
+	var appErr *apperrors.Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &appErr):
+	case errors.As(err, &fiberErr):
+		appErr = apperrors.FromStatus(fiberErr.Code, fiberErr)
+	default:
+		appErr = apperrors.From(err)
+	}
+
+	// La causa de los 5xx no se envía al cliente (no va en details): queda en el log
+	if appErr.Status >= fiber.StatusInternalServerError {
+		slog.ErrorContext(c.UserContext(), "request failed", "code", appErr.Code, "error", appErr.Error())
+	}
+
+	lang := Language(c)
+	problem := appErr.Problem(lang)
+	problem.Instance = c.Path()
+	problem.RequestID = GetRequestID(c)
+
+	c.Set(fiber.HeaderContentLanguage, lang)
+	return c.Status(problem.Status).JSON(problem, ProblemContentType)
+}
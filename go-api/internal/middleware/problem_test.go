@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+func TestWriteProblem(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: WriteProblem})
+	app.Use(RequestID)
+	app.Get("/app-error", func(c *fiber.Ctx) error {
+		return WriteProblem(c, apperrors.New(apperrors.CodeMatrixEmpty, nil))
+	})
+	app.Get("/generic", func(c *fiber.Ctx) error {
+		return errors.New("boom")
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptLanguage string
+		expectedStatus int
+		wantCode       string
+		wantDetail     string
+	}{
+		{
+			name:           "error de la API en español por defecto",
+			path:           "/app-error",
+			expectedStatus: 400,
+			wantCode:       "MATRIX_EMPTY",
+			wantDetail:     "la matriz no puede estar vacía",
+		},
+		{
+			name:           "error de la API en inglés",
+			path:           "/app-error",
+			acceptLanguage: "en-US,en;q=0.9",
+			expectedStatus: 400,
+			wantCode:       "MATRIX_EMPTY",
+			wantDetail:     "the matrix cannot be empty",
+		},
+		{
+			name:           "error genérico como INTERNAL_ERROR",
+			path:           "/generic",
+			expectedStatus: 500,
+			wantCode:       "INTERNAL_ERROR",
+			wantDetail:     "error interno del servidor",
+		},
+		{
+			name:           "ruta inexistente como NOT_FOUND",
+			path:           "/nope",
+			acceptLanguage: "en",
+			expectedStatus: 404,
+			wantCode:       "NOT_FOUND",
+			wantDetail:     "the requested resource does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Status code = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", ct, ProblemContentType)
+			}
+
+			var problem models.Problem
+			json.NewDecoder(resp.Body).Decode(&problem)
+			if problem.Code != tt.wantCode || problem.Detail != tt.wantDetail {
+				t.Errorf("code/detail = %s/%q, want %s/%q", problem.Code, problem.Detail, tt.wantCode, tt.wantDetail)
+			}
+			if problem.Status != tt.expectedStatus || problem.Instance != tt.path {
+				t.Errorf("status/instance = %d/%s, want %d/%s", problem.Status, problem.Instance, tt.expectedStatus, tt.path)
+			}
+			if problem.RequestID == "" || problem.RequestID != resp.Header.Get(RequestIDHeader) {
+				t.Errorf("requestId = %q, debe coincidir con X-Request-ID", problem.RequestID)
+			}
+		})
+	}
+}
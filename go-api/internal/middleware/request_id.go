@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"go-api/internal/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader header usado para correlacionar peticiones entre servicios
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength longitud máxima aceptada para un X-Request-ID entrante
+const maxRequestIDLength = 128
+
+// RequestID middleware que acepta el X-Request-ID del cliente (si es válido) o genera uno nuevo.
+// El ID se devuelve en la respuesta, se guarda en c.Locals("requestID") y en c.UserContext()
+// para que los logs y las llamadas salientes lo incluyan.
+func RequestID(c *fiber.Ctx) error {
+	id := c.Get(RequestIDHeader)
+	if !validRequestID(id) {
+		id = uuid.NewString()
+	}
+
+	c.Set(RequestIDHeader, id)
+	c.Locals("requestID", id)
+	c.SetUserContext(logging.WithRequestID(c.UserContext(), id))
+
+	return c.Next()
+}
+
+// GetRequestID retorna el request ID asignado por el middleware RequestID
+func GetRequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals("requestID").(string)
+	return id
+}
+
+// validRequestID acepta IDs no vacíos de hasta 128 caracteres alfanuméricos o -_.:
+// (evita inyectar contenido arbitrario en logs y headers)
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// AccessLog middleware que registra cada petición como una línea JSON (reemplaza logger.New()).
+// Debe registrarse después de RequestID para incluir el request_id.
+func AccessLog(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+	status := responseStatus(c, err)
+
+	level := slog.LevelInfo
+	switch {
+	case status >= fiber.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= fiber.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", c.Method()),
+		slog.String("path", c.Path()),
+		slog.String("route", c.Route().Path),
+		slog.Int("status", status),
+		slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		slog.String("ip", c.IP()),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	slog.LogAttrs(c.UserContext(), level, "request", attrs...)
+
+	return err
+}
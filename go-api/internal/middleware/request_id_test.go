@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go-api/internal/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		incoming   string
+		wantReused bool
+	}{
+		{name: "reutiliza el ID del cliente", incoming: "abc-123", wantReused: true},
+		{name: "genera un ID si no viene", incoming: "", wantReused: false},
+		{name: "descarta IDs con caracteres inválidos", incoming: "abc\"}\n", wantReused: false},
+		{name: "descarta IDs demasiado largos", incoming: strings.Repeat("a", 200), wantReused: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctxID, localsID string
+			app := fiber.New()
+			app.Use(RequestID)
+			app.Get("/", func(c *fiber.Ctx) error {
+				ctxID = logging.RequestID(c.UserContext())
+				localsID = GetRequestID(c)
+				return c.SendString("ok")
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Error al hacer request: %v", err)
+			}
+
+			got := resp.Header.Get(RequestIDHeader)
+			if got == "" {
+				t.Fatal("Se esperaba X-Request-ID en la respuesta")
+			}
+			if tt.wantReused && got != tt.incoming {
+				t.Errorf("X-Request-ID = %q, want %q", got, tt.incoming)
+			}
+			if !tt.wantReused && got == tt.incoming {
+				t.Errorf("X-Request-ID = %q, se esperaba un ID generado", got)
+			}
+			if ctxID != got || localsID != got {
+				t.Errorf("ID en contexto = %q / locals = %q, want %q", ctxID, localsID, got)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, slog.LevelInfo))
+	defer slog.SetDefault(previous)
+
+	app := fiber.New()
+	app.Use(RequestID, AccessLog)
+	app.Get("/items/:id", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusTeapot).SendString("ok")
+	})
+
+	req := httptest.NewRequest("GET", "/items/7", nil)
+	req.Header.Set(RequestIDHeader, "req-log-1")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("Error al hacer request: %v", err)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Se esperaba una línea JSON, got %q: %v", buf.String(), err)
+	}
+	if line["request_id"] != "req-log-1" {
+		t.Errorf("request_id = %v, want req-log-1", line["request_id"])
+	}
+	if line["route"] != "/items/:id" || line["status"] != float64(fiber.StatusTeapot) {
+		t.Errorf("route/status = %v/%v, want /items/:id/418", line["route"], line["status"])
+	}
+	if line["level"] != "WARN" {
+		t.Errorf("level = %v, want WARN para respuestas 4xx", line["level"])
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	var handlerTraceID trace.TraceID
+	app := fiber.New()
+	app.Use(Tracing)
+	app.Get("/items/:id", func(c *fiber.Ctx) error {
+		handlerTraceID = trace.SpanContextFromContext(c.UserContext()).TraceID()
+		return c.SendString("ok")
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("GET", "/items/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("Error al hacer request: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Se registraron %d spans, esperaba 1", len(spans))
+	}
+	if got := spans[0].Name(); got != "GET /items/:id" {
+		t.Errorf("Nombre del span = %q, want %q", got, "GET /items/:id")
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("TraceID = %s, want %s (debe continuar la traza entrante)", got, traceID)
+	}
+	if handlerTraceID.String() != traceID {
+		t.Errorf("El handler no recibió el contexto de traza en UserContext")
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Complex número complejo. En JSON se acepta como objeto {"re": 1, "im": -2} o como par
+// [1, -2]; en las respuestas va siempre como objeto.
+type Complex struct {
+	Re float64 `json:"re"`
+	Im float64 `json:"im"`
+}
+
+// UnmarshalJSON acepta la forma de objeto y la de par [re, im]
+func (c *Complex) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var pair []float64
+		if err := json.Unmarshal(data, &pair); err != nil {
+			return err
+		}
+		if len(pair) != 2 {
+			return fmt.Errorf("complex pair must have 2 elements [re, im], got %d", len(pair))
+		}
+		c.Re, c.Im = pair[0], pair[1]
+		return nil
+	}
+	type plain Complex
+	return json.Unmarshal(data, (*plain)(c))
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Fraction número racional exacto como texto: "1/3", "-2", "0.25" o "1e-3". En JSON se
+// acepta como string o como número; en las respuestas va siempre como string en su forma
+// reducida ("a/b", o "a" si es entero).
+type Fraction string
+
+// UnmarshalJSON acepta un string o un número JSON (que se guarda con el texto original)
+func (f *Fraction) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*f = Fraction(s)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("fraction must be a string or a number, got %s", data)
+	}
+	*f = Fraction(number)
+	return nil
+}
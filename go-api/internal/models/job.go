@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Operaciones soportadas por los jobs asíncronos
+const (
+	// JobOperationProcess procesamiento completo (rotación, QR y estadísticas de Node.js)
+	JobOperationProcess = "process"
+	// JobOperationQR solo factorización QR
+	JobOperationQR = "qr"
+)
+
+// Estados de un job
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusCanceled  = "canceled"
+)
+
+// JobRequest representa la petición para crear un job asíncrono
+type JobRequest struct {
+	Operation   string      `json:"operation,omitempty"`
+	Matrix      [][]float64 `json:"matrix"`
+	CallbackURL string      `json:"callbackUrl,omitempty"`
+}
+
+// Job representa el estado de un job asíncrono y su resultado
+type Job struct {
+	ID         string                 `json:"id"`
+	OwnerID    int                    `json:"ownerId"`
+	Operation  string                 `json:"operation"`
+	Status     string                 `json:"status"`
+	Progress   float64                `json:"progress"`
+	Result     *MatrixProcessResponse `json:"result,omitempty"`
+	Error      *Problem               `json:"error,omitempty"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	StartedAt  *time.Time             `json:"startedAt,omitempty"`
+	FinishedAt *time.Time             `json:"finishedAt,omitempty"`
+	// ExpiresAt se recalcula al terminar (FinishedAt + ResultTTL); un job sin terminar no expira
+	ExpiresAt time.Time `json:"expiresAt"`
+	// CallbackURL recibe el job al terminar (webhook firmado)
+	CallbackURL string `json:"callbackUrl,omitempty"`
+	// Deliveries registro de intentos de entrega del webhook
+	Deliveries []WebhookDelivery `json:"deliveries,omitempty"`
+}
+
+// WebhookDelivery registro de un intento de entrega de webhook
+type WebhookDelivery struct {
+	Attempt    int       `json:"attempt"`
+	Timestamp  time.Time `json:"timestamp"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs float64   `json:"durationMs"`
+}
+
+// Finished indica si el job terminó (con éxito, error o cancelado)
+func (j *Job) Finished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed || j.Status == JobStatusCanceled
+}
@@ -3,13 +3,77 @@ package models
 // MatrixRequest representa la petición del cliente con una matriz
 type MatrixRequest struct {
 	Matrix [][]float64 `json:"matrix"`
+	// Sparse matriz en formato disperso, en lugar de Matrix
+	Sparse *SparseMatrix `json:"sparse,omitempty"`
+	// Complex matriz de números complejos, en lugar de Matrix
+	Complex [][]Complex `json:"complex,omitempty"`
+	// Precision bits de mantisa con los que se calcula QR en big.Float (0: float64)
+	Precision uint `json:"precision,omitempty"`
+	// Explain agrega a la respuesta los pasos de Gram-Schmidt con los que se obtienen Q y R
+	Explain bool `json:"explain,omitempty"`
+	// ExplainMethod variante de Gram-Schmidt que se explica: "classical" (por defecto) o "modified"
+	ExplainMethod string `json:"explainMethod,omitempty"`
+}
+
+// Variantes de Gram-Schmidt de ExplainMethod
+const (
+	GramSchmidtClassical = "classical"
+	GramSchmidtModified  = "modified"
+)
+
+// Formatos de SparseMatrix
+const (
+	SparseFormatCOO = "coo"
+	SparseFormatCSR = "csr"
+)
+
+// SparseMatrix matriz dispersa de rows×cols con sus elementos no nulos en data (mismos
+// nombres que scipy.sparse). En COO el elemento k está en (row[k], col[k]); en CSR los
+// elementos de la fila i son data[indptr[i]:indptr[i+1]], con sus columnas en indices.
+type SparseMatrix struct {
+	Format  string    `json:"format"`
+	Rows    int       `json:"rows"`
+	Cols    int       `json:"cols"`
+	Row     []int     `json:"row,omitempty"`
+	Col     []int     `json:"col,omitempty"`
+	IndPtr  []int     `json:"indptr,omitempty"`
+	Indices []int     `json:"indices,omitempty"`
+	Data    []float64 `json:"data"`
+}
+
+// ExactMatrixRequest variante de MatrixRequest para el modo exacto: los elementos son
+// fracciones. Con b también se resuelve A·x = b (b tiene un elemento por fila).
+type ExactMatrixRequest struct {
+	Matrix [][]Fraction `json:"matrix"`
+	B      []Fraction   `json:"b,omitempty"`
 }
 
 // MatrixStatsRequest representa la petición que se envía a Node.js
 type MatrixStatsRequest struct {
-	Q        [][]float64 `json:"q"`
-	R        [][]float64 `json:"r"`
-	Rotated  [][]float64 `json:"rotated,omitempty"`
+	Q       [][]float64 `json:"q"`
+	R       [][]float64 `json:"r"`
+	Rotated [][]float64 `json:"rotated,omitempty"`
 }
 
+// MatrixStatsBatchRequest representa la petición por lote que se envía a Node.js
+type MatrixStatsBatchRequest struct {
+	Items []MatrixStatsRequest `json:"items"`
+}
+
+// BatchItemOptions opciones de procesamiento de un elemento del lote
+type BatchItemOptions struct {
+	// Operation "process" (por defecto) o "qr" (sin estadísticas de Node.js)
+	Operation string `json:"operation,omitempty"`
+}
 
+// BatchItem representa una matriz dentro de un lote
+type BatchItem struct {
+	ID      string           `json:"id"`
+	Matrix  [][]float64      `json:"matrix"`
+	Options BatchItemOptions `json:"options"`
+}
+
+// BatchRequest representa la petición de procesamiento por lote
+type BatchRequest struct {
+	Items []BatchItem `json:"items"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // MatrixStatsResponse representa la respuesta de Node.js con estadísticas
 type MatrixStatsResponse struct {
 	Max         float64 `json:"max"`
@@ -11,11 +13,180 @@ type MatrixStatsResponse struct {
 
 // MatrixProcessResponse representa la respuesta final al cliente
 type MatrixProcessResponse struct {
-	Rotated   [][]float64        `json:"rotated"`
-	Q         [][]float64        `json:"q"`
-	R         [][]float64        `json:"r"`
+	Rotated   [][]float64          `json:"rotated,omitempty"`
+	Q         [][]float64          `json:"q,omitempty"`
+	R         [][]float64          `json:"r,omitempty"`
 	NodeStats *MatrixStatsResponse `json:"nodeStats,omitempty"`
-	Error     string             `json:"error,omitempty"`
+	Error     string               `json:"error,omitempty"`
+	// ErrorCode código estable del error parcial (ej: NODE_STATS_UNAVAILABLE)
+	ErrorCode string `json:"errorCode,omitempty"`
+	// RotatedSparse, QSparse y RSparse reemplazan a Rotated, Q y R cuando la entrada es
+	// dispersa y la matriz ocupa menos en formato disperso que en denso
+	RotatedSparse *SparseMatrix `json:"rotatedSparse,omitempty"`
+	QSparse       *SparseMatrix `json:"qSparse,omitempty"`
+	RSparse       *SparseMatrix `json:"rSparse,omitempty"`
+	// RotatedComplex, QComplex y RComplex reemplazan a Rotated, Q y R cuando la entrada es
+	// compleja; NodeStats se calcula sobre sus módulos
+	RotatedComplex [][]Complex `json:"rotatedComplex,omitempty"`
+	QComplex       [][]Complex `json:"qComplex,omitempty"`
+	RComplex       [][]Complex `json:"rComplex,omitempty"`
+	// Precision, QDecimal, RDecimal y Residuals van cuando el pedido trae precision: Q y R
+	// calculadas con big.Float a esa precisión, como strings decimales, en lugar de Q y R
+	Precision uint                `json:"precision,omitempty"`
+	QDecimal  [][]string          `json:"qDecimal,omitempty"`
+	RDecimal  [][]string          `json:"rDecimal,omitempty"`
+	Residuals *PrecisionResiduals `json:"residuals,omitempty"`
+	// Explanation pasos de Gram-Schmidt, si el pedido trae explain
+	Explanation *GramSchmidtExplanation `json:"explanation,omitempty"`
+}
+
+// Tipos de paso de GramSchmidtStep
+const (
+	// GramSchmidtStepStart v_j = a_j
+	GramSchmidtStepStart = "start"
+	// GramSchmidtStepProjection r_ij = q_i·a_j (clásico) o q_i·v_j (modificado) y v_j -= r_ij·q_i
+	GramSchmidtStepProjection = "projection"
+	// GramSchmidtStepNormalization r_jj = ‖v_j‖ y q_j = v_j / r_jj
+	GramSchmidtStepNormalization = "normalization"
+)
+
+// GramSchmidtExplanation traza de la factorización QR reducida por Gram-Schmidt; la Q
+// (rows×cols) y R (cols×cols) que construye, con R[j][j] >= 0, son las q y r de la respuesta
+type GramSchmidtExplanation struct {
+	Method string            `json:"method"`
+	Steps  []GramSchmidtStep `json:"steps"`
+}
+
+// GramSchmidtStep un paso de Gram-Schmidt sobre la columna j (índices desde 0; en LaTeX
+// desde 1). Before es v_j antes de restar la proyección y Vector el resultado del paso
+// (v_j, o q_j en la normalización).
+type GramSchmidtStep struct {
+	Kind        string    `json:"kind"`
+	I           int       `json:"i"`
+	J           int       `json:"j"`
+	Coefficient float64   `json:"coefficient"`
+	Before      []float64 `json:"before,omitempty"`
+	Vector      []float64 `json:"vector"`
+	LaTeX       string    `json:"latex"`
+}
+
+// PrecisionResiduals verificación de una factorización QR calculada con big.Float, con la
+// misma precisión
+type PrecisionResiduals struct {
+	// Factorization ‖A - Q·R‖_F / ‖A‖_F
+	Factorization string `json:"factorization"`
+	// Orthogonality ‖Qᵀ·Q - I‖_F
+	Orthogonality string `json:"orthogonality"`
 }
 
+// ExactQRResponse factorización QR exacta por Gram-Schmidt: A = V·T, con V (basis) de
+// columnas ortogonales sin normalizar y T (coefficients) triangular superior con unos en la
+// diagonal. Con D = diag(norms2) es Q = V·D^(-1/2) y R = D^(1/2)·T, que van en q y r en forma
+// simbólica ("2/5·√5").
+type ExactQRResponse struct {
+	Basis        [][]Fraction `json:"basis"`
+	Coefficients [][]Fraction `json:"coefficients"`
+	// Norms2 cuadrado de la norma de cada columna de V; 0 si la columna de A depende de las
+	// anteriores
+	Norms2 []Fraction `json:"norms2"`
+	Q      [][]string `json:"q"`
+	R      [][]string `json:"r"`
+	Rank   int        `json:"rank"`
+	// Solution solución exacta de A·x = b, si se envió b
+	Solution *ExactSolution `json:"solution,omitempty"`
+}
+
+// ExactSolution solución exacta de mínimos cuadrados de A·x = b
+type ExactSolution struct {
+	X []Fraction `json:"x"`
+	// Residual2 cuadrado de la norma de A·x - b (0 si el sistema es compatible)
+	Residual2 Fraction `json:"residual2"`
+}
+
+// MatrixStatsBatchResult resultado de Node.js para un elemento del lote:
+// las estadísticas o el error de ese elemento
+type MatrixStatsBatchResult struct {
+	MatrixStatsResponse
+	Error string `json:"error,omitempty"`
+}
+
+// MatrixStatsBatchResponse representa la respuesta por lote de Node.js
+type MatrixStatsBatchResponse struct {
+	Results []MatrixStatsBatchResult `json:"results"`
+}
 
+// BatchItemResult resultado de un elemento del lote: la respuesta de procesamiento
+// etiquetada con el id del elemento, o el error (problem) si no se pudo procesar
+type BatchItemResult struct {
+	ID string `json:"id"`
+	*MatrixProcessResponse
+	Problem *Problem `json:"problem,omitempty"`
+}
+
+// BatchResponse representa la respuesta del procesamiento por lote
+type BatchResponse struct {
+	Results   []BatchItemResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// Estados posibles de una verificación de readiness
+const (
+	CheckStatusOK   = "ok"
+	CheckStatusFail = "fail"
+)
+
+// CheckResult representa el resultado de una verificación de dependencia
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ReadinessResponse representa la respuesta de /readyz
+type ReadinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Problem representa un error en formato RFC 7807 (application/problem+json)
+// Code es un identificador estable para clientes automatizados; Detail está localizado
+type Problem struct {
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Status    int                    `json:"status"`
+	Detail    string                 `json:"detail"`
+	Instance  string                 `json:"instance,omitempty"`
+	Code      string                 `json:"code"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"requestId,omitempty"`
+}
+
+// CacheEntry resultado guardado en el cache en memoria
+type CacheEntry struct {
+	Key       string    `json:"key"`
+	Bytes     int       `json:"bytes"`
+	Hits      int       `json:"hits"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// CacheStats estado del cache de resultados (GET /v1/admin/cache)
+type CacheStats struct {
+	Backend    string       `json:"backend"`
+	Entries    int          `json:"entries"`
+	Bytes      int64        `json:"bytes"`
+	MaxEntries int          `json:"maxEntries"`
+	MaxBytes   int64        `json:"maxBytes"`
+	TTLSeconds float64      `json:"ttlSeconds"`
+	Hits       uint64       `json:"hits"`
+	Misses     uint64       `json:"misses"`
+	Evictions  uint64       `json:"evictions"`
+	HitRatio   float64      `json:"hitRatio"`
+	Items      []CacheEntry `json:"items"`
+}
+
+// CachePurgeResponse resultado de vaciar el cache (DELETE /v1/admin/cache)
+type CachePurgeResponse struct {
+	Purged int `json:"purged"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// Tipos de mensaje del cliente en una sesión interactiva
+const (
+	// SessionMessageMatrix reemplaza la matriz completa
+	SessionMessageMatrix = "matrix"
+	// SessionMessageUpdate modifica celdas de la matriz actual
+	SessionMessageUpdate = "update"
+)
+
+// Tipos de evento que el servidor envía en una sesión interactiva
+const (
+	// SessionEventResult resultado recalculado para una versión de la matriz
+	SessionEventResult = "result"
+	// SessionEventError mensaje inválido o cálculo fallido
+	SessionEventError = "error"
+)
+
+// CellUpdate nuevo valor de una celda (índices desde 0)
+type CellUpdate struct {
+	Row   int     `json:"row"`
+	Col   int     `json:"col"`
+	Value float64 `json:"value"`
+}
+
+// SessionMessage mensaje del cliente en una sesión interactiva: una matriz completa
+// (type "matrix") o cambios de celdas sobre la matriz actual (type "update")
+type SessionMessage struct {
+	Type   string       `json:"type"`
+	Matrix [][]float64  `json:"matrix,omitempty"`
+	Cells  []CellUpdate `json:"cells,omitempty"`
+}
+
+// SessionEvent evento del servidor en una sesión interactiva. Version identifica la
+// versión de la matriz a la que corresponde (cada cambio aceptado la incrementa).
+type SessionEvent struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Result  *MatrixProcessResponse `json:"result,omitempty"`
+	Problem *Problem               `json:"problem,omitempty"`
+}
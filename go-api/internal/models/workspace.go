@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Operaciones que actualizan la factorización de un workspace sin recalcularla
+const (
+	// WorkspaceOpInsertRow inserta values como fila en index (sin index agrega al final)
+	WorkspaceOpInsertRow = "insertRow"
+	// WorkspaceOpDeleteRow elimina la fila index
+	WorkspaceOpDeleteRow = "deleteRow"
+	// WorkspaceOpInsertColumn inserta values como columna en index (sin index agrega al final)
+	WorkspaceOpInsertColumn = "insertColumn"
+	// WorkspaceOpDeleteColumn elimina la columna index
+	WorkspaceOpDeleteColumn = "deleteColumn"
+	// WorkspaceOpRankOne suma u·vᵀ a la matriz
+	WorkspaceOpRankOne = "rankOne"
+)
+
+// WorkspaceRequest representa la petición para crear un workspace a partir de una matriz
+type WorkspaceRequest struct {
+	Matrix [][]float64 `json:"matrix"`
+}
+
+// WorkspaceOperation modificación de la matriz de un workspace
+type WorkspaceOperation struct {
+	Type   string    `json:"type"`
+	Index  *int      `json:"index,omitempty"`
+	Values []float64 `json:"values,omitempty"`
+	U      []float64 `json:"u,omitempty"`
+	V      []float64 `json:"v,omitempty"`
+}
+
+// WorkspaceUpdateRequest lista de operaciones que se aplican en orden y de forma atómica
+type WorkspaceUpdateRequest struct {
+	Operations []WorkspaceOperation `json:"operations"`
+}
+
+// Workspace matriz guardada en el servidor junto con su factorización QR reducida
+// (Q m×n, R n×n). Matrix y Q solo se incluyen al consultarlo con GET.
+type Workspace struct {
+	ID        string      `json:"id"`
+	OwnerID   int         `json:"ownerId"`
+	Rows      int         `json:"rows"`
+	Cols      int         `json:"cols"`
+	Version   int         `json:"version"`
+	Matrix    [][]float64 `json:"matrix,omitempty"`
+	Q         [][]float64 `json:"q,omitempty"`
+	R         [][]float64 `json:"r"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/metrics"
+	"go-api/internal/models"
+)
+
+// BatchLimits límites de un lote; 0 significa sin límite
+type BatchLimits struct {
+	MaxItems    int
+	MaxElements int
+}
+
+// Validate verifica que el lote no esté vacío, respete los límites y no repita ids.
+// Los elementos sin id reciben su posición en el lote como id.
+func (l BatchLimits) Validate(items []models.BatchItem) error {
+	if len(items) == 0 {
+		return apperrors.New(apperrors.CodeBatchEmpty, nil)
+	}
+	if l.MaxItems > 0 && len(items) > l.MaxItems {
+		return apperrors.New(apperrors.CodeBatchTooManyItems, map[string]interface{}{"items": len(items), "max": l.MaxItems})
+	}
+
+	elements := 0
+	seen := make(map[string]bool, len(items))
+	for i := range items {
+		for _, row := range items[i].Matrix {
+			elements += len(row)
+		}
+		if items[i].ID == "" {
+			items[i].ID = strconv.Itoa(i)
+		}
+		if seen[items[i].ID] {
+			return apperrors.New(apperrors.CodeBatchDuplicateID, map[string]interface{}{"id": items[i].ID})
+		}
+		seen[items[i].ID] = true
+	}
+	if l.MaxElements > 0 && elements > l.MaxElements {
+		return apperrors.New(apperrors.CodeBatchTooManyElements, map[string]interface{}{"elements": elements, "max": l.MaxElements})
+	}
+	return nil
+}
+
+// BatchTracker aplica BatchLimits a un lote que llega elemento por elemento (ej: NDJSON),
+// sin conocer su tamaño total de antemano
+type BatchTracker struct {
+	limits   BatchLimits
+	items    int
+	elements int
+	seen     map[string]bool
+}
+
+// Tracker crea un BatchTracker con estos límites
+func (l BatchLimits) Tracker() *BatchTracker {
+	return &BatchTracker{limits: l, seen: make(map[string]bool)}
+}
+
+// Add registra un elemento y retorna error si supera un límite (BATCH_TOO_MANY_ITEMS,
+// BATCH_TOO_MANY_ELEMENTS) o repite un id (BATCH_DUPLICATE_ID). Un elemento rechazado no
+// cuenta para los límites; superar un límite invalida también los elementos siguientes.
+func (t *BatchTracker) Add(item models.BatchItem) error {
+	if t.limits.MaxItems > 0 && t.items+1 > t.limits.MaxItems {
+		return apperrors.New(apperrors.CodeBatchTooManyItems, map[string]interface{}{"items": t.items + 1, "max": t.limits.MaxItems})
+	}
+	elements := t.elements
+	for _, row := range item.Matrix {
+		elements += len(row)
+	}
+	if t.limits.MaxElements > 0 && elements > t.limits.MaxElements {
+		return apperrors.New(apperrors.CodeBatchTooManyElements, map[string]interface{}{"elements": elements, "max": t.limits.MaxElements})
+	}
+	if t.seen[item.ID] {
+		return apperrors.New(apperrors.CodeBatchDuplicateID, map[string]interface{}{"id": item.ID})
+	}
+	t.seen[item.ID] = true
+	t.items++
+	t.elements = elements
+	return nil
+}
+
+// BatchProcessor procesa lotes de matrices: la validación, rotación y QR se reparten en
+// un pool acotado de workers y las estadísticas se piden a Node.js por lotes
+type BatchProcessor struct {
+	Processor *MatrixProcessor
+	// Workers cantidad de matrices que se procesan en paralelo
+	Workers int
+	// NodeBatchSize cantidad máxima de elementos por llamada a Node.js
+	NodeBatchSize int
+	// FlushInterval espera máxima para completar un lote de Node.js antes de enviarlo incompleto
+	FlushInterval time.Duration
+}
+
+// NewBatchProcessor crea un procesador de lotes con lotes de Node.js de hasta 100 elementos
+func NewBatchProcessor(processor *MatrixProcessor, workers int) *BatchProcessor {
+	if workers < 1 {
+		workers = 1
+	}
+	return &BatchProcessor{
+		Processor:     processor,
+		Workers:       workers,
+		NodeBatchSize: 100,
+		FlushInterval: 20 * time.Millisecond,
+	}
+}
+
+// EmitFunc recibe el resultado de un elemento; index es su posición en la entrada
+type EmitFunc func(index int, result models.BatchItemResult)
+
+// pendingStats elemento ya factorizado que espera sus estadísticas de Node.js
+type pendingStats struct {
+	index    int
+	id       string
+	response *models.MatrixProcessResponse
+}
+
+// Process procesa todos los elementos y retorna los resultados en el orden de entrada.
+// Un elemento inválido no hace fallar al resto: su resultado lleva Problem.
+func (b *BatchProcessor) Process(ctx context.Context, items []models.BatchItem, opts ProcessOptions) []models.BatchItemResult {
+	in := make(chan models.BatchItem)
+	go func() {
+		defer close(in)
+		for _, item := range items {
+			select {
+			case in <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make([]models.BatchItemResult, len(items))
+	b.Run(ctx, in, opts, func(index int, result models.BatchItemResult) {
+		results[index] = result
+	})
+	return results
+}
+
+// Run procesa los elementos a medida que llegan por in y llama a emit con cada resultado
+// en cuanto está listo (no necesariamente en orden). emit nunca se llama en paralelo.
+// Retorna cuando in se cierra y todos los elementos fueron emitidos, o cuando se cancela ctx.
+func (b *BatchProcessor) Run(ctx context.Context, in <-chan models.BatchItem, opts ProcessOptions, emit EmitFunc) {
+	var emitMu sync.Mutex
+	safeEmit := func(index int, result models.BatchItemResult) {
+		emitMu.Lock()
+		defer emitMu.Unlock()
+		emit(index, result)
+	}
+
+	// Numerar los elementos en el orden de llegada
+	type indexed struct {
+		index int
+		item  models.BatchItem
+	}
+	numbered := make(chan indexed)
+	go func() {
+		defer close(numbered)
+		index := 0
+		for item := range in {
+			select {
+			case numbered <- indexed{index, item}:
+				index++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Workers: validación, rotación y QR en paralelo
+	stats := make(chan pendingStats, b.NodeBatchSize)
+	var wg sync.WaitGroup
+	for i := 0; i < b.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			noProgress := func(float64) {}
+			for in := range numbered {
+				if ctx.Err() != nil {
+					continue
+				}
+				operation := in.item.Options.Operation
+				if operation != "" && operation != models.JobOperationProcess && operation != models.JobOperationQR {
+					safeEmit(in.index, b.failed(in.item.ID, apperrors.New(apperrors.CodeBatchInvalidOperation, map[string]interface{}{"operation": operation}), opts.Language))
+					continue
+				}
+				response, err := b.Processor.Compute(ctx, in.item.Matrix, noProgress)
+				if err != nil {
+					safeEmit(in.index, b.failed(in.item.ID, err, opts.Language))
+					continue
+				}
+				if operation == models.JobOperationQR {
+					metrics.MatrixProcessTotal.WithLabelValues("ok").Inc()
+					safeEmit(in.index, models.BatchItemResult{ID: in.item.ID, MatrixProcessResponse: response})
+					continue
+				}
+				stats <- pendingStats{index: in.index, id: in.item.ID, response: response}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(stats)
+	}()
+
+	// Agrupar las peticiones de estadísticas: se envían al completar NodeBatchSize
+	// elementos, al vencer FlushInterval o al terminar la entrada
+	var batch []pendingStats
+	flush := func() {
+		if len(batch) > 0 {
+			b.fetchStats(ctx, batch, opts, safeEmit)
+			batch = nil
+		}
+	}
+	timer := time.NewTimer(b.FlushInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case p, ok := <-stats:
+			if !ok {
+				flush()
+				return
+			}
+			if len(batch) == 0 {
+				timer.Reset(b.FlushInterval)
+			}
+			batch = append(batch, p)
+			if len(batch) >= b.NodeBatchSize {
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+// fetchStats pide a Node.js las estadísticas de un lote y emite cada resultado.
+// Si la llamada falla, todos los elementos quedan como respuesta parcial (NODE_STATS_UNAVAILABLE).
+func (b *BatchProcessor) fetchStats(ctx context.Context, batch []pendingStats, opts ProcessOptions, emit EmitFunc) {
+	requests := make([]models.MatrixStatsRequest, len(batch))
+	for i, p := range batch {
+		requests[i] = models.MatrixStatsRequest{Q: p.response.Q, R: p.response.R, Rotated: p.response.Rotated}
+	}
+
+	results, err := b.Processor.NodeClient.GetMatrixStatsBatch(ctx, requests, opts.Token)
+	for i, p := range batch {
+		switch {
+		case err != nil:
+			SetStatsUnavailable(p.response, err, opts.Language)
+		case results[i].Error != "":
+			SetStatsUnavailable(p.response, errors.New(results[i].Error), opts.Language)
+		default:
+			metrics.MatrixProcessTotal.WithLabelValues("ok").Inc()
+			stats := results[i].MatrixStatsResponse
+			p.response.NodeStats = &stats
+		}
+		emit(p.index, models.BatchItemResult{ID: p.id, MatrixProcessResponse: p.response})
+	}
+}
+
+// failed construye el resultado de un elemento que no se pudo procesar
+func (b *BatchProcessor) failed(id string, err error, lang string) models.BatchItemResult {
+	problem := apperrors.From(err).Problem(lang)
+	return models.BatchItemResult{ID: id, Problem: &problem}
+}
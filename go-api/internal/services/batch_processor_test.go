@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+// fakeStatsBatchServer simula POST /matrix/stats/batch de Node.js: responde max = R[0][0]
+// y un error por elemento si R[0][0] es negativo. calls cuenta las llamadas recibidas.
+func fakeStatsBatchServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if r.URL.Path != "/matrix/stats/batch" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req models.MatrixStatsBatchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var resp models.MatrixStatsBatchResponse
+		for _, item := range req.Items {
+			var result models.MatrixStatsBatchResult
+			if item.Rotated[0][0] < 0 {
+				result.Error = "valor negativo"
+			} else {
+				result.Max = item.Rotated[0][0]
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBatchProcessor_Process(t *testing.T) {
+	var calls int32
+	server := fakeStatsBatchServer(t, &calls)
+
+	batch := NewBatchProcessor(NewMatrixProcessor(NewNodeClient(server.URL)), 3)
+	batch.NodeBatchSize = 2
+	// Un intervalo largo hace que los lotes de Node.js solo se envíen al completarse o al final
+	batch.FlushInterval = time.Minute
+
+	items := []models.BatchItem{
+		{ID: "a", Matrix: [][]float64{{1}}},
+		{ID: "b", Matrix: [][]float64{{1, 2}, {3}}},
+		{ID: "c", Matrix: [][]float64{{2}}},
+		{ID: "d", Matrix: [][]float64{{-5}}},
+		{ID: "e", Matrix: [][]float64{{3}}, Options: models.BatchItemOptions{Operation: models.JobOperationQR}},
+		{ID: "f", Matrix: [][]float64{{4}}},
+		{ID: "g", Matrix: [][]float64{{1}}, Options: models.BatchItemOptions{Operation: "svd"}},
+	}
+	results := batch.Process(context.Background(), items, ProcessOptions{Language: "es"})
+
+	if len(results) != len(items) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(items))
+	}
+	for i, result := range results {
+		if result.ID != items[i].ID {
+			t.Errorf("results[%d].ID = %q, want %q (orden de entrada)", i, result.ID, items[i].ID)
+		}
+	}
+
+	tests := []struct {
+		id          string
+		wantCode    string  // código del problem (vacío si el elemento se procesó)
+		wantMax     float64 // max de nodeStats (0 si no hay estadísticas)
+		wantPartial bool    // respuesta parcial con NODE_STATS_UNAVAILABLE
+	}{
+		{id: "a", wantMax: 1},
+		{id: "b", wantCode: "MATRIX_NOT_RECTANGULAR"},
+		{id: "c", wantMax: 2},
+		{id: "d", wantPartial: true},
+		{id: "e"},
+		{id: "f", wantMax: 4},
+		{id: "g", wantCode: "BATCH_INVALID_OPERATION"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			result := results[i]
+			if tt.wantCode != "" {
+				if result.Problem == nil || result.Problem.Code != tt.wantCode {
+					t.Errorf("Problem = %+v, want code %s", result.Problem, tt.wantCode)
+				}
+				return
+			}
+			if result.Problem != nil || result.MatrixProcessResponse == nil {
+				t.Fatalf("resultado = %+v, se esperaba una respuesta sin problem", result)
+			}
+			if tt.wantPartial {
+				if result.ErrorCode != "NODE_STATS_UNAVAILABLE" || result.NodeStats != nil {
+					t.Errorf("ErrorCode = %q, want NODE_STATS_UNAVAILABLE sin nodeStats", result.ErrorCode)
+				}
+				return
+			}
+			if tt.wantMax == 0 {
+				if result.NodeStats != nil {
+					t.Error("la operación qr no debe incluir nodeStats")
+				}
+				return
+			}
+			if result.NodeStats == nil || result.NodeStats.Max != tt.wantMax {
+				t.Errorf("NodeStats = %+v, want max %v", result.NodeStats, tt.wantMax)
+			}
+		})
+	}
+
+	// 4 elementos piden estadísticas (a, c, d, f) en lotes de 2
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("llamadas a Node.js = %d, want 2", got)
+	}
+}
+
+func TestBatchProcessor_NodeUnavailable(t *testing.T) {
+	nodeClient := NewNodeClient("http://localhost:9999")
+	nodeClient.MaxRetries = 0
+	batch := NewBatchProcessor(NewMatrixProcessor(nodeClient), 2)
+
+	results := batch.Process(context.Background(), []models.BatchItem{
+		{ID: "a", Matrix: [][]float64{{1, 2}, {3, 4}}},
+		{ID: "b", Matrix: [][]float64{{5}}},
+	}, ProcessOptions{Language: "es"})
+
+	for _, result := range results {
+		if result.MatrixProcessResponse == nil || result.ErrorCode != "NODE_STATS_UNAVAILABLE" || result.Q == nil {
+			t.Errorf("resultado %s = %+v, want respuesta parcial con Q/R y NODE_STATS_UNAVAILABLE", result.ID, result)
+		}
+	}
+}
+
+func TestBatchLimits_Validate(t *testing.T) {
+	limits := BatchLimits{MaxItems: 3, MaxElements: 6}
+
+	tests := []struct {
+		name     string
+		items    []models.BatchItem
+		wantCode apperrors.Code // vacío si el lote es válido
+	}{
+		{
+			name:  "lote válido",
+			items: []models.BatchItem{{ID: "a", Matrix: [][]float64{{1, 2}}}, {ID: "b", Matrix: [][]float64{{1, 2}, {3, 4}}}},
+		},
+		{
+			name:     "lote vacío",
+			items:    nil,
+			wantCode: apperrors.CodeBatchEmpty,
+		},
+		{
+			name:     "demasiados elementos del lote",
+			items:    []models.BatchItem{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}},
+			wantCode: apperrors.CodeBatchTooManyItems,
+		},
+		{
+			name:     "demasiados elementos de matriz",
+			items:    []models.BatchItem{{ID: "a", Matrix: [][]float64{{1, 2, 3}, {4, 5, 6}}}, {ID: "b", Matrix: [][]float64{{7}}}},
+			wantCode: apperrors.CodeBatchTooManyElements,
+		},
+		{
+			name:     "id duplicado",
+			items:    []models.BatchItem{{ID: "a"}, {ID: "a"}},
+			wantCode: apperrors.CodeBatchDuplicateID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.Validate(tt.items)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, apperrors.New(tt.wantCode, nil)) {
+				t.Errorf("Validate() = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestBatchLimits_Validate_DefaultIDs(t *testing.T) {
+	items := []models.BatchItem{{Matrix: [][]float64{{1}}}, {ID: "x", Matrix: [][]float64{{1}}}, {Matrix: [][]float64{{1}}}}
+	if err := (BatchLimits{}).Validate(items); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	for i, want := range []string{"0", "x", "2"} {
+		if items[i].ID != want {
+			t.Errorf("items[%d].ID = %q, want %q", i, items[i].ID, want)
+		}
+	}
+}
+
+func TestBatchTracker_Add(t *testing.T) {
+	tracker := BatchLimits{MaxItems: 3, MaxElements: 4}.Tracker()
+
+	steps := []struct {
+		item     models.BatchItem
+		wantCode apperrors.Code // vacío si se acepta
+	}{
+		{item: models.BatchItem{ID: "a", Matrix: [][]float64{{1, 2}}}},
+		{item: models.BatchItem{ID: "a", Matrix: [][]float64{{1}}}, wantCode: apperrors.CodeBatchDuplicateID},
+		{item: models.BatchItem{ID: "b", Matrix: [][]float64{{1, 2}, {3, 4}}}, wantCode: apperrors.CodeBatchTooManyElements},
+		{item: models.BatchItem{ID: "c", Matrix: [][]float64{{1}}}},
+		{item: models.BatchItem{ID: "d", Matrix: [][]float64{{1}}}},
+		{item: models.BatchItem{ID: "e", Matrix: [][]float64{{1}}}, wantCode: apperrors.CodeBatchTooManyItems},
+	}
+
+	for i, step := range steps {
+		err := tracker.Add(step.item)
+		if step.wantCode == "" {
+			if err != nil {
+				t.Errorf("paso %d: Add() = %v, want nil", i, err)
+			}
+			continue
+		}
+		if !errors.Is(err, apperrors.New(step.wantCode, nil)) {
+			t.Errorf("paso %d: Add() = %v, want %s", i, err, step.wantCode)
+		}
+	}
+}
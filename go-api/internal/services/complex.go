@@ -0,0 +1,147 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"time"
+
+	"go-api/internal/metrics"
+	"go-api/internal/models"
+)
+
+// ComplexQRDecomposition calcula la factorización QR unitaria de una matriz compleja con
+// reflexiones de Householder: Q (rows×rows) es unitaria (Q^H·Q = I) y R (rows×cols) es
+// triangular superior con la diagonal real y no negativa, de forma que A = Q·R.
+// A diferencia de QRDecomposition acepta matrices con más columnas que filas.
+func ComplexQRDecomposition(matrix [][]complex128) ([][]complex128, [][]complex128, error) {
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		return nil, nil, fmt.Errorf("la matriz no puede estar vacía")
+	}
+
+	rows := len(matrix)
+	cols := len(matrix[0])
+	start := time.Now()
+	defer func() {
+		metrics.QRDuration.WithLabelValues(metrics.SizeBucket(rows, cols)).Observe(time.Since(start).Seconds())
+	}()
+
+	R := make([][]complex128, rows)
+	Q := make([][]complex128, rows)
+	for i := range R {
+		R[i] = append([]complex128(nil), matrix[i]...)
+		Q[i] = make([]complex128, rows)
+		Q[i][i] = 1
+	}
+
+	steps := min(rows, cols)
+	v := make([]complex128, rows)
+	for k := 0; k < steps; k++ {
+		// x = R[k:, k] se refleja sobre beta·e1, con beta de fase opuesta a x[0] para no restar
+		// números parecidos
+		var norm float64
+		for i := k; i < rows; i++ {
+			norm = math.Hypot(norm, cmplx.Abs(R[i][k]))
+		}
+		if norm == 0 {
+			continue
+		}
+		phase := complex(1, 0)
+		if R[k][k] != 0 {
+			phase = R[k][k] / complex(cmplx.Abs(R[k][k]), 0)
+		}
+		beta := -phase * complex(norm, 0)
+
+		var vNorm2 float64
+		for i := k; i < rows; i++ {
+			v[i] = R[i][k]
+			if i == k {
+				v[i] -= beta
+			}
+			vNorm2 += real(v[i])*real(v[i]) + imag(v[i])*imag(v[i])
+		}
+		scale := complex(2/vNorm2, 0)
+
+		// R = H·R con H = I - 2·v·v^H / (v^H·v), solo las columnas que quedan
+		R[k][k] = beta
+		for i := k + 1; i < rows; i++ {
+			R[i][k] = 0
+		}
+		for j := k + 1; j < cols; j++ {
+			var s complex128
+			for i := k; i < rows; i++ {
+				s += cmplx.Conj(v[i]) * R[i][j]
+			}
+			s *= scale
+			for i := k; i < rows; i++ {
+				R[i][j] -= s * v[i]
+			}
+		}
+
+		// Q = Q·H (H es hermítica)
+		for r := 0; r < rows; r++ {
+			var s complex128
+			for i := k; i < rows; i++ {
+				s += Q[r][i] * v[i]
+			}
+			s *= scale
+			for i := k; i < rows; i++ {
+				Q[r][i] -= s * cmplx.Conj(v[i])
+			}
+		}
+	}
+
+	// Llevar la diagonal de R a reales no negativos: A = (Q·D)·(D^H·R) con D diagonal de fases
+	for k := 0; k < steps; k++ {
+		d := R[k][k]
+		if d == 0 {
+			continue
+		}
+		phase := d / complex(cmplx.Abs(d), 0)
+		for j := k; j < cols; j++ {
+			R[k][j] *= cmplx.Conj(phase)
+		}
+		R[k][k] = complex(cmplx.Abs(d), 0)
+		for r := 0; r < rows; r++ {
+			Q[r][k] *= phase
+		}
+	}
+
+	return Q, R, nil
+}
+
+// complexFromModel convierte una matriz de models.Complex a complex128
+func complexFromModel(matrix [][]models.Complex) [][]complex128 {
+	out := make([][]complex128, len(matrix))
+	for i, row := range matrix {
+		out[i] = make([]complex128, len(row))
+		for j, value := range row {
+			out[i][j] = complex(value.Re, value.Im)
+		}
+	}
+	return out
+}
+
+// complexToModel convierte una matriz complex128 a models.Complex
+func complexToModel(matrix [][]complex128) [][]models.Complex {
+	out := make([][]models.Complex, len(matrix))
+	for i, row := range matrix {
+		out[i] = make([]models.Complex, len(row))
+		for j, value := range row {
+			out[i][j] = models.Complex{Re: real(value), Im: imag(value)}
+		}
+	}
+	return out
+}
+
+// magnitudes matriz con el módulo de cada elemento
+func magnitudes(matrix [][]complex128) [][]float64 {
+	out := make([][]float64, len(matrix))
+	for i, row := range matrix {
+		out[i] = make([]float64, len(row))
+		for j, value := range row {
+			out[i][j] = cmplx.Abs(value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+func TestComplexQRDecomposition(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomMatrix := func(rows, cols int) [][]complex128 {
+		m := make([][]complex128, rows)
+		for i := range m {
+			m[i] = make([]complex128, cols)
+			for j := range m[i] {
+				m[i][j] = complex(random.NormFloat64(), random.NormFloat64())
+			}
+		}
+		return m
+	}
+
+	tests := []struct {
+		name   string
+		matrix [][]complex128
+	}{
+		{name: "2x2 compleja", matrix: [][]complex128{{1 + 2i, 3 - 1i}, {-2i, 4}}},
+		{name: "solo real", matrix: [][]complex128{{1, 2}, {3, 4}}},
+		{name: "solo imaginaria", matrix: [][]complex128{{1i, 2i}, {3i, 4i}}},
+		{name: "diagonal con fase negativa", matrix: [][]complex128{{-2, 0}, {0, -3i}}},
+		{name: "con más filas que columnas", matrix: randomMatrix(5, 3)},
+		{name: "con más columnas que filas", matrix: randomMatrix(2, 4)},
+		{name: "rango deficiente", matrix: [][]complex128{{1 + 1i, 2 + 2i}, {1 - 1i, 2 - 2i}, {0, 0}}},
+		{name: "columna nula", matrix: [][]complex128{{0, 1}, {0, 1i}}},
+		{name: "10x10 aleatoria", matrix: randomMatrix(10, 10)},
+	}
+
+	const tolerance = 1e-10
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Q, R, err := ComplexQRDecomposition(tt.matrix)
+			if err != nil {
+				t.Fatalf("ComplexQRDecomposition() error = %v", err)
+			}
+			rows, cols := len(tt.matrix), len(tt.matrix[0])
+			if len(Q) != rows || len(Q[0]) != rows || len(R) != rows || len(R[0]) != cols {
+				t.Fatalf("Q es %dx%d y R %dx%d, want %dx%d y %dx%d", len(Q), len(Q[0]), len(R), len(R[0]), rows, rows, rows, cols)
+			}
+
+			// Q^H·Q = I
+			for i := 0; i < rows; i++ {
+				for j := 0; j < rows; j++ {
+					var sum complex128
+					for k := 0; k < rows; k++ {
+						sum += cmplx.Conj(Q[k][i]) * Q[k][j]
+					}
+					want := complex128(0)
+					if i == j {
+						want = 1
+					}
+					if cmplx.Abs(sum-want) > tolerance {
+						t.Errorf("(Q^H·Q)[%d][%d] = %v, want %v", i, j, sum, want)
+					}
+				}
+			}
+
+			// R triangular superior con la diagonal real no negativa
+			for i := 0; i < rows; i++ {
+				for j := 0; j < cols; j++ {
+					switch {
+					case j < i && cmplx.Abs(R[i][j]) > tolerance:
+						t.Errorf("R[%d][%d] = %v, debería ser 0", i, j, R[i][j])
+					case j == i && (imag(R[i][i]) != 0 || real(R[i][i]) < 0):
+						t.Errorf("R[%d][%d] = %v, debería ser real no negativo", i, i, R[i][i])
+					}
+				}
+			}
+
+			// Q·R = A
+			for i := 0; i < rows; i++ {
+				for j := 0; j < cols; j++ {
+					var sum complex128
+					for k := 0; k < rows; k++ {
+						sum += Q[i][k] * R[k][j]
+					}
+					if cmplx.Abs(sum-tt.matrix[i][j]) > tolerance {
+						t.Errorf("(Q·R)[%d][%d] = %v, want %v", i, j, sum, tt.matrix[i][j])
+					}
+				}
+			}
+		})
+	}
+
+	if _, _, err := ComplexQRDecomposition(nil); err == nil {
+		t.Errorf("ComplexQRDecomposition(nil) debería fallar")
+	}
+}
+
+func TestMatrixProcessor_ProcessComplex(t *testing.T) {
+	// Node.js no disponible: el resultado es parcial pero la rotación y QR se calculan
+	nodeClient := NewNodeClient("http://localhost:9999")
+	nodeClient.MaxRetries = 0
+	processor := NewMatrixProcessor(nodeClient)
+
+	matrix := [][]models.Complex{{{Re: 1, Im: 1}, {Re: 2}}, {{Im: -3}, {Re: 4, Im: 4}}}
+	response, err := processor.ProcessComplex(context.Background(), matrix, ProcessOptions{})
+	if err != nil {
+		t.Fatalf("ProcessComplex() error = %v", err)
+	}
+	if response.Q != nil || response.R != nil || response.Rotated != nil {
+		t.Errorf("las matrices reales deben quedar vacías: %+v", response)
+	}
+	if got := response.RotatedComplex[0][0]; got != (models.Complex{Im: -3}) {
+		t.Errorf("rotated[0][0] = %v, want -3i", got)
+	}
+	// |A[0][0]|² + |A[1][0]|² = 2 + 9
+	if got := response.RComplex[0][0]; got.Im != 0 || math.Abs(got.Re-math.Sqrt(11)) > 1e-12 {
+		t.Errorf("R[0][0] = %v, want √11", got)
+	}
+	if response.ErrorCode != string(apperrors.CodeNodeStatsUnavailable) {
+		t.Errorf("errorCode = %q, want NODE_STATS_UNAVAILABLE", response.ErrorCode)
+	}
+
+	_, err = processor.ProcessComplex(context.Background(), [][]models.Complex{{{Re: 1}}, {}}, ProcessOptions{})
+	if !errors.Is(err, apperrors.New(apperrors.CodeMatrixNotRectangular, nil)) {
+		t.Errorf("error = %v, want MATRIX_NOT_RECTANGULAR", err)
+	}
+}
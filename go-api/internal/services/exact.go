@@ -0,0 +1,314 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+// DefaultExactMaxDimension filas o columnas máximas del modo exacto: con big.Rat el costo
+// crece con el tamaño de numeradores y denominadores, que se multiplican en cada paso
+const DefaultExactMaxDimension = 12
+
+// exactNumber formas aceptadas de un número exacto: entero o decimal con exponente de hasta
+// tres cifras, o fracción de enteros decimales. Sin exponentes grandes ni prefijos de base
+// (big.Rat aceptaría "1e999999999" o "0x10/3").
+var exactNumber = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d{1,3})?$`)
+var exactFraction = regexp.MustCompile(`^([+-]?\d+)/(\d+)$`)
+
+// exactMaxLength largo máximo del texto de cada número
+const exactMaxLength = 64
+
+// ExactQR factorización exacta por Gram-Schmidt A = V·T: V (rows×cols) tiene columnas
+// ortogonales sin normalizar y T (cols×cols) es triangular superior con unos en la diagonal
+type ExactQR struct {
+	Basis        [][]*big.Rat
+	Coefficients [][]*big.Rat
+	// Norms2 ⟨v_j, v_j⟩ de cada columna de V; 0 si la columna de A depende de las anteriores
+	Norms2 []*big.Rat
+	Rank   int
+}
+
+// ProcessExact calcula en aritmética exacta la factorización de Gram-Schmidt de la matriz
+// del pedido y, si trae b, la solución de mínimos cuadrados de A·x = b. La matriz debe ser
+// rectangular y tener a lo sumo maxDimension filas y columnas (EXACT_TOO_LARGE).
+func ProcessExact(req *models.ExactMatrixRequest, maxDimension int) (*models.ExactQRResponse, error) {
+	if err := ValidateMatrix(req.Matrix); err != nil {
+		return nil, err
+	}
+	rows, cols := len(req.Matrix), len(req.Matrix[0])
+	if rows > maxDimension || cols > maxDimension {
+		return nil, apperrors.New(apperrors.CodeExactTooLarge, map[string]interface{}{"rows": rows, "cols": cols, "max": maxDimension})
+	}
+
+	matrix := make([][]*big.Rat, rows)
+	for i, row := range req.Matrix {
+		matrix[i] = make([]*big.Rat, cols)
+		for j, value := range row {
+			r, err := parseFraction(fmt.Sprintf("matrix[%d][%d]", i, j), value)
+			if err != nil {
+				return nil, err
+			}
+			matrix[i][j] = r
+		}
+	}
+	var b []*big.Rat
+	for i, value := range req.B {
+		r, err := parseFraction(fmt.Sprintf("b[%d]", i), value)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, r)
+	}
+
+	qr := ExactGramSchmidt(matrix)
+	response := &models.ExactQRResponse{
+		Basis:        fractions2D(qr.Basis),
+		Coefficients: fractions2D(qr.Coefficients),
+		Norms2:       fractions(qr.Norms2),
+		Q:            make([][]string, rows),
+		R:            make([][]string, cols),
+		Rank:         qr.Rank,
+	}
+
+	// Q[i][j] = V[i][j] / √d_j = (V[i][j] / d_j)·√d_j y R[i][j] = √d_i·T[i][j]
+	roots := make([]surd, cols)
+	for j, d := range qr.Norms2 {
+		roots[j] = sqrtRat(d)
+	}
+	for i := range response.Q {
+		response.Q[i] = make([]string, cols)
+		for j := range response.Q[i] {
+			response.Q[i][j] = "0"
+			if qr.Norms2[j].Sign() != 0 {
+				coef := new(big.Rat).Quo(qr.Basis[i][j], qr.Norms2[j])
+				response.Q[i][j] = roots[j].scaled(coef)
+			}
+		}
+	}
+	for i := range response.R {
+		response.R[i] = make([]string, cols)
+		for j := range response.R[i] {
+			response.R[i][j] = roots[i].scaled(qr.Coefficients[i][j])
+		}
+	}
+
+	if req.B != nil {
+		x, residual2, err := SolveExact(qr, matrix, b)
+		if err != nil {
+			return nil, err
+		}
+		response.Solution = &models.ExactSolution{X: fractions(x), Residual2: models.Fraction(residual2.RatString())}
+	}
+	return response, nil
+}
+
+// parseFraction convierte el texto en un racional (EXACT_INVALID_NUMBER si no es válido)
+func parseFraction(position string, value models.Fraction) (*big.Rat, error) {
+	text := strings.TrimSpace(string(value))
+	invalid := apperrors.New(apperrors.CodeExactInvalidNumber, map[string]interface{}{"position": position, "value": string(value)})
+	if len(text) > exactMaxLength {
+		return nil, invalid
+	}
+
+	// En las fracciones big.Rat lee "010" como octal: numerador y denominador van en base 10
+	if parts := exactFraction.FindStringSubmatch(text); parts != nil {
+		num, _ := new(big.Int).SetString(strings.TrimPrefix(parts[1], "+"), 10)
+		den, _ := new(big.Int).SetString(parts[2], 10)
+		if den.Sign() == 0 {
+			return nil, invalid
+		}
+		return new(big.Rat).SetFrac(num, den), nil
+	}
+	if !exactNumber.MatchString(text) {
+		return nil, invalid
+	}
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, invalid
+	}
+	return r, nil
+}
+
+// ExactGramSchmidt ortogonaliza las columnas de la matriz sin normalizarlas:
+// v_j = a_j - Σ_{i<j} T[i][j]·v_i con T[i][j] = ⟨a_j, v_i⟩ / ⟨v_i, v_i⟩. En aritmética
+// exacta el Gram-Schmidt clásico no pierde ortogonalidad. Las columnas que dependen de las
+// anteriores quedan en cero (y no cuentan para el rango).
+func ExactGramSchmidt(matrix [][]*big.Rat) *ExactQR {
+	rows, cols := len(matrix), len(matrix[0])
+	qr := &ExactQR{
+		Basis:        make([][]*big.Rat, rows),
+		Coefficients: make([][]*big.Rat, cols),
+		Norms2:       make([]*big.Rat, cols),
+	}
+	for i := range qr.Basis {
+		qr.Basis[i] = make([]*big.Rat, cols)
+	}
+	for i := range qr.Coefficients {
+		qr.Coefficients[i] = make([]*big.Rat, cols)
+		for j := range qr.Coefficients[i] {
+			qr.Coefficients[i][j] = new(big.Rat)
+		}
+		qr.Coefficients[i][i].SetInt64(1)
+	}
+
+	column := func(m [][]*big.Rat, j int) []*big.Rat {
+		out := make([]*big.Rat, len(m))
+		for i := range m {
+			out[i] = m[i][j]
+		}
+		return out
+	}
+
+	term := new(big.Rat)
+	for j := 0; j < cols; j++ {
+		a := column(matrix, j)
+		v := make([]*big.Rat, rows)
+		for k := range v {
+			v[k] = new(big.Rat).Set(a[k])
+		}
+		for i := 0; i < j; i++ {
+			if qr.Norms2[i].Sign() == 0 {
+				continue
+			}
+			basis := column(qr.Basis, i)
+			t := qr.Coefficients[i][j].Quo(dotRat(a, basis), qr.Norms2[i])
+			for k := range v {
+				v[k].Sub(v[k], term.Mul(t, basis[k]))
+			}
+		}
+		for k := range v {
+			qr.Basis[k][j] = v[k]
+		}
+		qr.Norms2[j] = dotRat(v, v)
+		if qr.Norms2[j].Sign() != 0 {
+			qr.Rank++
+		}
+	}
+	return qr
+}
+
+// SolveExact resuelve A·x = b por mínimos cuadrados en aritmética exacta con la
+// factorización de Gram-Schmidt de A: de Aᵀ·A·x = Aᵀ·b queda D·T·x = Vᵀ·b, que se resuelve
+// hacia atrás porque T es triangular con unos en la diagonal. Retorna x y ‖A·x - b‖².
+// Como SolveLeastSquares, exige rango completo (MATRIX_RANK_DEFICIENT).
+func SolveExact(qr *ExactQR, matrix [][]*big.Rat, b []*big.Rat) ([]*big.Rat, *big.Rat, error) {
+	rows, cols := len(matrix), len(matrix[0])
+	if len(b) != rows {
+		return nil, nil, apperrors.New(apperrors.CodeSolveDimensionMismatch, map[string]interface{}{"rows": rows, "length": len(b)})
+	}
+	if qr.Rank < cols {
+		return nil, nil, apperrors.New(apperrors.CodeMatrixRankDeficient, map[string]interface{}{"rank": qr.Rank, "cols": cols})
+	}
+
+	x := make([]*big.Rat, cols)
+	term := new(big.Rat)
+	for i := cols - 1; i >= 0; i-- {
+		basis := make([]*big.Rat, rows)
+		for k := range basis {
+			basis[k] = qr.Basis[k][i]
+		}
+		x[i] = new(big.Rat).Quo(dotRat(basis, b), qr.Norms2[i])
+		for j := i + 1; j < cols; j++ {
+			x[i].Sub(x[i], term.Mul(qr.Coefficients[i][j], x[j]))
+		}
+	}
+
+	residual2 := new(big.Rat)
+	for i, row := range matrix {
+		r := new(big.Rat).Neg(b[i])
+		for j, value := range row {
+			r.Add(r, term.Mul(value, x[j]))
+		}
+		residual2.Add(residual2, r.Mul(r, r))
+	}
+	return x, residual2, nil
+}
+
+// dotRat producto escalar exacto
+func dotRat(a, b []*big.Rat) *big.Rat {
+	sum := new(big.Rat)
+	term := new(big.Rat)
+	for k := range a {
+		sum.Add(sum, term.Mul(a[k], b[k]))
+	}
+	return sum
+}
+
+// surd raíz cuadrada de un racional en la forma coef·√radicand, con radicand entero
+type surd struct {
+	coef     *big.Rat
+	radicand *big.Int
+}
+
+// maxSquareFactor mayor factor k cuyo k² se busca por división al simplificar una raíz
+const maxSquareFactor = 1000
+
+// sqrtRat escribe √(p/q) como (1/q)·√(p·q) y saca de la raíz los cuadrados perfectos
+// (k² con k hasta maxSquareFactor, o el radicando completo si es un cuadrado)
+func sqrtRat(d *big.Rat) surd {
+	n := new(big.Int).Mul(d.Num(), d.Denom())
+	coef := new(big.Rat).SetFrac(big.NewInt(1), d.Denom())
+	if n.Sign() == 0 {
+		return surd{coef: new(big.Rat), radicand: big.NewInt(1)}
+	}
+
+	factor, square, quo, rem := new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	for k := int64(2); k <= maxSquareFactor; k++ {
+		factor.SetInt64(k)
+		square.Mul(factor, factor)
+		if square.Cmp(n) > 0 {
+			break
+		}
+		for {
+			quo.QuoRem(n, square, rem)
+			if rem.Sign() != 0 {
+				break
+			}
+			n.Set(quo)
+			coef.Mul(coef, new(big.Rat).SetInt(factor))
+		}
+	}
+	if root := new(big.Int).Sqrt(n); new(big.Int).Mul(root, root).Cmp(n) == 0 {
+		coef.Mul(coef, new(big.Rat).SetInt(root))
+		n.SetInt64(1)
+	}
+	return surd{coef: coef, radicand: n}
+}
+
+// scaled escribe factor·coef·√radicand: "a/b", "√c", "-√c" o "a/b·√c"
+func (s surd) scaled(factor *big.Rat) string {
+	c := new(big.Rat).Mul(factor, s.coef)
+	switch {
+	case c.Sign() == 0:
+		return "0"
+	case s.radicand.IsInt64() && s.radicand.Int64() == 1:
+		return c.RatString()
+	case c.Cmp(big.NewRat(1, 1)) == 0:
+		return "√" + s.radicand.String()
+	case c.Cmp(big.NewRat(-1, 1)) == 0:
+		return "-√" + s.radicand.String()
+	}
+	return c.RatString() + "·√" + s.radicand.String()
+}
+
+// fractions convierte racionales a su forma reducida
+func fractions(values []*big.Rat) []models.Fraction {
+	out := make([]models.Fraction, len(values))
+	for i, value := range values {
+		out[i] = models.Fraction(value.RatString())
+	}
+	return out
+}
+
+func fractions2D(matrix [][]*big.Rat) [][]models.Fraction {
+	out := make([][]models.Fraction, len(matrix))
+	for i, row := range matrix {
+		out[i] = fractions(row)
+	}
+	return out
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+func TestProcessExact(t *testing.T) {
+	t.Run("Gram-Schmidt 3x2 con Q y R simbólicas", func(t *testing.T) {
+		// a1 = (1, 1, 0), a2 = (1, 0, 1): v2 = a2 - 1/2·v1 = (1/2, -1/2, 1)
+		req := &models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1", "1"}, {"1", "0"}, {"0", "1"}}}
+		response, err := ProcessExact(req, DefaultExactMaxDimension)
+		if err != nil {
+			t.Fatalf("ProcessExact() error = %v", err)
+		}
+		want := &models.ExactQRResponse{
+			Basis:        [][]models.Fraction{{"1", "1/2"}, {"1", "-1/2"}, {"0", "1"}},
+			Coefficients: [][]models.Fraction{{"1", "1/2"}, {"0", "1"}},
+			Norms2:       []models.Fraction{"2", "3/2"},
+			Q:            [][]string{{"1/2·√2", "1/6·√6"}, {"1/2·√2", "-1/6·√6"}, {"0", "1/3·√6"}},
+			R:            [][]string{{"√2", "1/2·√2"}, {"0", "1/2·√6"}},
+			Rank:         2,
+		}
+		if !reflect.DeepEqual(response, want) {
+			t.Errorf("respuesta = %+v\nwant %+v", response, want)
+		}
+	})
+
+	t.Run("la base es ortogonal y A = V·T", func(t *testing.T) {
+		matrix := [][]*big.Rat{
+			{big.NewRat(1, 3), big.NewRat(2, 1), big.NewRat(-1, 7)},
+			{big.NewRat(4, 5), big.NewRat(0, 1), big.NewRat(3, 2)},
+			{big.NewRat(-2, 1), big.NewRat(1, 9), big.NewRat(5, 1)},
+			{big.NewRat(1, 1), big.NewRat(1, 1), big.NewRat(1, 1)},
+		}
+		qr := ExactGramSchmidt(matrix)
+		column := func(j int) []*big.Rat {
+			out := make([]*big.Rat, len(qr.Basis))
+			for i := range qr.Basis {
+				out[i] = qr.Basis[i][j]
+			}
+			return out
+		}
+		for i := 0; i < 3; i++ {
+			for j := i + 1; j < 3; j++ {
+				if dot := dotRat(column(i), column(j)); dot.Sign() != 0 {
+					t.Errorf("⟨v%d, v%d⟩ = %s, want 0", i, j, dot.RatString())
+				}
+			}
+		}
+		for i := range matrix {
+			for j := range matrix[i] {
+				sum := new(big.Rat)
+				for k := 0; k < 3; k++ {
+					sum.Add(sum, new(big.Rat).Mul(qr.Basis[i][k], qr.Coefficients[k][j]))
+				}
+				if sum.Cmp(matrix[i][j]) != 0 {
+					t.Errorf("(V·T)[%d][%d] = %s, want %s", i, j, sum.RatString(), matrix[i][j].RatString())
+				}
+			}
+		}
+	})
+
+	t.Run("columna dependiente", func(t *testing.T) {
+		req := &models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1", "2"}, {"1/3", "2/3"}}}
+		response, err := ProcessExact(req, DefaultExactMaxDimension)
+		if err != nil {
+			t.Fatalf("ProcessExact() error = %v", err)
+		}
+		if response.Rank != 1 || response.Norms2[1] != "0" || response.Q[0][1] != "0" {
+			t.Errorf("respuesta = %+v, want rango 1 con la segunda columna en cero", response)
+		}
+	})
+
+	tests := []struct {
+		name          string
+		req           models.ExactMatrixRequest
+		wantX         []models.Fraction
+		wantResidual2 models.Fraction
+		wantCode      apperrors.Code
+	}{
+		{
+			name:  "sistema cuadrado con fracciones",
+			req:   models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1/3", "1"}, {"2", "-1/2"}}, B: []models.Fraction{"1", "0.5"}},
+			wantX: []models.Fraction{"6/13", "11/13"}, wantResidual2: "0",
+		},
+		{
+			name:  "mínimos cuadrados 3x2",
+			req:   models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1", "0"}, {"0", "1"}, {"1", "1"}}, B: []models.Fraction{"1", "1", "0"}},
+			wantX: []models.Fraction{"1/3", "1/3"}, wantResidual2: "4/3",
+		},
+		{
+			name:     "rango deficiente",
+			req:      models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1", "2"}, {"2", "4"}}, B: []models.Fraction{"1", "2"}},
+			wantCode: apperrors.CodeMatrixRankDeficient,
+		},
+		{
+			name:     "b de otro largo",
+			req:      models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1"}}, B: []models.Fraction{"1", "2"}},
+			wantCode: apperrors.CodeSolveDimensionMismatch,
+		},
+		{
+			name:     "denominador cero",
+			req:      models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1/0"}}},
+			wantCode: apperrors.CodeExactInvalidNumber,
+		},
+		{
+			name:     "exponente demasiado grande",
+			req:      models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1e999999"}}},
+			wantCode: apperrors.CodeExactInvalidNumber,
+		},
+		{
+			name:     "prefijo de base",
+			req:      models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"0x10"}}},
+			wantCode: apperrors.CodeExactInvalidNumber,
+		},
+		{
+			name:     "matriz no rectangular",
+			req:      models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1", "2"}, {"3"}}},
+			wantCode: apperrors.CodeMatrixNotRectangular,
+		},
+		{
+			name:     "supera el tamaño máximo",
+			req:      models.ExactMatrixRequest{Matrix: make([][]models.Fraction, DefaultExactMaxDimension+1)},
+			wantCode: apperrors.CodeExactTooLarge,
+		},
+	}
+	for i := range tests[len(tests)-1].req.Matrix {
+		tests[len(tests)-1].req.Matrix[i] = []models.Fraction{"1"}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := ProcessExact(&tt.req, DefaultExactMaxDimension)
+			if tt.wantCode != "" {
+				if !errors.Is(err, apperrors.New(tt.wantCode, nil)) {
+					t.Errorf("error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessExact() error = %v", err)
+			}
+			if !reflect.DeepEqual(response.Solution.X, tt.wantX) || response.Solution.Residual2 != tt.wantResidual2 {
+				t.Errorf("solución = %+v, want x = %v y residual2 = %s", response.Solution, tt.wantX, tt.wantResidual2)
+			}
+		})
+	}
+
+	// El error de número inválido indica la posición
+	_, err := ProcessExact(&models.ExactMatrixRequest{Matrix: [][]models.Fraction{{"1"}}, B: []models.Fraction{"uno"}}, DefaultExactMaxDimension)
+	if details := apperrors.From(err).Details; details["position"] != "b[0]" || details["value"] != "uno" {
+		t.Errorf("details = %v, want position b[0] y value uno", details)
+	}
+}
+
+func TestSqrtRat(t *testing.T) {
+	tests := []struct {
+		value  *big.Rat
+		factor *big.Rat
+		want   string
+	}{
+		{big.NewRat(4, 1), big.NewRat(1, 1), "2"},
+		{big.NewRat(8, 1), big.NewRat(1, 1), "2·√2"},
+		{big.NewRat(1, 2), big.NewRat(1, 1), "1/2·√2"},
+		{big.NewRat(9, 4), big.NewRat(-1, 3), "-1/2"},
+		{big.NewRat(3, 1), big.NewRat(-1, 1), "-√3"},
+		{big.NewRat(0, 1), big.NewRat(5, 1), "0"},
+		{big.NewRat(50, 27), big.NewRat(1, 1), "5/9·√6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value.RatString(), func(t *testing.T) {
+			if got := sqrtRat(tt.value).scaled(tt.factor); got != tt.want {
+				t.Errorf("%s·√%s = %q, want %q", tt.factor.RatString(), tt.value.RatString(), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+// DefaultExplainMaxElements elementos (rows·cols) máximos del modo explicación: la traza
+// tiene O(cols²) pasos con un vector de rows elementos cada uno
+const DefaultExplainMaxElements = 400
+
+// CheckExplain valida el tamaño de la matriz para el modo explicación (EXPLAIN_TOO_LARGE).
+// La matriz debe estar validada.
+func CheckExplain(matrix [][]float64, maxElements int) error {
+	rows, cols := len(matrix), len(matrix[0])
+	if rows*cols > maxElements {
+		return apperrors.New(apperrors.CodeExplainTooLarge, map[string]interface{}{
+			"rows": rows, "cols": cols, "elements": rows * cols, "max": maxElements,
+		})
+	}
+	return nil
+}
+
+// ExplainGramSchmidt calcula la QR reducida con Gram-Schmidt (method es
+// models.GramSchmidtClassical o models.GramSchmidtModified) registrando cada paso: el
+// inicio v_j = a_j, cada coeficiente r_ij con la resta v_j -= r_ij·q_i y la normalización
+// q_j = v_j / r_jj. En el clásico r_ij = q_i·a_j y en el modificado r_ij = q_i·v_j, con v_j
+// ya reducido por q_0..q_{i-1}. Una columna linealmente dependiente de las anteriores deja
+// r_jj = 0 y q_j nulo. Retorna la traza y la factorización reducida que construye: Q de
+// rows×cols y R de cols×cols con la diagonal no negativa.
+func ExplainGramSchmidt(matrix [][]float64, method string) (explanation *models.GramSchmidtExplanation, Q, R [][]float64) {
+	rows, cols := len(matrix), len(matrix[0])
+	column := func(j int) []float64 {
+		out := make([]float64, rows)
+		for i := range out {
+			out[i] = matrix[i][j]
+		}
+		return out
+	}
+	dot := func(a, b []float64) float64 {
+		sum := 0.0
+		for i := range a {
+			sum += a[i] * b[i]
+		}
+		return sum
+	}
+	// Una norma por debajo de esta tolerancia relativa a la de a_j se toma como cero
+	tolerance := float64(rows) * 0x1p-52
+
+	explanation = &models.GramSchmidtExplanation{
+		Method: method,
+		Steps:  make([]models.GramSchmidtStep, 0, cols*(cols+3)/2),
+	}
+	Q, R = make([][]float64, rows), make([][]float64, cols)
+	for i := range Q {
+		Q[i] = make([]float64, cols)
+	}
+	for i := range R {
+		R[i] = make([]float64, cols)
+	}
+	q := make([][]float64, cols)
+
+	for j := 0; j < cols; j++ {
+		a := column(j)
+		v := append([]float64(nil), a...)
+		explanation.Steps = append(explanation.Steps, models.GramSchmidtStep{
+			Kind:   models.GramSchmidtStepStart,
+			I:      j,
+			J:      j,
+			Vector: append([]float64(nil), v...),
+			LaTeX:  fmt.Sprintf("v_{%d} = a_{%d} = %s", j+1, j+1, latexVector(v)),
+		})
+
+		for i := 0; i < j; i++ {
+			projected, symbol := a, fmt.Sprintf("a_{%d}", j+1)
+			if method == models.GramSchmidtModified {
+				projected, symbol = v, fmt.Sprintf("v_{%d}", j+1)
+			}
+			r := dot(q[i], projected)
+			before := append([]float64(nil), v...)
+			for k := range v {
+				v[k] -= r * q[i][k]
+			}
+			R[i][j] = r
+			explanation.Steps = append(explanation.Steps, models.GramSchmidtStep{
+				Kind:        models.GramSchmidtStepProjection,
+				I:           i,
+				J:           j,
+				Coefficient: r,
+				Before:      before,
+				Vector:      append([]float64(nil), v...),
+				LaTeX: fmt.Sprintf("r_{%d,%d} = q_{%d}^{T} %s = %s,\\quad v_{%d} = %s - %s\\, q_{%d} = %s",
+					i+1, j+1, i+1, symbol, latexNumber(r),
+					j+1, latexVector(before), latexNumber(r), i+1, latexVector(v)),
+			})
+		}
+
+		norm := math.Sqrt(dot(v, v))
+		step := models.GramSchmidtStep{Kind: models.GramSchmidtStepNormalization, I: j, J: j, Before: append([]float64(nil), v...)}
+		q[j] = make([]float64, rows)
+		if norm <= tolerance*math.Sqrt(dot(a, a)) {
+			step.LaTeX = fmt.Sprintf("r_{%d,%d} = \\lVert v_{%d} \\rVert = 0,\\quad a_{%d} \\in \\operatorname{span}(a_{1}, \\dots, a_{%d}) \\Rightarrow q_{%d} = 0",
+				j+1, j+1, j+1, j+1, j, j+1)
+			if j == 0 {
+				step.LaTeX = "r_{1,1} = \\lVert v_{1} \\rVert = 0,\\quad a_{1} = 0 \\Rightarrow q_{1} = 0"
+			}
+		} else {
+			for k := range v {
+				q[j][k] = v[k] / norm
+			}
+			R[j][j] = norm
+			step.Coefficient = norm
+			step.LaTeX = fmt.Sprintf("r_{%d,%d} = \\lVert v_{%d} \\rVert = %s,\\quad q_{%d} = \\frac{v_{%d}}{r_{%d,%d}} = %s",
+				j+1, j+1, j+1, latexNumber(norm), j+1, j+1, j+1, j+1, latexVector(q[j]))
+		}
+		step.Vector = append([]float64(nil), q[j]...)
+		explanation.Steps = append(explanation.Steps, step)
+
+		for i := range Q {
+			Q[i][j] = q[j][i]
+		}
+	}
+	return explanation, Q, R
+}
+
+// latexNumber escribe x con 6 cifras significativas; el exponente va como ×10^{e}
+func latexNumber(x float64) string {
+	s := strconv.FormatFloat(x, 'g', 6, 64)
+	if mantissa, exponent, found := strings.Cut(s, "e"); found {
+		e, _ := strconv.Atoi(exponent)
+		return fmt.Sprintf("%s \\times 10^{%d}", mantissa, e)
+	}
+	return s
+}
+
+// latexVector escribe v como vector columna (pmatrix)
+func latexVector(v []float64) string {
+	parts := make([]string, len(v))
+	for i, x := range v {
+		parts[i] = latexNumber(x)
+	}
+	return "\\begin{pmatrix} " + strings.Join(parts, " \\\\ ") + " \\end{pmatrix}"
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+func TestExplainGramSchmidt(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		method string
+	}{
+		{name: "clásico 3x3", matrix: [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 10}}, method: models.GramSchmidtClassical},
+		{name: "modificado 3x3", matrix: [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 10}}, method: models.GramSchmidtModified},
+		{name: "clásico 4x2", matrix: [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}}, method: models.GramSchmidtClassical},
+		{name: "modificado Hilbert 5x5", matrix: hilbert(5), method: models.GramSchmidtModified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation, Q, R := ExplainGramSchmidt(tt.matrix, tt.method)
+			rows, cols := len(tt.matrix), len(tt.matrix[0])
+
+			// Por cada columna j: start, j proyecciones y la normalización
+			if want := cols * (cols + 3) / 2; len(explanation.Steps) != want {
+				t.Fatalf("%d pasos, want %d", len(explanation.Steps), want)
+			}
+			step := 0
+			for j := 0; j < cols; j++ {
+				kinds := []string{models.GramSchmidtStepStart}
+				for i := 0; i < j; i++ {
+					kinds = append(kinds, models.GramSchmidtStepProjection)
+				}
+				kinds = append(kinds, models.GramSchmidtStepNormalization)
+				for i, kind := range kinds {
+					got := explanation.Steps[step]
+					if got.Kind != kind || got.J != j || (kind == models.GramSchmidtStepProjection && got.I != i-1) {
+						t.Errorf("paso %d = %s (%d, %d), want %s en la columna %d", step, got.Kind, got.I, got.J, kind, j)
+					}
+					if got.LaTeX == "" || len(got.Vector) != rows {
+						t.Errorf("paso %d sin LaTeX o con un vector de %d elementos", step, len(got.Vector))
+					}
+					if kind == models.GramSchmidtStepProjection && got.Coefficient != R[got.I][got.J] {
+						t.Errorf("paso %d: r = %v, want R[%d][%d] = %v", step, got.Coefficient, got.I, got.J, R[got.I][got.J])
+					}
+					step++
+				}
+			}
+
+			// Qᵀ·Q = I, A = Q·R y R triangular superior con diagonal positiva
+			for i := 0; i < cols; i++ {
+				for j := 0; j < cols; j++ {
+					dot := 0.0
+					for k := 0; k < rows; k++ {
+						dot += Q[k][i] * Q[k][j]
+					}
+					want := 0.0
+					if i == j {
+						want = 1
+					}
+					if math.Abs(dot-want) > 1e-9 {
+						t.Errorf("(QᵀQ)[%d][%d] = %v, want %v", i, j, dot, want)
+					}
+				}
+				if R[i][i] <= 0 {
+					t.Errorf("R[%d][%d] = %v, debería ser positivo", i, i, R[i][i])
+				}
+			}
+			for i := 0; i < rows; i++ {
+				for j := 0; j < cols; j++ {
+					sum := 0.0
+					for k := 0; k <= j; k++ {
+						sum += Q[i][k] * R[k][j]
+					}
+					if math.Abs(sum-tt.matrix[i][j]) > 1e-9 {
+						t.Errorf("(QR)[%d][%d] = %v, want %v", i, j, sum, tt.matrix[i][j])
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("el clásico proyecta a_j y el modificado v_j", func(t *testing.T) {
+		matrix := [][]float64{{1, 1, 1}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+		classical, _, _ := ExplainGramSchmidt(matrix, models.GramSchmidtClassical)
+		modified, _, _ := ExplainGramSchmidt(matrix, models.GramSchmidtModified)
+		// Paso r_23 (i = 1, j = 2): último paso de proyección de la tercera columna
+		c, m := classical.Steps[7], modified.Steps[7]
+		if c.Kind != models.GramSchmidtStepProjection || c.I != 1 || c.J != 2 {
+			t.Fatalf("paso 7 = %+v, want la proyección r_23", c)
+		}
+		if !strings.Contains(c.LaTeX, "q_{2}^{T} a_{3}") || !strings.Contains(m.LaTeX, "q_{2}^{T} v_{3}") {
+			t.Errorf("LaTeX = %q y %q, want q_2ᵀa_3 y q_2ᵀv_3", c.LaTeX, m.LaTeX)
+		}
+		// En aritmética exacta ambos coinciden
+		if math.Abs(c.Coefficient-m.Coefficient) > 1e-12 {
+			t.Errorf("r_23 clásico = %v, modificado = %v", c.Coefficient, m.Coefficient)
+		}
+	})
+
+	t.Run("columna dependiente", func(t *testing.T) {
+		explanation, _, R := ExplainGramSchmidt([][]float64{{1, 2}, {2, 4}}, models.GramSchmidtClassical)
+		last := explanation.Steps[len(explanation.Steps)-1]
+		if R[1][1] != 0 || last.Vector[0] != 0 || last.Vector[1] != 0 || !strings.Contains(last.LaTeX, `\operatorname{span}`) {
+			t.Errorf("normalización = %+v, want r_22 = 0 y q_2 nulo", last)
+		}
+	})
+}
+
+func TestLatexNumber(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{1, "1"},
+		{-0.5, "-0.5"},
+		{math.Sqrt2, "1.41421"},
+		{1.5e-12, `1.5 \times 10^{-12}`},
+		{2e21, `2 \times 10^{21}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := latexNumber(tt.value); got != tt.want {
+				t.Errorf("latexNumber(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatrixProcessor_ProcessExplained(t *testing.T) {
+	nodeClient := NewNodeClient("http://localhost:9999")
+	nodeClient.MaxRetries = 0
+	processor := NewMatrixProcessor(nodeClient)
+	processor.ExplainMaxElements = 9
+
+	response, err := processor.ProcessExplained(context.Background(), [][]float64{{3, 0}, {4, 5}}, models.GramSchmidtModified, ProcessOptions{SkipNodeStats: true})
+	if err != nil {
+		t.Fatalf("ProcessExplained() error = %v", err)
+	}
+	if response.Q == nil || response.Explanation == nil || response.Explanation.Method != models.GramSchmidtModified {
+		t.Fatalf("respuesta = %+v, want q, r y la explicación modificada", response)
+	}
+	// La primera columna (3, 4) tiene norma 5: r_11 = 5 con signo positivo (Householder daría -5),
+	// y la primera columna de Q es (3, 4) / 5, la del paso de normalización
+	if response.R[0][0] != 5 || response.Q[0][0] != 0.6 || response.Q[1][0] != 0.8 {
+		t.Errorf("q = %v y r = %v, want r_11 = 5 y q_1 = (0.6, 0.8)", response.Q, response.R)
+	}
+	if normalization := response.Explanation.Steps[1]; normalization.Coefficient != response.R[0][0] {
+		t.Errorf("r_11 de la traza = %v, want %v", normalization.Coefficient, response.R[0][0])
+	}
+
+	tests := []struct {
+		name       string
+		matrix     [][]float64
+		wantCode   apperrors.Code
+		wantStatus int
+	}{
+		{name: "más elementos que el máximo", matrix: [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}, {9, 10}}, wantCode: apperrors.CodeExplainTooLarge, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "matriz inválida", matrix: [][]float64{{1, 2}, {3}}, wantCode: apperrors.CodeMatrixNotRectangular, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := processor.ProcessExplained(context.Background(), tt.matrix, models.GramSchmidtClassical, ProcessOptions{SkipNodeStats: true})
+			if !errors.Is(err, apperrors.New(tt.wantCode, nil)) || apperrors.From(err).Status != tt.wantStatus {
+				t.Errorf("error = %v, want %s %d", err, tt.wantCode, tt.wantStatus)
+			}
+		})
+	}
+}
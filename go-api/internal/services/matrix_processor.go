@@ -0,0 +1,408 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/metrics"
+	"go-api/internal/models"
+	"go-api/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProgressFunc recibe el avance del procesamiento (entre 0 y 1)
+type ProgressFunc func(progress float64)
+
+// ProcessOptions opciones de una ejecución de MatrixProcessor.Process
+type ProcessOptions struct {
+	// Token JWT que se reenvía a Node.js
+	Token string
+	// Language idioma del mensaje de error parcial (si Node.js falla)
+	Language string
+	// Progress se invoca al completar cada etapa (opcional)
+	Progress ProgressFunc
+	// SkipNodeStats omite la llamada a Node.js (solo rotación y QR)
+	SkipNodeStats bool
+}
+
+// MatrixProcessor orquesta el procesamiento completo de una matriz:
+// valida, rota, calcula QR y obtiene estadísticas de Node.js.
+// Lo usan tanto el endpoint síncrono como los jobs asíncronos.
+type MatrixProcessor struct {
+	NodeClient *NodeClient
+	// SparseMaxElements elementos hasta los que se densifica una matriz dispersa para
+	// calcular QR (CheckSparseSize); más grandes solo se rotan. También acota la rotación
+	// (CheckSparseRotation), que se rechaza con SPARSE_TOO_LARGE.
+	SparseMaxElements int
+	// PrecisionMaxBits y PrecisionMaxElements límites del modo de precisión (ProcessPrecise)
+	PrecisionMaxBits     uint
+	PrecisionMaxElements int
+	// ExplainMaxElements elementos (rows·cols) máximos del modo explicación (ProcessExplained)
+	ExplainMaxElements int
+}
+
+// NewMatrixProcessor crea un nuevo procesador de matrices
+func NewMatrixProcessor(nodeClient *NodeClient) *MatrixProcessor {
+	return &MatrixProcessor{
+		NodeClient:           nodeClient,
+		SparseMaxElements:    DefaultSparseMaxElements,
+		PrecisionMaxBits:     DefaultPrecisionMaxBits,
+		PrecisionMaxElements: DefaultPrecisionMaxElements,
+		ExplainMaxElements:   DefaultExplainMaxElements,
+	}
+}
+
+// Process ejecuta el procesamiento completo de la matriz.
+// Retorna error (*apperrors.Error) si la matriz es inválida o falla la factorización QR.
+// Si falla Node.js no es un error: se retorna la respuesta parcial con Error/ErrorCode.
+func (p *MatrixProcessor) Process(ctx context.Context, matrix [][]float64, opts ProcessOptions) (*models.MatrixProcessResponse, error) {
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(float64) {}
+	}
+
+	response, err := p.Compute(ctx, matrix, progress)
+	if err != nil {
+		return nil, err
+	}
+	p.attachStats(ctx, response, response.Q, response.R, response.Rotated, opts)
+	progress(1)
+	return response, nil
+}
+
+// Compute valida, rota y calcula la factorización QR, sin consultar a Node.js.
+// progress (no nil) se invoca al completar cada etapa.
+func (p *MatrixProcessor) Compute(ctx context.Context, matrix [][]float64, progress ProgressFunc) (*models.MatrixProcessResponse, error) {
+	// Validar matriz
+	if err := validate(ctx, "ValidateMatrix", validMatrix(matrix), validQRShape(matrix)); err != nil {
+		return nil, err
+	}
+	progress(0.1)
+
+	// Rotar matriz 90° en sentido horario
+	_, span := tracing.Start(ctx, "RotateMatrix90Clockwise")
+	rotated := RotateMatrix90Clockwise(matrix)
+	span.End()
+	progress(0.2)
+
+	// Calcular factorización QR de la matriz original (no rotada)
+	// Decisión técnica: calculamos QR de la matriz original para mantener
+	// la relación matemática estándar A = Q * R
+	_, span = tracing.Start(ctx, "QRDecomposition", trace.WithAttributes(
+		attribute.Int("matrix.rows", len(matrix)),
+		attribute.Int("matrix.cols", len(matrix[0])),
+	))
+	Q, R, err := QRDecomposition(matrix)
+	tracing.RecordError(span, err)
+	span.End()
+	if err != nil {
+		slog.ErrorContext(ctx, "qr decomposition failed", "error", err)
+		metrics.MatrixProcessTotal.WithLabelValues("qr_error").Inc()
+		return nil, apperrors.Wrap(apperrors.CodeQRFailed, err)
+	}
+	progress(0.6)
+
+	return &models.MatrixProcessResponse{
+		Rotated: rotated,
+		Q:       Q,
+		R:       R,
+	}, nil
+}
+
+// SetStatsUnavailable marca la respuesta como parcial porque no se pudieron obtener
+// las estadísticas de Node.js (mensaje localizado y código NODE_STATS_UNAVAILABLE)
+func SetStatsUnavailable(response *models.MatrixProcessResponse, err error, lang string) {
+	metrics.MatrixProcessTotal.WithLabelValues("node_error").Inc()
+	statsErr := apperrors.Wrap(apperrors.CodeNodeStatsUnavailable, err)
+	response.Error = statsErr.Message(lang)
+	response.ErrorCode = string(statsErr.Code)
+}
+
+// validate ejecuta las validaciones en orden, hasta la primera que falle, dentro del span
+// name; un error cuenta como invalid_matrix
+func validate(ctx context.Context, name string, checks ...func() error) error {
+	_, span := tracing.Start(ctx, name)
+	defer span.End()
+	for _, check := range checks {
+		if err := check(); err != nil {
+			tracing.RecordError(span, err)
+			metrics.MatrixProcessTotal.WithLabelValues("invalid_matrix").Inc()
+			return err
+		}
+	}
+	return nil
+}
+
+// validMatrix validación de ValidateMatrix para validate
+func validMatrix[T any](matrix [][]T) func() error {
+	return func() error { return ValidateMatrix(matrix) }
+}
+
+// validQRShape validación de ValidateQRShape para validate; va después de validMatrix
+func validQRShape[T any](matrix [][]T) func() error {
+	return func() error { return ValidateQRShape(len(matrix), len(matrix[0])) }
+}
+
+// attachStats completa la respuesta con las estadísticas que Node.js calcula sobre q, r y
+// rotated (salvo con opts.SkipNodeStats). Si Node.js falla la respuesta queda parcial
+// (SetStatsUnavailable); si no, cuenta como ok.
+func (p *MatrixProcessor) attachStats(ctx context.Context, response *models.MatrixProcessResponse, q, r, rotated [][]float64, opts ProcessOptions) {
+	if !opts.SkipNodeStats {
+		nodeStats, err := p.NodeClient.GetMatrixStats(ctx, q, r, rotated, opts.Token)
+		if err != nil {
+			slog.WarnContext(ctx, "node stats request failed", "error", err)
+			SetStatsUnavailable(response, err, opts.Language)
+			return
+		}
+		response.NodeStats = nodeStats
+	}
+	metrics.MatrixProcessTotal.WithLabelValues("ok").Inc()
+}
+
+// Selection partes del resultado que pidió el cliente; las demás no se calculan
+type Selection struct {
+	Rotated   bool
+	Q         bool
+	R         bool
+	NodeStats bool
+	// Thin factorización reducida: Q de rows×cols y R de cols×cols
+	Thin bool
+}
+
+// ProcessSelection calcula solo lo que pide sel: sin Rotated no se rota, sin NodeStats no se
+// llama a Node.js y sin Q no se construye Q. Node.js calcula las estadísticas sobre la
+// rotación y la factorización completa (como Process), así que NodeStats las necesita
+// aunque no se hayan pedido; en la respuesta solo se incluyen las partes pedidas.
+func (p *MatrixProcessor) ProcessSelection(ctx context.Context, matrix [][]float64, sel Selection, opts ProcessOptions) (*models.MatrixProcessResponse, error) {
+	factorize := sel.Q || sel.R || sel.NodeStats
+	checks := []func() error{validMatrix(matrix)}
+	if factorize {
+		checks = append(checks, validQRShape(matrix))
+	}
+	if err := validate(ctx, "ValidateMatrix", checks...); err != nil {
+		return nil, err
+	}
+
+	var rotated, Q, R [][]float64
+	if sel.Rotated || sel.NodeStats {
+		_, span := tracing.Start(ctx, "RotateMatrix90Clockwise")
+		rotated = RotateMatrix90Clockwise(matrix)
+		span.End()
+	}
+
+	if factorize {
+		// Las estadísticas de Node.js usan la factorización completa; la reducida sale de ella
+		thin := sel.Thin && !sel.NodeStats
+		_, span := tracing.Start(ctx, "QRDecomposition", trace.WithAttributes(
+			attribute.Int("matrix.rows", len(matrix)),
+			attribute.Int("matrix.cols", len(matrix[0])),
+			attribute.Bool("qr.thin", thin),
+		))
+		var err error
+		Q, R, err = QRParts(matrix, sel.Q || sel.NodeStats, sel.R || sel.NodeStats, thin)
+		tracing.RecordError(span, err)
+		span.End()
+		if err != nil {
+			slog.ErrorContext(ctx, "qr decomposition failed", "error", err)
+			metrics.MatrixProcessTotal.WithLabelValues("qr_error").Inc()
+			return nil, apperrors.Wrap(apperrors.CodeQRFailed, err)
+		}
+	}
+
+	response := &models.MatrixProcessResponse{}
+	opts.SkipNodeStats = !sel.NodeStats
+	p.attachStats(ctx, response, Q, R, rotated, opts)
+
+	if sel.Rotated {
+		response.Rotated = rotated
+	}
+	cols := len(matrix[0])
+	if sel.Q {
+		response.Q = Q
+		if sel.Thin && len(Q[0]) > cols {
+			response.Q = make([][]float64, len(Q))
+			for i, row := range Q {
+				response.Q[i] = row[:cols:cols]
+			}
+		}
+	}
+	if sel.R {
+		response.R = R
+		if sel.Thin && len(R) > cols {
+			response.R = R[:cols:cols]
+		}
+	}
+	return response, nil
+}
+
+// ProcessSparse procesa una matriz dispersa: la valida, la rota sin densificarla y, si
+// CheckSparseSize lo permite, la densifica para calcular QR y las estadísticas de Node.js
+// (que recibe las matrices densas, como en Process). Si no, la respuesta es parcial: solo
+// la rotación, con Error/ErrorCode SPARSE_TOO_LARGE. Cada matriz del resultado va en el
+// formato disperso de la entrada (RotatedSparse, QSparse, RSparse) si así ocupa menos, o
+// densa si no.
+func (p *MatrixProcessor) ProcessSparse(ctx context.Context, sparse *models.SparseMatrix, opts ProcessOptions) (*models.MatrixProcessResponse, error) {
+	err := validate(ctx, "ValidateSparse",
+		func() error { return ValidateSparse(sparse) },
+		func() error { return CheckSparseRotation(sparse, p.SparseMaxElements) },
+		func() error { return ValidateQRShape(sparse.Rows, sparse.Cols) },
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.Start(ctx, "RotateSparse90Clockwise")
+	rotated := RotateSparse90Clockwise(sparse)
+	span.End()
+
+	response := &models.MatrixProcessResponse{}
+	if sparseIsSmaller(rotated.Format, rotated.Rows, rotated.Cols, len(rotated.Data)) {
+		response.RotatedSparse = rotated
+	} else {
+		response.Rotated = DenseFromSparse(rotated)
+	}
+	if err := CheckSparseSize(sparse, p.SparseMaxElements); err != nil {
+		metrics.MatrixProcessTotal.WithLabelValues("qr_skipped").Inc()
+		sizeErr := apperrors.From(err)
+		response.Error = sizeErr.Message(opts.Language)
+		response.ErrorCode = string(sizeErr.Code)
+		return response, nil
+	}
+
+	_, span = tracing.Start(ctx, "QRDecomposition", trace.WithAttributes(
+		attribute.Int("matrix.rows", sparse.Rows),
+		attribute.Int("matrix.cols", sparse.Cols),
+		attribute.Int("matrix.nnz", len(sparse.Data)),
+	))
+	Q, R, err := QRDecomposition(DenseFromSparse(sparse))
+	tracing.RecordError(span, err)
+	span.End()
+	if err != nil {
+		slog.ErrorContext(ctx, "qr decomposition failed", "error", err)
+		metrics.MatrixProcessTotal.WithLabelValues("qr_error").Inc()
+		return nil, apperrors.Wrap(apperrors.CodeQRFailed, err)
+	}
+
+	p.attachStats(ctx, response, Q, R, DenseFromSparse(rotated), opts)
+	response.Q, response.QSparse = compactMatrix(Q, sparse.Format)
+	response.R, response.RSparse = compactMatrix(R, sparse.Format)
+	return response, nil
+}
+
+// ProcessComplex procesa una matriz compleja: la valida, la rota y calcula su factorización
+// QR unitaria (ComplexQRDecomposition). Node.js recibe los módulos de Q, R y la rotada, así
+// que NodeStats son estadísticas sobre magnitudes. El resultado va en RotatedComplex,
+// QComplex y RComplex.
+func (p *MatrixProcessor) ProcessComplex(ctx context.Context, matrix [][]models.Complex, opts ProcessOptions) (*models.MatrixProcessResponse, error) {
+	if err := validate(ctx, "ValidateMatrix", validMatrix(matrix)); err != nil {
+		return nil, err
+	}
+
+	values := complexFromModel(matrix)
+	_, span := tracing.Start(ctx, "RotateMatrix90Clockwise")
+	rotated := RotateMatrix90Clockwise(values)
+	span.End()
+
+	_, span = tracing.Start(ctx, "ComplexQRDecomposition", trace.WithAttributes(
+		attribute.Int("matrix.rows", len(matrix)),
+		attribute.Int("matrix.cols", len(matrix[0])),
+	))
+	Q, R, err := ComplexQRDecomposition(values)
+	tracing.RecordError(span, err)
+	span.End()
+	if err != nil {
+		slog.ErrorContext(ctx, "complex qr decomposition failed", "error", err)
+		metrics.MatrixProcessTotal.WithLabelValues("qr_error").Inc()
+		return nil, apperrors.Wrap(apperrors.CodeQRFailed, err)
+	}
+
+	response := &models.MatrixProcessResponse{
+		RotatedComplex: complexToModel(rotated),
+		QComplex:       complexToModel(Q),
+		RComplex:       complexToModel(R),
+	}
+	p.attachStats(ctx, response, magnitudes(Q), magnitudes(R), magnitudes(rotated), opts)
+	return response, nil
+}
+
+// ProcessPrecise procesa la matriz calculando QR con big.Float a precision bits
+// (PreciseQRDecomposition) y la verifica con la misma precisión. Q y R (reducidas) van como
+// strings decimales en QDecimal y RDecimal, con los residuos en Residuals; la rotación es
+// exacta y va en Rotated. Node.js recibe Q y R redondeadas a float64.
+func (p *MatrixProcessor) ProcessPrecise(ctx context.Context, matrix [][]float64, precision uint, opts ProcessOptions) (*models.MatrixProcessResponse, error) {
+	err := validate(ctx, "ValidateMatrix", validMatrix(matrix), validQRShape(matrix),
+		func() error { return CheckPrecision(matrix, precision, p.PrecisionMaxBits, p.PrecisionMaxElements) })
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.Start(ctx, "RotateMatrix90Clockwise")
+	rotated := RotateMatrix90Clockwise(matrix)
+	span.End()
+
+	_, span = tracing.Start(ctx, "PreciseQRDecomposition", trace.WithAttributes(
+		attribute.Int("matrix.rows", len(matrix)),
+		attribute.Int("matrix.cols", len(matrix[0])),
+		attribute.Int("matrix.precision", int(precision)),
+	))
+	Q, R, err := PreciseQRDecomposition(matrix, precision)
+	tracing.RecordError(span, err)
+	span.End()
+	if err != nil {
+		slog.ErrorContext(ctx, "precise qr decomposition failed", "error", err)
+		metrics.MatrixProcessTotal.WithLabelValues("qr_error").Inc()
+		return nil, apperrors.Wrap(apperrors.CodeQRFailed, err)
+	}
+	factorization, orthogonality := PrecisionResiduals(matrix, Q, R, precision)
+
+	response := &models.MatrixProcessResponse{
+		Rotated:   rotated,
+		Precision: precision,
+		QDecimal:  decimalStrings(Q),
+		RDecimal:  decimalStrings(R),
+		Residuals: &models.PrecisionResiduals{
+			Factorization: factorization.Text('g', 6),
+			Orthogonality: orthogonality.Text('g', 6),
+		},
+	}
+	p.attachStats(ctx, response, float64Matrix(Q), float64Matrix(R), rotated, opts)
+	return response, nil
+}
+
+// ProcessExplained procesa la matriz con la QR reducida de Gram-Schmidt en la variante
+// method (ExplainGramSchmidt) en lugar de Householder: q y r son las que construye la traza
+// que va en Explanation, con la diagonal de R no negativa, así que pueden diferir en el
+// signo de columnas de Q y filas de R de las de Process.
+func (p *MatrixProcessor) ProcessExplained(ctx context.Context, matrix [][]float64, method string, opts ProcessOptions) (*models.MatrixProcessResponse, error) {
+	err := validate(ctx, "ValidateMatrix", validMatrix(matrix), validQRShape(matrix),
+		func() error { return CheckExplain(matrix, p.ExplainMaxElements) })
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.Start(ctx, "RotateMatrix90Clockwise")
+	rotated := RotateMatrix90Clockwise(matrix)
+	span.End()
+
+	_, span = tracing.Start(ctx, "ExplainGramSchmidt", trace.WithAttributes(
+		attribute.Int("matrix.rows", len(matrix)),
+		attribute.Int("matrix.cols", len(matrix[0])),
+	))
+	explanation, Q, R := ExplainGramSchmidt(matrix, method)
+	span.End()
+
+	response := &models.MatrixProcessResponse{Rotated: rotated, Q: Q, R: R, Explanation: explanation}
+	p.attachStats(ctx, response, Q, R, rotated, opts)
+	return response, nil
+}
+
+// compactMatrix retorna la matriz en formato disperso si así ocupa menos, o densa si no
+func compactMatrix(matrix [][]float64, format string) ([][]float64, *models.SparseMatrix) {
+	if sparseIsSmaller(format, len(matrix), len(matrix[0]), countNonZero(matrix)) {
+		return nil, SparseFromDense(matrix, format)
+	}
+	return matrix, nil
+}
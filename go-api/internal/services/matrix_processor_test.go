@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go-api/internal/models"
+)
+
+func TestMatrixProcessor_ProcessSelection(t *testing.T) {
+	// Node.js simulado: responde max = cantidad de columnas de la Q recibida
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var req models.MatrixStatsRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(models.MatrixStatsResponse{Max: float64(len(req.Q[0]))})
+	}))
+	defer server.Close()
+	processor := NewMatrixProcessor(NewNodeClient(server.URL))
+	matrix := [][]float64{{1, 2}, {3, 4}, {5, 6}}
+
+	tests := []struct {
+		name      string
+		sel       Selection
+		wantCalls int32
+		// dimensiones esperadas (0 si la parte no debe calcularse)
+		rotatedRows, qCols, rRows int
+	}{
+		{name: "todo", sel: Selection{Rotated: true, Q: true, R: true, NodeStats: true}, wantCalls: 1, rotatedRows: 2, qCols: 3, rRows: 3},
+		{name: "solo R", sel: Selection{R: true}, rRows: 3},
+		{name: "Q y R reducidas", sel: Selection{Q: true, R: true, Thin: true}, qCols: 2, rRows: 2},
+		{name: "R reducida y estadísticas", sel: Selection{R: true, NodeStats: true, Thin: true}, wantCalls: 1, rRows: 2},
+		{name: "solo rotación", sel: Selection{Rotated: true}, rotatedRows: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
+			response, err := processor.ProcessSelection(context.Background(), matrix, tt.sel, ProcessOptions{})
+			if err != nil {
+				t.Fatalf("ProcessSelection() error = %v", err)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("llamadas a Node.js = %d, esperaba %d", got, tt.wantCalls)
+			}
+			if len(response.Rotated) != tt.rotatedRows || len(response.R) != tt.rRows {
+				t.Errorf("rotated %d filas, R %d filas; esperaba %d y %d", len(response.Rotated), len(response.R), tt.rotatedRows, tt.rRows)
+			}
+			if tt.qCols == 0 && response.Q != nil || tt.qCols > 0 && (len(response.Q) != 3 || len(response.Q[0]) != tt.qCols) {
+				t.Errorf("Q = %v, esperaba %d columnas", response.Q, tt.qCols)
+			}
+			// Node.js recibe siempre la Q completa, aunque se pida la reducida
+			if tt.sel.NodeStats && (response.NodeStats == nil || response.NodeStats.Max != 3) {
+				t.Errorf("nodeStats = %+v, esperaba max 3", response.NodeStats)
+			}
+		})
+	}
+
+	if _, err := processor.ProcessSelection(context.Background(), [][]float64{}, Selection{R: true}, ProcessOptions{}); err == nil {
+		t.Error("ProcessSelection() con matriz vacía no retornó error")
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+// MatrixSession mantiene la matriz de una sesión interactiva de edición y la recalcula
+// cuando cambia. Los cambios se agrupan durante Debounce y un cambio nuevo cancela el
+// cálculo en curso, así solo se envía el resultado de la última versión.
+type MatrixSession struct {
+	processor *MatrixProcessor
+	opts      ProcessOptions
+	debounce  time.Duration
+	send      func(models.SessionEvent)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	matrix  [][]float64
+	version int
+	timer   *time.Timer
+	// running cancela el cálculo en curso (nil si no hay ninguno)
+	running context.CancelFunc
+}
+
+// NewMatrixSession crea una sesión sin matriz. send recibe los resultados y errores de
+// cálculo; se llama desde otras goroutines pero nunca en paralelo consigo misma.
+func NewMatrixSession(ctx context.Context, processor *MatrixProcessor, opts ProcessOptions, debounce time.Duration, send func(models.SessionEvent)) *MatrixSession {
+	ctx, cancel := context.WithCancel(ctx)
+	return &MatrixSession{
+		processor: processor,
+		opts:      opts,
+		debounce:  debounce,
+		send:      send,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// SetMatrix reemplaza la matriz completa y retorna la nueva versión
+func (s *MatrixSession) SetMatrix(matrix [][]float64) (int, error) {
+	if err := ValidateMatrix(matrix); err != nil {
+		return 0, err
+	}
+	copied := copyMatrix(matrix)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matrix = copied
+	return s.changed(), nil
+}
+
+// Update aplica cambios de celdas a la matriz actual y retorna la nueva versión.
+// Los cambios se aplican todos o ninguno: una celda fuera de rango rechaza el mensaje.
+func (s *MatrixSession) Update(cells []models.CellUpdate) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.matrix == nil {
+		return 0, apperrors.New(apperrors.CodeSessionNoMatrix, nil)
+	}
+	rows, cols := len(s.matrix), len(s.matrix[0])
+	for _, cell := range cells {
+		if cell.Row < 0 || cell.Row >= rows || cell.Col < 0 || cell.Col >= cols {
+			return 0, apperrors.New(apperrors.CodeSessionCellOutOfRange, map[string]interface{}{
+				"row": cell.Row, "col": cell.Col, "rows": rows, "cols": cols,
+			})
+		}
+	}
+	for _, cell := range cells {
+		s.matrix[cell.Row][cell.Col] = cell.Value
+	}
+	return s.changed(), nil
+}
+
+// Close cancela el cálculo pendiente y espera a que termine; después de Close no se llama a send
+func (s *MatrixSession) Close() {
+	s.mu.Lock()
+	s.cancel()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// changed incrementa la versión, cancela el cálculo en curso (ya es obsoleto) y
+// programa el recálculo al terminar el debounce. Se llama con mu tomado.
+func (s *MatrixSession) changed() int {
+	s.version++
+	if s.running != nil {
+		s.running()
+		s.running = nil
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	version := s.version
+	s.timer = time.AfterFunc(s.debounce, func() { s.compute(version) })
+	return version
+}
+
+// compute recalcula la versión dada si sigue siendo la actual
+func (s *MatrixSession) compute(version int) {
+	s.mu.Lock()
+	if version != s.version || s.ctx.Err() != nil {
+		s.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.running = cancel
+	matrix := copyMatrix(s.matrix)
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	defer s.wg.Done()
+	defer cancel()
+	response, err := s.processor.Process(ctx, matrix, s.opts)
+	if ctx.Err() != nil {
+		return
+	}
+
+	event := models.SessionEvent{Type: models.SessionEventResult, Version: version, Result: response}
+	if err != nil {
+		problem := apperrors.From(err).Problem(s.opts.Language)
+		event = models.SessionEvent{Type: models.SessionEventError, Version: version, Problem: &problem}
+	}
+
+	// Enviar con mu tomado garantiza que no se envía un resultado ya reemplazado por otra versión
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if version != s.version || s.ctx.Err() != nil {
+		return
+	}
+	s.running = nil
+	s.send(event)
+}
+
+// copyMatrix copia la matriz para que los cambios de la sesión no afecten a un cálculo en curso
+func copyMatrix(matrix [][]float64) [][]float64 {
+	copied := make([][]float64, len(matrix))
+	for i, row := range matrix {
+		copied[i] = append([]float64(nil), row...)
+	}
+	return copied
+}
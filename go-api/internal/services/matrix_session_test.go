@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+// slowValue valor de la matriz que hace que fakeStatsServer bloquee hasta que se cancele la petición
+const slowValue = 99
+
+// fakeStatsServer simula POST /matrix/stats de Node.js: responde sum = suma de la matriz
+// rotada y bloquea si contiene slowValue. calls cuenta las llamadas recibidas.
+func fakeStatsServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		var req models.MatrixStatsRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var resp models.MatrixStatsResponse
+		for _, row := range req.Rotated {
+			for _, value := range row {
+				if value == slowValue {
+					<-r.Context().Done()
+					return
+				}
+				resp.Sum += value
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestSession crea una sesión cuyos eventos llegan por el canal retornado
+func newTestSession(t *testing.T, calls *int32) (*MatrixSession, <-chan models.SessionEvent) {
+	t.Helper()
+	client := NewNodeClient(fakeStatsServer(t, calls).URL)
+	client.MaxRetries = 0
+	events := make(chan models.SessionEvent, 10)
+	session := NewMatrixSession(context.Background(), NewMatrixProcessor(client), ProcessOptions{}, 20*time.Millisecond, func(event models.SessionEvent) {
+		events <- event
+	})
+	t.Cleanup(session.Close)
+	return session, events
+}
+
+// nextEvent espera el siguiente evento de la sesión
+func nextEvent(t *testing.T, events <-chan models.SessionEvent) models.SessionEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout esperando un evento de la sesión")
+		return models.SessionEvent{}
+	}
+}
+
+func TestMatrixSession_Debounce(t *testing.T) {
+	var calls int32
+	session, events := newTestSession(t, &calls)
+
+	if _, err := session.SetMatrix([][]float64{{1, 2}, {3, 4}}); err != nil {
+		t.Fatalf("SetMatrix() = %v", err)
+	}
+	session.Update([]models.CellUpdate{{Row: 0, Col: 0, Value: 10}})
+	version, err := session.Update([]models.CellUpdate{{Row: 1, Col: 1, Value: 20}})
+	if err != nil {
+		t.Fatalf("Update() = %v", err)
+	}
+
+	event := nextEvent(t, events)
+	if event.Type != models.SessionEventResult || event.Version != version {
+		t.Fatalf("evento = %+v, want result de la versión %d", event, version)
+	}
+	if event.Result.NodeStats == nil || event.Result.NodeStats.Sum != 35 {
+		t.Errorf("nodeStats = %+v, want sum 35 (10+2+3+20)", event.Result.NodeStats)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("llamadas a Node.js = %d, want 1", got)
+	}
+}
+
+func TestMatrixSession_CancelsStaleComputation(t *testing.T) {
+	var calls int32
+	session, events := newTestSession(t, &calls)
+
+	// La primera versión queda bloqueada en Node.js hasta que la cancela el cambio siguiente
+	session.SetMatrix([][]float64{{slowValue}})
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&calls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	version, _ := session.Update([]models.CellUpdate{{Row: 0, Col: 0, Value: 7}})
+
+	event := nextEvent(t, events)
+	if event.Type != models.SessionEventResult || event.Version != version {
+		t.Fatalf("evento = %+v, want result de la versión %d", event, version)
+	}
+	select {
+	case extra := <-events:
+		t.Errorf("evento inesperado de una versión obsoleta: %+v", extra)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestMatrixSession_Errors(t *testing.T) {
+	var calls int32
+	session, events := newTestSession(t, &calls)
+
+	if _, err := session.Update([]models.CellUpdate{{Row: 0, Col: 0, Value: 1}}); !errors.Is(err, apperrors.New(apperrors.CodeSessionNoMatrix, nil)) {
+		t.Errorf("Update() sin matriz = %v, want SESSION_NO_MATRIX", err)
+	}
+	if _, err := session.SetMatrix([][]float64{{1, 2}, {3}}); !errors.Is(err, apperrors.New(apperrors.CodeMatrixNotRectangular, nil)) {
+		t.Errorf("SetMatrix() no rectangular = %v, want MATRIX_NOT_RECTANGULAR", err)
+	}
+
+	version, _ := session.SetMatrix([][]float64{{1, 2}, {3, 4}})
+	if _, err := session.Update([]models.CellUpdate{{Row: 0, Col: 0, Value: 5}, {Row: 2, Col: 0, Value: 1}}); !errors.Is(err, apperrors.New(apperrors.CodeSessionCellOutOfRange, nil)) {
+		t.Errorf("Update() fuera de rango = %v, want SESSION_CELL_OUT_OF_RANGE", err)
+	}
+
+	// El cambio rechazado no modifica la matriz ni la versión
+	event := nextEvent(t, events)
+	if event.Version != version || event.Result.Rotated[1][1] != 2 {
+		t.Errorf("evento = %+v, want versión %d con la matriz sin cambios", event, version)
+	}
+}
@@ -2,19 +2,36 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
+	"go-api/internal/logging"
+	"go-api/internal/metrics"
 	"go-api/internal/models"
+	"go-api/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // NodeClient maneja la comunicación con la API de Node.js
 type NodeClient struct {
 	BaseURL    string
 	HTTPClient *http.Client
+	// MaxRetries cantidad de reintentos ante errores transitorios (conexión o 5xx). Un error
+	// de transporte puede ocurrir con la petición ya entregada a Node.js, así que se reintenta
+	// un POST que quizá se procesó: es seguro porque las estadísticas no tienen efectos
+	// secundarios. Una operación que los tenga no debe usar do con reintentos.
+	MaxRetries int
+	// RetryBackoff espera base entre reintentos (crece linealmente con cada intento)
+	RetryBackoff time.Duration
 }
 
 // NewNodeClient crea un nuevo cliente para la API de Node.js
@@ -24,12 +41,21 @@ func NewNodeClient(baseURL string) *NodeClient {
 		HTTPClient: &http.Client{
 			Timeout: 10 * time.Second, // Timeout de 10 segundos
 		},
+		MaxRetries:   2,
+		RetryBackoff: 100 * time.Millisecond,
 	}
 }
 
 // GetMatrixStats envía las matrices Q y R a Node.js y obtiene las estadísticas
 // tokenJWT es el token JWT que se usará para autenticar la petición a Node.js
-func (c *NodeClient) GetMatrixStats(q, r, rotated [][]float64, tokenJWT string) (*models.MatrixStatsResponse, error) {
+// El contexto de traza de ctx se propaga a Node.js mediante el header traceparent
+func (c *NodeClient) GetMatrixStats(ctx context.Context, q, r, rotated [][]float64, tokenJWT string) (stats *models.MatrixStatsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "NodeClient.GetMatrixStats", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	// Preparar el request
 	reqBody := models.MatrixStatsRequest{
 		Q:       q,
@@ -44,41 +70,158 @@ func (c *NodeClient) GetMatrixStats(q, r, rotated [][]float64, tokenJWT string)
 
 	// Realizar la petición HTTP
 	url := fmt.Sprintf("%s/matrix/stats", c.BaseURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	body, err := c.do(ctx, "stats", func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		// Incluir token JWT en el header Authorization
+		if tokenJWT != "" {
+			req.Header.Set("Authorization", "Bearer "+tokenJWT)
+		}
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error al crear request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	// Incluir token JWT en el header Authorization
-	if tokenJWT != "" {
-		req.Header.Set("Authorization", "Bearer "+tokenJWT)
+	// Parsear respuesta
+	stats = &models.MatrixStatsResponse{}
+	if err := json.Unmarshal(body, stats); err != nil {
+		metrics.NodeRequestErrors.WithLabelValues("stats", "decode").Inc()
+		return nil, fmt.Errorf("error al parsear respuesta: %w", err)
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	return stats, nil
+}
+
+// GetMatrixStatsBatch obtiene las estadísticas de varios conjuntos de matrices en una sola llamada
+// a Node.js. Retorna un resultado por elemento, en el mismo orden; los errores de un elemento
+// vienen en su campo Error y no hacen fallar al resto.
+func (c *NodeClient) GetMatrixStatsBatch(ctx context.Context, items []models.MatrixStatsRequest, tokenJWT string) (results []models.MatrixStatsBatchResult, err error) {
+	ctx, span := tracing.Start(ctx, "NodeClient.GetMatrixStatsBatch", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("batch.size", len(items))))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	jsonData, err := json.Marshal(models.MatrixStatsBatchRequest{Items: items})
 	if err != nil {
-		return nil, fmt.Errorf("error al realizar petición a Node.js: %w", err)
+		return nil, fmt.Errorf("error al serializar request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Leer respuesta
-	body, err := io.ReadAll(resp.Body)
+	url := fmt.Sprintf("%s/matrix/stats/batch", c.BaseURL)
+	body, err := c.do(ctx, "stats_batch", func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if tokenJWT != "" {
+			req.Header.Set("Authorization", "Bearer "+tokenJWT)
+		}
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error al leer respuesta: %w", err)
+		return nil, err
 	}
 
-	// Verificar código de estado
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Node.js retornó código %d: %s", resp.StatusCode, string(body))
+	var response models.MatrixStatsBatchResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		metrics.NodeRequestErrors.WithLabelValues("stats_batch", "decode").Inc()
+		return nil, fmt.Errorf("error al parsear respuesta: %w", err)
+	}
+	if len(response.Results) != len(items) {
+		metrics.NodeRequestErrors.WithLabelValues("stats_batch", "decode").Inc()
+		return nil, fmt.Errorf("Node.js retornó %d resultados para %d elementos", len(response.Results), len(items))
 	}
 
-	// Parsear respuesta
-	var stats models.MatrixStatsResponse
-	if err := json.Unmarshal(body, &stats); err != nil {
-		return nil, fmt.Errorf("error al parsear respuesta: %w", err)
+	return response.Results, nil
+}
+
+// Ping verifica que la API de Node.js esté disponible consultando su endpoint /health
+func (c *NodeClient) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/health", c.BaseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("error al crear request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al realizar petición a Node.js: %w", err)
 	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
 
-	return &stats, nil
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Node.js retornó código %d en /health", resp.StatusCode)
+	}
+	return nil
 }
 
+// do ejecuta la petición construida por newRequest, reintentando ante errores de conexión
+// o respuestas 5xx, y registra latencia, errores y reintentos por operación.
+// Retorna el cuerpo de la respuesta si el código es 200.
+func (c *NodeClient) do(ctx context.Context, operation string, newRequest func() (*http.Request, error)) ([]byte, error) {
+	span := trace.SpanFromContext(ctx)
+	var lastErr error
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		if attempt > 0 {
+			metrics.NodeRequestRetries.WithLabelValues(operation).Inc()
+			span.AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("error al realizar petición a Node.js: %w", ctx.Err())
+			case <-time.After(time.Duration(attempt) * c.RetryBackoff):
+			}
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			metrics.NodeRequestErrors.WithLabelValues(operation, "request").Inc()
+			return nil, fmt.Errorf("error al crear request: %w", err)
+		}
+		// Propagar el contexto de traza (W3C traceparent) para continuar la traza en Node.js
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+		// Propagar el request ID para correlacionar los logs de ambos servicios
+		if id := logging.RequestID(ctx); id != "" {
+			req.Header.Set("X-Request-ID", id)
+		}
+
+		start := time.Now()
+		resp, err := c.HTTPClient.Do(req)
+		metrics.NodeRequestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+		if err != nil {
+			// Puede haber llegado a Node.js: se reintenta solo porque stats es idempotente
+			metrics.NodeRequestErrors.WithLabelValues(operation, "transport").Inc()
+			lastErr = fmt.Errorf("error al realizar petición a Node.js: %w", err)
+			continue
+		}
+
+		// Leer respuesta
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			metrics.NodeRequestErrors.WithLabelValues(operation, "read").Inc()
+			lastErr = fmt.Errorf("error al leer respuesta: %w", err)
+			continue
+		}
 
+		// Verificar código de estado
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode != http.StatusOK {
+			metrics.NodeRequestErrors.WithLabelValues(operation, "status_"+strconv.Itoa(resp.StatusCode)).Inc()
+			lastErr = fmt.Errorf("Node.js retornó código %d: %s", resp.StatusCode, string(body))
+			if resp.StatusCode >= 500 {
+				continue
+			}
+			return nil, lastErr
+		}
+
+		return body, nil
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api/internal/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestNodeClient_GetMatrixStats_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		failStatus   int
+		wantErr      bool
+		wantAttempts int32
+	}{
+		{
+			name:         "éxito al primer intento",
+			failures:     0,
+			wantAttempts: 1,
+		},
+		{
+			name:         "reintenta ante 5xx y luego responde",
+			failures:     2,
+			failStatus:   http.StatusBadGateway,
+			wantAttempts: 3,
+		},
+		{
+			name:         "agota los reintentos",
+			failures:     10,
+			failStatus:   http.StatusServiceUnavailable,
+			wantErr:      true,
+			wantAttempts: 3,
+		},
+		{
+			name:         "no reintenta ante 4xx",
+			failures:     10,
+			failStatus:   http.StatusForbidden,
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+				if n <= tt.failures {
+					w.WriteHeader(tt.failStatus)
+					return
+				}
+				w.Write([]byte(`{"max":4,"min":1,"avg":2.5,"sum":10,"anyDiagonal":false}`))
+			}))
+			defer server.Close()
+
+			client := NewNodeClient(server.URL)
+			client.RetryBackoff = time.Millisecond
+
+			stats, err := client.GetMatrixStats(context.Background(), [][]float64{{1}}, [][]float64{{1}}, nil, "token")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetMatrixStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && stats.Max != 4 {
+				t.Errorf("stats.Max = %v, want 4", stats.Max)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("intentos = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestNodeClient_GetMatrixStats_PropagatesTraceparent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"max":1,"min":1,"avg":1,"sum":1,"anyDiagonal":true}`))
+	}))
+	defer server.Close()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	_, err := NewNodeClient(server.URL).GetMatrixStats(ctx, [][]float64{{1}}, [][]float64{{1}}, nil, "")
+	parent.End()
+	if err != nil {
+		t.Fatalf("GetMatrixStats() error = %v", err)
+	}
+
+	traceID := parent.SpanContext().TraceID().String()
+	if traceparent == "" || traceparent[3:35] != traceID {
+		t.Errorf("traceparent = %q, se esperaba el trace ID %s", traceparent, traceID)
+	}
+
+	var found bool
+	for _, span := range recorder.Ended() {
+		if span.Name() == "NodeClient.GetMatrixStats" {
+			found = true
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Error("El span de Node.js no es hijo del span padre")
+			}
+		}
+	}
+	if !found {
+		t.Error("No se registró el span NodeClient.GetMatrixStats")
+	}
+}
+
+func TestNodeClient_GetMatrixStats_PropagatesRequestID(t *testing.T) {
+	var requestID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = r.Header.Get("X-Request-ID")
+		w.Write([]byte(`{"max":1,"min":1,"avg":1,"sum":1,"anyDiagonal":true}`))
+	}))
+	defer server.Close()
+
+	ctx := logging.WithRequestID(context.Background(), "req-node-1")
+	if _, err := NewNodeClient(server.URL).GetMatrixStats(ctx, [][]float64{{1}}, [][]float64{{1}}, nil, ""); err != nil {
+		t.Fatalf("GetMatrixStats() error = %v", err)
+	}
+	if requestID != "req-node-1" {
+		t.Errorf("X-Request-ID = %q, want req-node-1", requestID)
+	}
+}
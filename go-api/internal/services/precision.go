@@ -0,0 +1,216 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/metrics"
+)
+
+// Límites del modo de precisión arbitraria (big.Float)
+const (
+	// MinPrecision precisión mínima en bits: la de la mantisa de float64, para no perder
+	// dígitos de la entrada
+	MinPrecision = 53
+	// DefaultPrecisionMaxBits precisión máxima en bits
+	DefaultPrecisionMaxBits = 4096
+	// DefaultPrecisionMaxElements elementos (rows·cols) máximos: Householder y la
+	// verificación son O(rows·cols²) operaciones de big.Float y, como cols <= rows, a lo
+	// sumo elements^1.5
+	DefaultPrecisionMaxElements = 2500
+)
+
+// CheckPrecision valida la precisión pedida y el tamaño de la matriz para el modo de
+// precisión (PRECISION_INVALID, PRECISION_TOO_LARGE). La matriz debe estar validada.
+func CheckPrecision(matrix [][]float64, precision uint, maxBits uint, maxElements int) error {
+	if precision < MinPrecision || precision > maxBits {
+		return apperrors.New(apperrors.CodePrecisionInvalid, map[string]interface{}{
+			"precision": precision, "min": MinPrecision, "max": maxBits,
+		})
+	}
+	rows, cols := len(matrix), len(matrix[0])
+	if rows*cols > maxElements {
+		return apperrors.New(apperrors.CodePrecisionTooLarge, map[string]interface{}{
+			"rows": rows, "cols": cols, "elements": rows * cols, "max": maxElements,
+		})
+	}
+	return nil
+}
+
+// PreciseQRDecomposition calcula la factorización QR reducida (Q de rows×cols, R de
+// cols×cols) con reflexiones de Householder en big.Float con prec bits de mantisa. Sigue
+// la convención de LAPACK (la de QRDecomposition): R[k][k] tiene el signo opuesto al de la
+// columna y una columna que ya es triangular no se refleja, así que el resultado es
+// comparable elemento a elemento con las primeras columnas de Q y filas de R en float64.
+// Q se arma aplicando los reflectores a las primeras cols columnas de la identidad, sin
+// construir la completa: todo el cálculo es O(rows·cols²). Como QRDecomposition, exige rows >= cols.
+func PreciseQRDecomposition(matrix [][]float64, prec uint) ([][]*big.Float, [][]*big.Float, error) {
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		return nil, nil, fmt.Errorf("la matriz no puede estar vacía")
+	}
+	rows, cols := len(matrix), len(matrix[0])
+	if rows < cols {
+		return nil, nil, fmt.Errorf("la matriz %dx%d tiene más columnas que filas", rows, cols)
+	}
+
+	start := time.Now()
+	defer func() {
+		metrics.QRDuration.WithLabelValues(metrics.SizeBucket(rows, cols)).Observe(time.Since(start).Seconds())
+	}()
+
+	newFloat := func() *big.Float { return new(big.Float).SetPrec(prec) }
+	newMatrix := func(m, n int) [][]*big.Float {
+		out := make([][]*big.Float, m)
+		for i := range out {
+			out[i] = make([]*big.Float, n)
+			for j := range out[i] {
+				out[i][j] = newFloat()
+			}
+		}
+		return out
+	}
+	R := newMatrix(rows, cols)
+	for i := range R {
+		for j, value := range matrix[i] {
+			R[i][j].SetFloat64(value)
+		}
+	}
+
+	// Reflector k: H_k = I - scales[k]·v_k·v_kᵀ, con v_k en vs[k][k:]; scales[k] nil si no se refleja
+	vs := newMatrix(cols, rows)
+	scales := make([]*big.Float, cols)
+	xnorm2, norm, beta, vNorm2, s, term := newFloat(), newFloat(), newFloat(), newFloat(), newFloat(), newFloat()
+	for k := 0; k < cols; k++ {
+		xnorm2.SetInt64(0)
+		for i := k + 1; i < rows; i++ {
+			xnorm2.Add(xnorm2, term.Mul(R[i][k], R[i][k]))
+		}
+		if xnorm2.Sign() == 0 {
+			continue
+		}
+
+		// beta = -sign(alpha)·‖x‖ y v = x - beta·e1
+		v := vs[k]
+		alpha := R[k][k]
+		norm.Sqrt(norm.Add(xnorm2, term.Mul(alpha, alpha)))
+		beta.Neg(norm)
+		if alpha.Sign() < 0 {
+			beta.Set(norm)
+		}
+		v[k].Sub(alpha, beta)
+		for i := k + 1; i < rows; i++ {
+			v[i].Set(R[i][k])
+		}
+		vNorm2.Add(xnorm2, term.Mul(v[k], v[k]))
+		scales[k] = newFloat().Quo(newFloat().SetInt64(2), vNorm2)
+
+		// R = H·R con H = I - 2·v·vᵀ / (vᵀ·v), solo las columnas que quedan
+		for j := k + 1; j < cols; j++ {
+			s.SetInt64(0)
+			for i := k; i < rows; i++ {
+				s.Add(s, term.Mul(v[i], R[i][j]))
+			}
+			s.Mul(s, scales[k])
+			for i := k; i < rows; i++ {
+				R[i][j].Sub(R[i][j], term.Mul(s, v[i]))
+			}
+		}
+		R[k][k].Set(beta)
+		for i := k + 1; i < rows; i++ {
+			R[i][k].SetInt64(0)
+		}
+	}
+
+	// Q = H_0·…·H_{cols-1}·[I; 0], del último reflector al primero. H_k no cambia filas
+	// anteriores a k, así que las columnas j < k siguen siendo e_j.
+	Q := newMatrix(rows, cols)
+	for j := 0; j < cols; j++ {
+		Q[j][j].SetInt64(1)
+	}
+	for k := cols - 1; k >= 0; k-- {
+		if scales[k] == nil {
+			continue
+		}
+		v := vs[k]
+		for j := k; j < cols; j++ {
+			s.SetInt64(0)
+			for i := k; i < rows; i++ {
+				s.Add(s, term.Mul(v[i], Q[i][j]))
+			}
+			s.Mul(s, scales[k])
+			for i := k; i < rows; i++ {
+				Q[i][j].Sub(Q[i][j], term.Mul(s, v[i]))
+			}
+		}
+	}
+	return Q, R[:cols:cols], nil
+}
+
+// PrecisionResiduals verifica una factorización de PreciseQRDecomposition con la misma
+// precisión: retorna ‖A - Q·R‖_F / ‖A‖_F (sin dividir si A es nula) y ‖Qᵀ·Q - I‖_F sobre
+// las cols columnas de Q, en O(rows·cols²)
+func PrecisionResiduals(matrix [][]float64, Q, R [][]*big.Float, prec uint) (*big.Float, *big.Float) {
+	newFloat := func() *big.Float { return new(big.Float).SetPrec(prec) }
+	rows, cols := len(matrix), len(matrix[0])
+	term, sum := newFloat(), newFloat()
+
+	factorization, normA := newFloat(), newFloat()
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			a := newFloat().SetFloat64(matrix[i][j])
+			normA.Add(normA, term.Mul(a, a))
+			sum.Neg(a)
+			for k := 0; k <= j; k++ {
+				sum.Add(sum, term.Mul(Q[i][k], R[k][j]))
+			}
+			factorization.Add(factorization, term.Mul(sum, sum))
+		}
+	}
+	factorization.Sqrt(factorization)
+	if normA.Sign() > 0 {
+		factorization.Quo(factorization, normA.Sqrt(normA))
+	}
+
+	orthogonality := newFloat()
+	for i := 0; i < cols; i++ {
+		for j := 0; j < cols; j++ {
+			sum.SetInt64(0)
+			if i == j {
+				sum.SetInt64(-1)
+			}
+			for k := 0; k < rows; k++ {
+				sum.Add(sum, term.Mul(Q[k][i], Q[k][j]))
+			}
+			orthogonality.Add(orthogonality, term.Mul(sum, sum))
+		}
+	}
+	orthogonality.Sqrt(orthogonality)
+	return factorization, orthogonality
+}
+
+// decimalStrings escribe cada elemento en decimal con los dígitos justos para recuperar
+// el mismo big.Float a su precisión
+func decimalStrings(matrix [][]*big.Float) [][]string {
+	out := make([][]string, len(matrix))
+	for i, row := range matrix {
+		out[i] = make([]string, len(row))
+		for j, value := range row {
+			out[i][j] = value.Text('g', -1)
+		}
+	}
+	return out
+}
+
+// float64Matrix redondea una matriz de big.Float a float64
+func float64Matrix(matrix [][]*big.Float) [][]float64 {
+	out := make([][]float64, len(matrix))
+	for i, row := range matrix {
+		out[i] = make([]float64, len(row))
+		for j, value := range row {
+			out[i][j], _ = value.Float64()
+		}
+	}
+	return out
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"go-api/internal/apperrors"
+)
+
+// hilbert matriz de Hilbert n×n, H[i][j] = 1/(i+j+1): mal condicionada ya con n = 10
+func hilbert(n int) [][]float64 {
+	h := make([][]float64, n)
+	for i := range h {
+		h[i] = make([]float64, n)
+		for j := range h[i] {
+			h[i][j] = 1 / float64(i+j+1)
+		}
+	}
+	return h
+}
+
+// columns primeras n columnas de la matriz
+func columns(matrix [][]float64, n int) [][]float64 {
+	out := make([][]float64, len(matrix))
+	for i, row := range matrix {
+		out[i] = row[:n:n]
+	}
+	return out
+}
+
+func TestPreciseQRDecomposition(t *testing.T) {
+	tests := []struct {
+		name      string
+		matrix    [][]float64
+		prec      uint
+		tolerance float64
+	}{
+		{name: "3x3 a 53 bits", matrix: [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 10}}, prec: 53, tolerance: 1e-14},
+		{name: "4x2 a 128 bits", matrix: [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}}, prec: 128, tolerance: 1e-36},
+		{name: "columna ya triangular", matrix: [][]float64{{2, 1}, {0, 3}, {0, 4}}, prec: 64, tolerance: 1e-18},
+		{name: "Hilbert 12x12 a 256 bits", matrix: hilbert(12), prec: 256, tolerance: 1e-74},
+		{name: "Hilbert 12x12 a 1024 bits", matrix: hilbert(12), prec: 1024, tolerance: 1e-305},
+		{name: "Hilbert 8x3 a 256 bits", matrix: columns(hilbert(8), 3), prec: 256, tolerance: 1e-74},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Q, R, err := PreciseQRDecomposition(tt.matrix, tt.prec)
+			if err != nil {
+				t.Fatalf("PreciseQRDecomposition() error = %v", err)
+			}
+			if Q[0][0].Prec() != tt.prec || R[0][0].Prec() != tt.prec {
+				t.Errorf("precisión = %d, want %d", Q[0][0].Prec(), tt.prec)
+			}
+			for i := range R {
+				for j := 0; j < i && j < len(R[i]); j++ {
+					if R[i][j].Sign() != 0 {
+						t.Errorf("R[%d][%d] = %s, debería ser 0", i, j, R[i][j].Text('g', 10))
+					}
+				}
+			}
+
+			tolerance := new(big.Float).SetFloat64(tt.tolerance)
+			factorization, orthogonality := PrecisionResiduals(tt.matrix, Q, R, tt.prec)
+			if factorization.Cmp(tolerance) > 0 || orthogonality.Cmp(tolerance) > 0 {
+				t.Errorf("‖A - Q·R‖/‖A‖ = %s y ‖Qᵀ·Q - I‖ = %s, want <= %g",
+					factorization.Text('g', 6), orthogonality.Text('g', 6), tt.tolerance)
+			}
+		})
+	}
+
+	t.Run("misma convención de signos que la QR reducida en float64", func(t *testing.T) {
+		matrix := [][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}, {1, 1, 1}}
+		Q, R, _ := QRParts(matrix, true, true, true)
+		preciseQ, preciseR, _ := PreciseQRDecomposition(matrix, 200)
+		if len(preciseQ[0]) != 3 || len(preciseR) != 3 {
+			t.Fatalf("Q de %dx%d y R de %dx%d, want 4x3 y 3x3", len(preciseQ), len(preciseQ[0]), len(preciseR), len(preciseR[0]))
+		}
+		for i := range Q {
+			for j := range Q[i] {
+				if got, _ := preciseQ[i][j].Float64(); math.Abs(got-Q[i][j]) > 1e-12 {
+					t.Errorf("Q[%d][%d] = %v, want %v", i, j, got, Q[i][j])
+				}
+			}
+		}
+		for i := range R {
+			for j := range R[i] {
+				if got, _ := preciseR[i][j].Float64(); math.Abs(got-R[i][j]) > 1e-12 {
+					t.Errorf("R[%d][%d] = %v, want %v", i, j, got, R[i][j])
+				}
+			}
+		}
+	})
+
+	if _, _, err := PreciseQRDecomposition([][]float64{{1, 2}}, 64); err == nil {
+		t.Errorf("una matriz con más columnas que filas debería fallar")
+	}
+}
+
+func TestMatrixProcessor_ProcessPrecise(t *testing.T) {
+	nodeClient := NewNodeClient("http://localhost:9999")
+	nodeClient.MaxRetries = 0
+	processor := NewMatrixProcessor(nodeClient)
+	processor.PrecisionMaxElements = 100
+
+	response, err := processor.ProcessPrecise(context.Background(), [][]float64{{3, 0}, {4, 5}}, 80, ProcessOptions{SkipNodeStats: true})
+	if err != nil {
+		t.Fatalf("ProcessPrecise() error = %v", err)
+	}
+	// La primera columna (3, 4) tiene norma 5: R[0][0] = -5 exacto
+	if response.Precision != 80 || response.RDecimal[0][0] != "-5" || response.Q != nil || response.R != nil {
+		t.Errorf("respuesta = %+v, want precision 80, R[0][0] = -5 y sin q/r float64", response)
+	}
+	if response.Rotated == nil || response.Residuals == nil {
+		t.Errorf("faltan rotated o residuals: %+v", response)
+	}
+
+	// Una columna alta con el máximo de bits: con la Q completa serían 100² elementos y
+	// 100³ operaciones de verificación; la reducida tiene 100
+	tall := columns(hilbert(100), 1)
+	response, err = processor.ProcessPrecise(context.Background(), tall, DefaultPrecisionMaxBits, ProcessOptions{SkipNodeStats: true})
+	if err != nil {
+		t.Fatalf("ProcessPrecise() error = %v", err)
+	}
+	if len(response.QDecimal) != 100 || len(response.QDecimal[0]) != 1 || len(response.RDecimal) != 1 {
+		t.Errorf("Q de %dx%d y R de %d filas, want 100x1 y 1", len(response.QDecimal), len(response.QDecimal[0]), len(response.RDecimal))
+	}
+
+	tests := []struct {
+		name       string
+		matrix     [][]float64
+		precision  uint
+		wantCode   apperrors.Code
+		wantStatus int
+	}{
+		{name: "menos bits que float64", matrix: [][]float64{{1}}, precision: 32, wantCode: apperrors.CodePrecisionInvalid, wantStatus: http.StatusBadRequest},
+		{name: "más bits que el máximo", matrix: [][]float64{{1}}, precision: DefaultPrecisionMaxBits + 1, wantCode: apperrors.CodePrecisionInvalid, wantStatus: http.StatusBadRequest},
+		{name: "más elementos que el máximo", matrix: hilbert(11), precision: 64, wantCode: apperrors.CodePrecisionTooLarge, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "matriz inválida", matrix: [][]float64{{1, 2}, {3}}, precision: 64, wantCode: apperrors.CodeMatrixNotRectangular, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := processor.ProcessPrecise(context.Background(), tt.matrix, tt.precision, ProcessOptions{SkipNodeStats: true})
+			if !errors.Is(err, apperrors.New(tt.wantCode, nil)) || apperrors.From(err).Status != tt.wantStatus {
+				t.Errorf("error = %v, want %s %d", err, tt.wantCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// BenchmarkPreciseQRDecomposition compara la QR en big.Float con la de float64 (gonum) en
+// matrices de Hilbert, incluida la verificación de los residuos
+func BenchmarkPreciseQRDecomposition(b *testing.B) {
+	for _, n := range []int{10, 30} {
+		matrix := hilbert(n)
+		b.Run(fmt.Sprintf("float64/%dx%d", n, n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				QRDecomposition(matrix)
+			}
+		})
+		for _, prec := range []uint{64, 256, 1024} {
+			b.Run(fmt.Sprintf("bigfloat-%d/%dx%d", prec, n, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					Q, R, _ := PreciseQRDecomposition(matrix, prec)
+					PrecisionResiduals(matrix, Q, R, prec)
+				}
+			})
+		}
+	}
+}
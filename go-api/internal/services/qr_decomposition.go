@@ -2,58 +2,90 @@ package services
 
 import (
 	"fmt"
-	"gonum.org/v1/gonum/mat"
+	"time"
+
+	"go-api/internal/metrics"
+
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/lapack/lapack64"
 )
 
 // QRDecomposition calcula la factorización QR de una matriz usando el método de Gram-Schmidt
 // Retorna las matrices Q y R, donde Q es ortogonal y R es triangular superior
 // La matriz original debe ser A = Q * R
 func QRDecomposition(matrix [][]float64) ([][]float64, [][]float64, error) {
+	return QRParts(matrix, true, true, false)
+}
+
+// QRParts calcula solo las partes pedidas de la factorización QR; las no pedidas quedan nil
+// (sin wantQ no se construye Q). Sin thin, Q es rows×rows y R rows×cols como en
+// QRDecomposition; con thin es la factorización reducida: Q rows×cols y R cols×cols,
+// que son las primeras columnas de Q y las primeras filas de R de la completa.
+func QRParts(matrix [][]float64, wantQ, wantR, thin bool) ([][]float64, [][]float64, error) {
 	if len(matrix) == 0 || len(matrix[0]) == 0 {
 		return nil, nil, fmt.Errorf("la matriz no puede estar vacía")
 	}
 
 	rows := len(matrix)
 	cols := len(matrix[0])
+	// lapack exige rows >= cols; en los workers de lotes y jobs no hay recover
+	if rows < cols {
+		return nil, nil, fmt.Errorf("la matriz %dx%d tiene más columnas que filas", rows, cols)
+	}
+
+	start := time.Now()
+	defer func() {
+		metrics.QRDuration.WithLabelValues(metrics.SizeBucket(rows, cols)).Observe(time.Since(start).Seconds())
+	}()
 
-	// Convertir a matriz de gonum
+	// Householder sobre una copia: R queda en el triángulo superior y los reflectores debajo
 	data := make([]float64, 0, rows*cols)
 	for _, row := range matrix {
 		data = append(data, row...)
 	}
-	A := mat.NewDense(rows, cols, data)
-
-	// Calcular factorización QR
-	var qr mat.QR
-	qr.Factorize(A)
-
-	// Extraer Q
-	qDense := &mat.Dense{}
-	qr.QTo(qDense)
-
-	// Extraer R
-	rDense := &mat.Dense{}
-	qr.RTo(rDense)
-
-	// Convertir Q a [][]float64
-	Q := make([][]float64, rows)
-	for i := 0; i < rows; i++ {
-		Q[i] = make([]float64, qDense.RawMatrix().Cols)
-		for j := 0; j < qDense.RawMatrix().Cols; j++ {
-			Q[i][j] = qDense.At(i, j)
+	a := blas64.General{Rows: rows, Cols: cols, Stride: cols, Data: data}
+	tau := make([]float64, cols)
+	work := []float64{0}
+	lapack64.Geqrf(a, tau, work, -1)
+	work = make([]float64, int(work[0]))
+	lapack64.Geqrf(a, tau, work, len(work))
+
+	var Q, R [][]float64
+	if wantR {
+		rRows := rows
+		if thin {
+			rRows = cols
+		}
+		R = make([][]float64, rRows)
+		for i := range R {
+			R[i] = make([]float64, cols)
+			if i < cols {
+				copy(R[i][i:], data[i*cols+i:(i+1)*cols])
+			}
 		}
 	}
 
-	// Convertir R a [][]float64
-	R := make([][]float64, rDense.RawMatrix().Rows)
-	for i := 0; i < rDense.RawMatrix().Rows; i++ {
-		R[i] = make([]float64, cols)
-		for j := 0; j < cols; j++ {
-			R[i][j] = rDense.At(i, j)
+	if wantQ {
+		// Q se arma desde los reflectores; con thin solo sus primeras cols columnas
+		qCols := rows
+		if thin {
+			qCols = cols
+		}
+		q := blas64.General{Rows: rows, Cols: qCols, Stride: qCols, Data: make([]float64, rows*qCols)}
+		for i := 0; i < rows; i++ {
+			copy(q.Data[i*qCols:i*qCols+cols], data[i*cols:(i+1)*cols])
+		}
+		lapack64.Orgqr(q, tau, work, -1)
+		if len(work) < int(work[0]) {
+			work = make([]float64, int(work[0]))
+		}
+		lapack64.Orgqr(q, tau, work, len(work))
+
+		Q = make([][]float64, rows)
+		for i := range Q {
+			Q[i] = q.Data[i*qCols : (i+1)*qCols : (i+1)*qCols]
 		}
 	}
 
 	return Q, R, nil
 }
-
-
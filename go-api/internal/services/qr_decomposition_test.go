@@ -26,6 +26,11 @@ func TestQRDecomposition(t *testing.T) {
 			matrix:  [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
 			wantErr: false,
 		},
+		{
+			name:    "matriz con más columnas que filas",
+			matrix:  [][]float64{{1, 2, 3}, {4, 5, 6}},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -90,3 +95,73 @@ func TestQRDecomposition_Verification(t *testing.T) {
 	}
 }
 
+func TestQRParts(t *testing.T) {
+	matrix := [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 9}}
+	fullQ, fullR, err := QRDecomposition(matrix)
+	if err != nil {
+		t.Fatalf("QRDecomposition() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		wantQ, wantR bool
+		thin         bool
+		qCols, rRows int
+	}{
+		{name: "completa", wantQ: true, wantR: true, qCols: 4, rRows: 4},
+		{name: "reducida", wantQ: true, wantR: true, thin: true, qCols: 2, rRows: 2},
+		{name: "solo R", wantR: true, rRows: 4},
+		{name: "solo Q reducida", wantQ: true, thin: true, qCols: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Q, R, err := QRParts(matrix, tt.wantQ, tt.wantR, tt.thin)
+			if err != nil {
+				t.Fatalf("QRParts() error = %v", err)
+			}
+			if (Q != nil) != tt.wantQ || (R != nil) != tt.wantR {
+				t.Fatalf("Q nil = %v, R nil = %v; se pidió Q %v, R %v", Q == nil, R == nil, tt.wantQ, tt.wantR)
+			}
+			// Las partes son las primeras columnas de Q y filas de R de la factorización completa
+			if Q != nil {
+				if len(Q) != 4 || len(Q[0]) != tt.qCols {
+					t.Fatalf("Q es %dx%d, esperaba 4x%d", len(Q), len(Q[0]), tt.qCols)
+				}
+				for i := range Q {
+					for j := range Q[i] {
+						if math.Abs(Q[i][j]-fullQ[i][j]) > 1e-12 {
+							t.Errorf("Q[%d][%d] = %f, esperaba %f", i, j, Q[i][j], fullQ[i][j])
+						}
+					}
+				}
+			}
+			if R != nil {
+				if len(R) != tt.rRows || len(R[0]) != 2 {
+					t.Fatalf("R es %dx%d, esperaba %dx2", len(R), len(R[0]), tt.rRows)
+				}
+				for i := range R {
+					for j := range R[i] {
+						if math.Abs(R[i][j]-fullR[i][j]) > 1e-12 {
+							t.Errorf("R[%d][%d] = %f, esperaba %f", i, j, R[i][j], fullR[i][j])
+						}
+					}
+				}
+			}
+		})
+	}
+
+	// La reducida también reconstruye la matriz: A = Q·R con Q 4x2 y R 2x2
+	Q, R, _ := QRParts(matrix, true, true, true)
+	for i := range matrix {
+		for j := range matrix[i] {
+			var sum float64
+			for k := range R {
+				sum += Q[i][k] * R[k][j]
+			}
+			if math.Abs(sum-matrix[i][j]) > 1e-9 {
+				t.Errorf("(Q·R)[%d][%d] = %f, matrix = %f", i, j, sum, matrix[i][j])
+			}
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"fmt"
+	"math"
+)
+
+// orthoTolerance norma mínima del residuo para considerar que un vector aporta una
+// dirección nueva fuera de las columnas de Q
+const orthoTolerance = 1e-10
+
+// ThinQR factorización QR reducida A = Q·R de una matriz m×n (m ≥ n): Q es m×n con
+// columnas ortonormales y R es n×n triangular superior. Los métodos la actualizan en
+// O(m·n) con rotaciones de Givens en vez de recalcularla desde cero (O(m·n²)).
+type ThinQR struct {
+	Q [][]float64
+	R [][]float64
+}
+
+// NewThinQR calcula la factorización reducida de la matriz
+func NewThinQR(matrix [][]float64) (*ThinQR, error) {
+	// Reducida desde el inicio: la completa armaría una Q de rows×rows solo para recortarla
+	q, r, err := QRParts(matrix, true, true, true)
+	if err != nil {
+		return nil, err
+	}
+	return &ThinQR{Q: q, R: r}, nil
+}
+
+// Rows cantidad de filas de la matriz factorizada
+func (f *ThinQR) Rows() int {
+	return len(f.Q)
+}
+
+// Cols cantidad de columnas de la matriz factorizada
+func (f *ThinQR) Cols() int {
+	return len(f.R)
+}
+
+// Clone copia la factorización
+func (f *ThinQR) Clone() *ThinQR {
+	return &ThinQR{Q: copyMatrix(f.Q), R: copyMatrix(f.R)}
+}
+
+// InsertRow actualiza la factorización tras insertar la fila en la posición index
+// (index = Rows() agrega al final). Se anulan los elementos de la fila nueva con n rotaciones.
+func (f *ThinQR) InsertRow(index int, row []float64) error {
+	m, n := f.Rows(), f.Cols()
+	if index < 0 || index > m {
+		return fmt.Errorf("índice de fila %d fuera de rango [0, %d]", index, m)
+	}
+	if len(row) != n {
+		return fmt.Errorf("la fila tiene %d valores, se esperaban %d", len(row), n)
+	}
+
+	// [A; a] = [[Q, 0], [0, 1]]·[R; a]: la fila nueva de Q es e_n
+	q := make([][]float64, m+1)
+	for i := range q {
+		q[i] = make([]float64, n+1)
+		switch {
+		case i < index:
+			copy(q[i], f.Q[i])
+		case i == index:
+			q[i][n] = 1
+		default:
+			copy(q[i], f.Q[i-1])
+		}
+	}
+	r := append(copyMatrix(f.R), append([]float64(nil), row...))
+
+	for j := 0; j < n; j++ {
+		c, s := givens(r[j][j], r[n][j])
+		rotateRows(r, j, n, c, s, j)
+		rotateCols(q, j, n, c, s)
+		r[n][j] = 0
+	}
+
+	f.Q = dropLastCol(q)
+	f.R = r[:n]
+	return nil
+}
+
+// DeleteRow actualiza la factorización tras eliminar la fila index. Se rota Q hasta que
+// su fila index sea ±e_0, así la fila eliminada queda desacoplada del resto.
+func (f *ThinQR) DeleteRow(index int) error {
+	m, n := f.Rows(), f.Cols()
+	if index < 0 || index >= m {
+		return fmt.Errorf("índice de fila %d fuera de rango [0, %d)", index, m)
+	}
+	if m-1 < n {
+		return fmt.Errorf("la matriz quedaría con más columnas (%d) que filas (%d)", n, m-1)
+	}
+
+	// Extender Q con una columna ortonormal z tal que la fila index de [Q, z] tenga norma 1
+	e := make([]float64, m)
+	e[index] = 1
+	z, norm := orthogonalResidual(f.Q, e)
+	if norm > orthoTolerance {
+		scale(z, 1/norm)
+	} else {
+		// e_index ya está en el rango de Q: sirve cualquier dirección ortogonal
+		z = complementVector(f.Q)
+	}
+	q := appendCol(f.Q, z)
+	r := append(copyMatrix(f.R), make([]float64, n))
+
+	for i := n - 1; i >= 0; i-- {
+		c, s := givens(q[index][i], q[index][i+1])
+		rotateCols(q, i, i+1, c, s)
+		rotateRows(r, i, i+1, c, s, i)
+	}
+
+	// La fila index de Q es ±e_0 y la columna 0 es ±e_index: quitarlas junto con la fila 0 de R
+	newQ := make([][]float64, 0, m-1)
+	for i := range q {
+		if i != index {
+			newQ = append(newQ, q[i][1:])
+		}
+	}
+	f.Q = newQ
+	f.R = make([][]float64, n)
+	for i := 0; i < n; i++ {
+		f.R[i] = r[i+1]
+	}
+	return nil
+}
+
+// InsertColumn actualiza la factorización tras insertar la columna en la posición index
+// (index = Cols() agrega al final). La columna se proyecta sobre Q, su residuo es la
+// columna nueva de Q y las rotaciones devuelven R a la forma triangular.
+func (f *ThinQR) InsertColumn(index int, col []float64) error {
+	m, n := f.Rows(), f.Cols()
+	if index < 0 || index > n {
+		return fmt.Errorf("índice de columna %d fuera de rango [0, %d]", index, n)
+	}
+	if len(col) != m {
+		return fmt.Errorf("la columna tiene %d valores, se esperaban %d", len(col), m)
+	}
+	if m < n+1 {
+		return fmt.Errorf("la matriz quedaría con más columnas (%d) que filas (%d)", n+1, m)
+	}
+
+	w := project(f.Q, col)
+	z, rho := orthogonalResidual(f.Q, col)
+	if rho > orthoTolerance*math.Max(1, norm2(col)) {
+		scale(z, 1/rho)
+	} else {
+		// La columna es combinación de las anteriores: R tiene un cero en la diagonal
+		z, rho = complementVector(f.Q), 0
+	}
+	q := appendCol(f.Q, z)
+
+	r := make([][]float64, n+1)
+	for i := range r {
+		r[i] = make([]float64, n+1)
+		for j := 0; j <= n; j++ {
+			switch {
+			case j < index && i < n:
+				r[i][j] = f.R[i][j]
+			case j == index && i < n:
+				r[i][j] = w[i]
+			case j == index:
+				r[i][j] = rho
+			case j > index && i < n:
+				r[i][j] = f.R[i][j-1]
+			}
+		}
+	}
+
+	for i := n; i > index; i-- {
+		c, s := givens(r[i-1][index], r[i][index])
+		rotateRows(r, i-1, i, c, s, index)
+		rotateCols(q, i-1, i, c, s)
+		r[i][index] = 0
+	}
+
+	f.Q, f.R = q, r
+	return nil
+}
+
+// DeleteColumn actualiza la factorización tras eliminar la columna index: R queda
+// Hessenberg superior desde index y se vuelve a triangular con una rotación por columna.
+func (f *ThinQR) DeleteColumn(index int) error {
+	n := f.Cols()
+	if index < 0 || index >= n {
+		return fmt.Errorf("índice de columna %d fuera de rango [0, %d)", index, n)
+	}
+	if n == 1 {
+		return fmt.Errorf("no se puede eliminar la única columna")
+	}
+
+	r := make([][]float64, n)
+	for i := range r {
+		r[i] = append(append([]float64(nil), f.R[i][:index]...), f.R[i][index+1:]...)
+	}
+	q := copyMatrix(f.Q)
+	for j := index; j < n-1; j++ {
+		c, s := givens(r[j][j], r[j+1][j])
+		rotateRows(r, j, j+1, c, s, j)
+		rotateCols(q, j, j+1, c, s)
+		r[j+1][j] = 0
+	}
+
+	// La última fila de R quedó en cero: se descarta junto con la última columna de Q
+	f.Q = dropLastCol(q)
+	f.R = r[:n-1]
+	return nil
+}
+
+// RankOneUpdate actualiza la factorización de A a la de A + u·vᵀ (ej: cambiar una celda
+// es u = δ·e_i, v = e_j). Se reduce Qᵀu a un múltiplo de e_0, con lo que R + (Qᵀu)·vᵀ
+// queda Hessenberg superior, y se vuelve a triangular.
+func (f *ThinQR) RankOneUpdate(u, v []float64) error {
+	m, n := f.Rows(), f.Cols()
+	if len(u) != m {
+		return fmt.Errorf("u tiene %d valores, se esperaban %d", len(u), m)
+	}
+	if len(v) != n {
+		return fmt.Errorf("v tiene %d valores, se esperaban %d", len(v), n)
+	}
+
+	// La parte de u fuera del rango de Q se agrega como columna extra (se descarta al final)
+	w := project(f.Q, u)
+	z, rho := orthogonalResidual(f.Q, u)
+	var q [][]float64
+	r := copyMatrix(f.R)
+	if rho > orthoTolerance*math.Max(1, norm2(u)) {
+		scale(z, 1/rho)
+		q = appendCol(f.Q, z)
+		r = append(r, make([]float64, n))
+		w = append(w, rho)
+	} else {
+		q = copyMatrix(f.Q)
+	}
+	p := len(w)
+
+	for i := p - 2; i >= 0; i-- {
+		c, s := givens(w[i], w[i+1])
+		w[i], w[i+1] = c*w[i]+s*w[i+1], 0
+		rotateRows(r, i, i+1, c, s, i)
+		rotateCols(q, i, i+1, c, s)
+	}
+	for j := range v {
+		r[0][j] += w[0] * v[j]
+	}
+	for i := 0; i < p-1 && i < n; i++ {
+		c, s := givens(r[i][i], r[i+1][i])
+		rotateRows(r, i, i+1, c, s, i)
+		rotateCols(q, i, i+1, c, s)
+		r[i+1][i] = 0
+	}
+
+	if p > n {
+		q = dropLastCol(q)
+		r = r[:n]
+	}
+	f.Q, f.R = q, r
+	return nil
+}
+
+// givens retorna c, s tales que la rotación [c s; -s c] lleva (a, b) a (r, 0)
+func givens(a, b float64) (float64, float64) {
+	if b == 0 {
+		return 1, 0
+	}
+	r := math.Hypot(a, b)
+	return a / r, b / r
+}
+
+// rotateRows aplica la rotación a las filas i, k de m desde la columna from
+func rotateRows(m [][]float64, i, k int, c, s float64, from int) {
+	for j := from; j < len(m[i]); j++ {
+		x, y := m[i][j], m[k][j]
+		m[i][j] = c*x + s*y
+		m[k][j] = -s*x + c*y
+	}
+}
+
+// rotateCols aplica la rotación a las columnas i, k de m (la inversa de rotateRows por la
+// derecha, así el producto Q·R no cambia)
+func rotateCols(m [][]float64, i, k int, c, s float64) {
+	for _, row := range m {
+		x, y := row[i], row[k]
+		row[i] = c*x + s*y
+		row[k] = -s*x + c*y
+	}
+}
+
+// project retorna Qᵀx
+func project(q [][]float64, x []float64) []float64 {
+	w := make([]float64, len(q[0]))
+	for i, row := range q {
+		for j, value := range row {
+			w[j] += value * x[i]
+		}
+	}
+	return w
+}
+
+// orthogonalResidual retorna x - Q·Qᵀx y su norma. La proyección se repite una vez
+// (Gram-Schmidt con reortogonalización) para no perder ortogonalidad por cancelación.
+func orthogonalResidual(q [][]float64, x []float64) ([]float64, float64) {
+	z := append([]float64(nil), x...)
+	for pass := 0; pass < 2; pass++ {
+		w := project(q, z)
+		for i, row := range q {
+			for j, value := range row {
+				z[i] -= value * w[j]
+			}
+		}
+	}
+	return z, norm2(z)
+}
+
+// complementVector retorna un vector unitario ortogonal a las columnas de Q (requiere
+// filas > columnas). Prueba los vectores canónicos hasta encontrar uno con residuo suficiente.
+func complementVector(q [][]float64) []float64 {
+	var best []float64
+	bestNorm := 0.0
+	for k := range q {
+		e := make([]float64, len(q))
+		e[k] = 1
+		z, norm := orthogonalResidual(q, e)
+		if norm > bestNorm {
+			best, bestNorm = z, norm
+		}
+		if norm*norm >= 0.5 {
+			break
+		}
+	}
+	scale(best, 1/bestNorm)
+	return best
+}
+
+// appendCol retorna una copia de m con la columna col agregada al final
+func appendCol(m [][]float64, col []float64) [][]float64 {
+	out := make([][]float64, len(m))
+	for i, row := range m {
+		out[i] = append(append(make([]float64, 0, len(row)+1), row...), col[i])
+	}
+	return out
+}
+
+// dropLastCol descarta la última columna de m
+func dropLastCol(m [][]float64) [][]float64 {
+	for i, row := range m {
+		m[i] = row[: len(row)-1 : len(row)-1]
+	}
+	return m
+}
+
+func scale(x []float64, factor float64) {
+	for i := range x {
+		x[i] *= factor
+	}
+}
+
+func norm2(x []float64) float64 {
+	sum := 0.0
+	for _, value := range x {
+		sum += value * value
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// updateTolerance error máximo aceptado tras una o varias actualizaciones
+const updateTolerance = 1e-9
+
+// randomMatrix genera una matriz m×n reproducible con valores en [-10, 10)
+func randomMatrix(rng *rand.Rand, m, n int) [][]float64 {
+	matrix := make([][]float64, m)
+	for i := range matrix {
+		matrix[i] = randomVector(rng, n)
+	}
+	return matrix
+}
+
+func randomVector(rng *rand.Rand, n int) []float64 {
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = rng.Float64()*20 - 10
+	}
+	return v
+}
+
+// checkThinQR verifica que Q·R = A, QᵀQ = I y que R sea triangular superior n×n
+func checkThinQR(t *testing.T, f *ThinQR, a [][]float64) {
+	t.Helper()
+	m, n := len(a), len(a[0])
+	if f.Rows() != m || f.Cols() != n || len(f.Q[0]) != n || len(f.R[0]) != n {
+		t.Fatalf("dimensiones Q %dx%d, R %dx%d; want Q %dx%d, R %dx%d", len(f.Q), len(f.Q[0]), len(f.R), len(f.R[0]), m, n, n, n)
+	}
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			sum := 0.0
+			for k := 0; k < n; k++ {
+				sum += f.Q[i][k] * f.R[k][j]
+			}
+			if math.Abs(sum-a[i][j]) > updateTolerance*math.Max(1, math.Abs(a[i][j])) {
+				t.Fatalf("(Q·R)[%d][%d] = %g, want %g", i, j, sum, a[i][j])
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			dot := 0.0
+			for k := 0; k < m; k++ {
+				dot += f.Q[k][i] * f.Q[k][j]
+			}
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if math.Abs(dot-want) > updateTolerance {
+				t.Fatalf("(QᵀQ)[%d][%d] = %g, want %g", i, j, dot, want)
+			}
+		}
+		for j := 0; j < i; j++ {
+			if f.R[i][j] != 0 {
+				t.Fatalf("R[%d][%d] = %g, want 0 (triangular superior)", i, j, f.R[i][j])
+			}
+		}
+	}
+}
+
+func TestThinQR_Updates(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	base := randomMatrix(rng, 8, 4)
+	newRow := randomVector(rng, 4)
+	newCol := randomVector(rng, 8)
+	u, v := randomVector(rng, 8), randomVector(rng, 4)
+
+	tests := []struct {
+		name   string
+		update func(*ThinQR) error
+		want   func([][]float64) [][]float64
+	}{
+		{
+			name:   "agregar fila al final",
+			update: func(f *ThinQR) error { return f.InsertRow(8, newRow) },
+			want:   func(a [][]float64) [][]float64 { return append(a, newRow) },
+		},
+		{
+			name:   "insertar fila al inicio",
+			update: func(f *ThinQR) error { return f.InsertRow(0, newRow) },
+			want:   func(a [][]float64) [][]float64 { return append([][]float64{newRow}, a...) },
+		},
+		{
+			name:   "eliminar fila intermedia",
+			update: func(f *ThinQR) error { return f.DeleteRow(3) },
+			want:   func(a [][]float64) [][]float64 { return append(a[:3], a[4:]...) },
+		},
+		{
+			name:   "agregar columna al final",
+			update: func(f *ThinQR) error { return f.InsertColumn(4, newCol) },
+			want: func(a [][]float64) [][]float64 {
+				for i := range a {
+					a[i] = append(a[i], newCol[i])
+				}
+				return a
+			},
+		},
+		{
+			name:   "insertar columna intermedia",
+			update: func(f *ThinQR) error { return f.InsertColumn(1, newCol) },
+			want: func(a [][]float64) [][]float64 {
+				for i := range a {
+					a[i] = append(a[i][:1], append([]float64{newCol[i]}, a[i][1:]...)...)
+				}
+				return a
+			},
+		},
+		{
+			name:   "eliminar columna",
+			update: func(f *ThinQR) error { return f.DeleteColumn(1) },
+			want: func(a [][]float64) [][]float64 {
+				for i := range a {
+					a[i] = append(a[i][:1], a[i][2:]...)
+				}
+				return a
+			},
+		},
+		{
+			name:   "actualización de rango 1",
+			update: func(f *ThinQR) error { return f.RankOneUpdate(u, v) },
+			want: func(a [][]float64) [][]float64 {
+				for i := range a {
+					for j := range a[i] {
+						a[i][j] += u[i] * v[j]
+					}
+				}
+				return a
+			},
+		},
+		{
+			name: "cambio de una celda como rango 1",
+			update: func(f *ThinQR) error {
+				e := make([]float64, 8)
+				e[2] = 5
+				return f.RankOneUpdate(e, []float64{0, 0, 1, 0})
+			},
+			want: func(a [][]float64) [][]float64 {
+				a[2][2] += 5
+				return a
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewThinQR(base)
+			if err != nil {
+				t.Fatalf("NewThinQR() = %v", err)
+			}
+			if err := tt.update(f); err != nil {
+				t.Fatalf("update = %v", err)
+			}
+			checkThinQR(t, f, tt.want(copyMatrix(base)))
+		})
+	}
+}
+
+func TestThinQR_StreamingAppend(t *testing.T) {
+	// Agregar muchas filas seguidas no debe acumular error
+	rng := rand.New(rand.NewSource(2))
+	a := randomMatrix(rng, 5, 5)
+	f, err := NewThinQR(a)
+	if err != nil {
+		t.Fatalf("NewThinQR() = %v", err)
+	}
+	for i := 0; i < 500; i++ {
+		row := randomVector(rng, 5)
+		if err := f.InsertRow(f.Rows(), row); err != nil {
+			t.Fatalf("InsertRow() = %v", err)
+		}
+		a = append(a, row)
+	}
+	checkThinQR(t, f, a)
+}
+
+func TestNewThinQR_Tall(t *testing.T) {
+	// Con la Q completa serían 10¹⁰ elementos; la reducida tiene uno por fila
+	a := randomMatrix(rand.New(rand.NewSource(3)), 100000, 1)
+	f, err := NewThinQR(a)
+	if err != nil {
+		t.Fatalf("NewThinQR() = %v", err)
+	}
+	checkThinQR(t, f, a)
+}
+
+func TestThinQR_RankDeficient(t *testing.T) {
+	// Una columna combinación de las anteriores deja un cero en la diagonal de R
+	a := [][]float64{{1, 0}, {0, 1}, {1, 1}, {2, 0}}
+	f, err := NewThinQR(a)
+	if err != nil {
+		t.Fatalf("NewThinQR() = %v", err)
+	}
+	col := []float64{1, 1, 2, 2}
+	if err := f.InsertColumn(2, col); err != nil {
+		t.Fatalf("InsertColumn() = %v", err)
+	}
+	for i := range a {
+		a[i] = append(a[i], col[i])
+	}
+	checkThinQR(t, f, a)
+
+	// Eliminar una fila cuyo vector canónico ya está en el rango de Q
+	square := [][]float64{{1, 0}, {0, 1}, {0, 0}}
+	f, _ = NewThinQR(square)
+	if err := f.DeleteRow(2); err != nil {
+		t.Fatalf("DeleteRow() = %v", err)
+	}
+	checkThinQR(t, f, square[:2])
+}
+
+func TestThinQR_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(*ThinQR) error
+	}{
+		{name: "fila fuera de rango", update: func(f *ThinQR) error { return f.InsertRow(5, []float64{1, 2}) }},
+		{name: "fila de largo incorrecto", update: func(f *ThinQR) error { return f.InsertRow(0, []float64{1}) }},
+		{name: "eliminar fila dejando más columnas que filas", update: func(f *ThinQR) error {
+			f.DeleteRow(0)
+			return f.DeleteRow(0)
+		}},
+		{name: "columna que deja más columnas que filas", update: func(f *ThinQR) error {
+			f.InsertColumn(2, []float64{1, 0, 0})
+			return f.InsertColumn(3, []float64{0, 1, 0})
+		}},
+		{name: "columna de largo incorrecto", update: func(f *ThinQR) error { return f.InsertColumn(0, []float64{1, 2}) }},
+		{name: "eliminar la única columna", update: func(f *ThinQR) error {
+			f.DeleteColumn(0)
+			return f.DeleteColumn(0)
+		}},
+		{name: "u de largo incorrecto", update: func(f *ThinQR) error { return f.RankOneUpdate([]float64{1}, []float64{1, 2}) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewThinQR([][]float64{{1, 2}, {3, 4}, {5, 6}})
+			if err != nil {
+				t.Fatalf("NewThinQR() = %v", err)
+			}
+			if err := tt.update(f); err == nil {
+				t.Error("se esperaba error")
+			}
+		})
+	}
+}
+
+func BenchmarkThinQR_AppendRow(b *testing.B) {
+	rng := rand.New(rand.NewSource(3))
+	f, _ := NewThinQR(randomMatrix(rng, 1000, 50))
+	row := randomVector(rng, 50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := f.Clone()
+		g.InsertRow(g.Rows(), row)
+	}
+}
+
+func BenchmarkThinQR_Recompute(b *testing.B) {
+	rng := rand.New(rand.NewSource(3))
+	a := randomMatrix(rng, 1001, 50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewThinQR(a)
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go-api/internal/models"
+)
+
+// CheckFunc verifica una dependencia y retorna error si no está disponible
+type CheckFunc func(ctx context.Context) error
+
+// ReadinessChecker ejecuta las verificaciones de dependencias registradas
+// (Node.js, configuración, stores, etc.) para el endpoint /readyz
+type ReadinessChecker struct {
+	Timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
+}
+
+// NewReadinessChecker crea un checker; timeout limita la duración de cada verificación
+func NewReadinessChecker(timeout time.Duration) *ReadinessChecker {
+	return &ReadinessChecker{
+		Timeout: timeout,
+		checks:  make(map[string]CheckFunc),
+	}
+}
+
+// Register agrega (o reemplaza) una verificación con el nombre dado
+func (r *ReadinessChecker) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// Run ejecuta todas las verificaciones en paralelo y agrega los resultados.
+// El estado global es "ok" solo si todas las verificaciones pasan.
+func (r *ReadinessChecker) Run(ctx context.Context) models.ReadinessResponse {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]CheckFunc, len(names))
+	for i, name := range names {
+		checks[i] = r.checks[name]
+	}
+	r.mu.RUnlock()
+
+	results := make([]models.CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = r.runCheck(ctx, checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	response := models.ReadinessResponse{
+		Status: models.CheckStatusOK,
+		Checks: make(map[string]models.CheckResult, len(names)),
+	}
+	for i, name := range names {
+		if results[i].Status != models.CheckStatusOK {
+			response.Status = models.CheckStatusFail
+		}
+		response.Checks[name] = results[i]
+	}
+	return response
+}
+
+// runCheck ejecuta una verificación con timeout y mide su latencia
+func (r *ReadinessChecker) runCheck(ctx context.Context, check CheckFunc) models.CheckResult {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check(ctx)
+	result := models.CheckResult{
+		Status:    models.CheckStatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = models.CheckStatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// CachedCheck envuelve una verificación para que su resultado se reutilice durante ttl.
+// Evita que cada sondeo del orquestador genere una petición a la dependencia.
+func CachedCheck(check CheckFunc, ttl time.Duration) CheckFunc {
+	var (
+		mu        sync.Mutex
+		lastErr   error
+		checkedAt time.Time
+	)
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+			return lastErr
+		}
+		lastErr = check(ctx)
+		checkedAt = time.Now()
+		return lastErr
+	}
+}
+
+// EnvCheck verifica que las variables de entorno requeridas estén definidas
+func EnvCheck(keys ...string) CheckFunc {
+	return func(ctx context.Context) error {
+		var missing []string
+		for _, key := range keys {
+			if os.Getenv(key) == "" {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("variables de entorno faltantes: %v", missing)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"go-api/internal/models"
+)
+
+func TestReadinessChecker_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     map[string]CheckFunc
+		wantStatus string
+	}{
+		{
+			name:       "sin verificaciones",
+			checks:     map[string]CheckFunc{},
+			wantStatus: models.CheckStatusOK,
+		},
+		{
+			name: "todas las verificaciones pasan",
+			checks: map[string]CheckFunc{
+				"a": func(ctx context.Context) error { return nil },
+				"b": func(ctx context.Context) error { return nil },
+			},
+			wantStatus: models.CheckStatusOK,
+		},
+		{
+			name: "una verificación falla",
+			checks: map[string]CheckFunc{
+				"a": func(ctx context.Context) error { return nil },
+				"b": func(ctx context.Context) error { return errors.New("caído") },
+			},
+			wantStatus: models.CheckStatusFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewReadinessChecker(time.Second)
+			for name, check := range tt.checks {
+				checker.Register(name, check)
+			}
+
+			result := checker.Run(context.Background())
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", result.Status, tt.wantStatus)
+			}
+			if len(result.Checks) != len(tt.checks) {
+				t.Errorf("Checks tiene %d entradas, esperaba %d", len(result.Checks), len(tt.checks))
+			}
+			if b, ok := result.Checks["b"]; ok && b.Status == models.CheckStatusFail && b.Error == "" {
+				t.Error("Se esperaba el mensaje de error en la verificación fallida")
+			}
+		})
+	}
+}
+
+func TestReadinessChecker_Timeout(t *testing.T) {
+	checker := NewReadinessChecker(10 * time.Millisecond)
+	checker.Register("lento", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	result := checker.Run(context.Background())
+	if result.Checks["lento"].Status != models.CheckStatusFail {
+		t.Errorf("Se esperaba fallo por timeout, got %+v", result.Checks["lento"])
+	}
+}
+
+func TestCachedCheck(t *testing.T) {
+	calls := 0
+	check := CachedCheck(func(ctx context.Context) error {
+		calls++
+		return nil
+	}, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if err := check(context.Background()); err != nil {
+			t.Fatalf("check() error = %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("La verificación se ejecutó %d veces, esperaba 1 (cacheada)", calls)
+	}
+}
+
+func TestEnvCheck(t *testing.T) {
+	os.Setenv("READINESS_TEST_VAR", "x")
+	defer os.Unsetenv("READINESS_TEST_VAR")
+
+	if err := EnvCheck("READINESS_TEST_VAR")(context.Background()); err != nil {
+		t.Errorf("EnvCheck() error = %v, want nil", err)
+	}
+	if err := EnvCheck("READINESS_TEST_VAR", "READINESS_TEST_MISSING")(context.Background()); err == nil {
+		t.Error("EnvCheck() esperaba error por variable faltante")
+	}
+}
+
+func TestNodeClient_Ping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	if err := NewNodeClient(server.URL).Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v, want nil", err)
+	}
+	if err := NewNodeClient("http://localhost:9999").Ping(context.Background()); err == nil {
+		t.Error("Ping() esperaba error con URL inexistente")
+	}
+}
@@ -2,7 +2,8 @@ package services
 
 // RotateMatrix90Clockwise rota una matriz 90 grados en sentido horario (derecha)
 // Ejemplo: [[1,2],[3,4]] -> [[3,1],[4,2]]
-func RotateMatrix90Clockwise(matrix [][]float64) [][]float64 {
+// Sirve para cualquier tipo de elemento (float64, complex128, ...)
+func RotateMatrix90Clockwise[T any](matrix [][]T) [][]T {
 	if len(matrix) == 0 || len(matrix[0]) == 0 {
 		return matrix
 	}
@@ -11,9 +12,9 @@ func RotateMatrix90Clockwise(matrix [][]float64) [][]float64 {
 	cols := len(matrix[0])
 
 	// Crear matriz rotada con dimensiones invertidas
-	rotated := make([][]float64, cols)
+	rotated := make([][]T, cols)
 	for i := range rotated {
-		rotated[i] = make([]float64, rows)
+		rotated[i] = make([]T, rows)
 	}
 
 	// Rotar: el elemento en [i][j] va a [j][rows-1-i]
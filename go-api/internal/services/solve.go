@@ -0,0 +1,73 @@
+package services
+
+import (
+	"math"
+
+	"go-api/internal/apperrors"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Solution solución de mínimos cuadrados de A·x = b
+type Solution struct {
+	X []float64
+	// Residual norma euclídea de A·x - b (0 si el sistema es compatible)
+	Residual float64
+}
+
+// SolveLeastSquares resuelve A·x = b por mínimos cuadrados con la factorización QR de A:
+// x minimiza ‖A·x - b‖. Con A cuadrada e invertible es la solución exacta.
+// A debe tener al menos tantas filas como columnas y rango completo; si no, el sistema no
+// tiene solución única (MATRIX_RANK_DEFICIENT).
+func SolveLeastSquares(matrix [][]float64, b []float64) (*Solution, error) {
+	if err := ValidateMatrix(matrix); err != nil {
+		return nil, err
+	}
+	rows, cols := len(matrix), len(matrix[0])
+	if len(b) != rows {
+		return nil, apperrors.New(apperrors.CodeSolveDimensionMismatch, map[string]interface{}{"rows": rows, "length": len(b)})
+	}
+	if rows < cols {
+		return nil, apperrors.New(apperrors.CodeMatrixRankDeficient, map[string]interface{}{"rank": rows, "cols": cols})
+	}
+
+	data := make([]float64, 0, rows*cols)
+	for _, row := range matrix {
+		data = append(data, row...)
+	}
+	a := mat.NewDense(rows, cols, data)
+	var qr mat.QR
+	qr.Factorize(a)
+
+	// Rango numérico: elementos de la diagonal de R despreciables frente al mayor
+	var r mat.Dense
+	qr.RTo(&r)
+	largest := 0.0
+	for i := 0; i < cols; i++ {
+		largest = math.Max(largest, math.Abs(r.At(i, i)))
+	}
+	tolerance := float64(rows) * largest * 0x1p-52
+	rank := 0
+	for i := 0; i < cols; i++ {
+		if math.Abs(r.At(i, i)) > tolerance {
+			rank++
+		}
+	}
+	if rank < cols {
+		return nil, apperrors.New(apperrors.CodeMatrixRankDeficient, map[string]interface{}{"rank": rank, "cols": cols})
+	}
+
+	var x mat.VecDense
+	if err := qr.SolveVecTo(&x, false, mat.NewVecDense(rows, append([]float64(nil), b...))); err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeQRFailed, err)
+	}
+	var residual mat.VecDense
+	residual.MulVec(a, &x)
+	residual.SubVec(&residual, mat.NewVecDense(rows, b))
+
+	return &Solution{
+		X:        append([]float64(nil), x.RawVector().Data...),
+		Residual: floats.Norm(residual.RawVector().Data, 2),
+	}, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"go-api/internal/apperrors"
+)
+
+func TestSolveLeastSquares(t *testing.T) {
+	tests := []struct {
+		name     string
+		matrix   [][]float64
+		b        []float64
+		want     []float64
+		residual float64
+		wantCode apperrors.Code
+	}{
+		{
+			name:   "sistema cuadrado",
+			matrix: [][]float64{{2, 1}, {1, 3}},
+			b:      []float64{3, 5},
+			want:   []float64{0.8, 1.4},
+		},
+		{
+			// Recta y = 1 + x por los puntos (0, 1), (1, 2), (2, 4): la mejor es y = 5/6 + 3/2·x
+			name:     "sobredeterminado por mínimos cuadrados",
+			matrix:   [][]float64{{1, 0}, {1, 1}, {1, 2}},
+			b:        []float64{1, 2, 4},
+			want:     []float64{5.0 / 6, 1.5},
+			residual: math.Sqrt(1.0 / 6),
+		},
+		{
+			name:     "b con otra longitud",
+			matrix:   [][]float64{{1, 0}, {0, 1}},
+			b:        []float64{1},
+			wantCode: apperrors.CodeSolveDimensionMismatch,
+		},
+		{
+			name:     "columnas dependientes",
+			matrix:   [][]float64{{1, 2}, {2, 4}, {3, 6}},
+			b:        []float64{1, 2, 3},
+			wantCode: apperrors.CodeMatrixRankDeficient,
+		},
+		{
+			name:     "más columnas que filas",
+			matrix:   [][]float64{{1, 2, 3}},
+			b:        []float64{1},
+			wantCode: apperrors.CodeMatrixRankDeficient,
+		},
+		{
+			name:     "matriz vacía",
+			matrix:   [][]float64{},
+			wantCode: apperrors.CodeMatrixEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			solution, err := SolveLeastSquares(tt.matrix, tt.b)
+			if tt.wantCode != "" {
+				if !errors.Is(err, apperrors.New(tt.wantCode, nil)) {
+					t.Fatalf("error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SolveLeastSquares() error = %v", err)
+			}
+			for i := range tt.want {
+				if math.Abs(solution.X[i]-tt.want[i]) > 1e-12 {
+					t.Errorf("x[%d] = %v, want %v", i, solution.X[i], tt.want[i])
+				}
+			}
+			if math.Abs(solution.Residual-tt.residual) > 1e-12 {
+				t.Errorf("residuo = %v, want %v", solution.Residual, tt.residual)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+// DefaultSparseMaxElements elementos hasta los que se densifica una matriz dispersa para
+// calcular su factorización QR (rows·max(rows, cols), por la Q completa), y límite de
+// nnz + rows + cols de la rotación
+const DefaultSparseMaxElements = 1_000_000
+
+// ValidateSparse es la contraparte dispersa de ValidateMatrix: valida el formato, las
+// longitudes de los arreglos, que los índices estén dentro de rows×cols y que ninguna
+// posición se repita. Los errores son *apperrors.Error (SPARSE_INVALID,
+// SPARSE_INDEX_OUT_OF_RANGE, SPARSE_DUPLICATE_ENTRY).
+func ValidateSparse(m *models.SparseMatrix) error {
+	if m.Rows <= 0 || m.Cols <= 0 {
+		return sparseInvalid(fmt.Sprintf("rows and cols must be positive, got %dx%d", m.Rows, m.Cols))
+	}
+	for k, value := range m.Data {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return sparseInvalid(fmt.Sprintf("data[%d] is not a finite number", k))
+		}
+	}
+
+	switch m.Format {
+	case models.SparseFormatCOO:
+		if len(m.Row) != len(m.Data) || len(m.Col) != len(m.Data) {
+			return sparseInvalid(fmt.Sprintf("row, col and data must have the same length, got %d, %d and %d", len(m.Row), len(m.Col), len(m.Data)))
+		}
+	case models.SparseFormatCSR:
+		if len(m.IndPtr) != m.Rows+1 {
+			return sparseInvalid(fmt.Sprintf("indptr must have rows+1 = %d elements, got %d", m.Rows+1, len(m.IndPtr)))
+		}
+		if len(m.Indices) != len(m.Data) {
+			return sparseInvalid(fmt.Sprintf("indices and data must have the same length, got %d and %d", len(m.Indices), len(m.Data)))
+		}
+		if m.IndPtr[0] != 0 || m.IndPtr[m.Rows] != len(m.Data) {
+			return sparseInvalid(fmt.Sprintf("indptr must start at 0 and end at %d", len(m.Data)))
+		}
+		for i := 0; i < m.Rows; i++ {
+			if m.IndPtr[i+1] < m.IndPtr[i] {
+				return sparseInvalid(fmt.Sprintf("indptr must be non-decreasing, indptr[%d] > indptr[%d]", i, i+1))
+			}
+		}
+	default:
+		return sparseInvalid(fmt.Sprintf("unsupported format %q, expected coo or csr", m.Format))
+	}
+
+	seen := make(map[[2]int]struct{}, len(m.Data))
+	var err error
+	eachEntry(m, func(k, row, col int, _ float64) bool {
+		if row < 0 || row >= m.Rows || col < 0 || col >= m.Cols {
+			err = apperrors.New(apperrors.CodeSparseIndexOutOfRange, map[string]interface{}{
+				"entry": k, "row": row, "col": col, "rows": m.Rows, "cols": m.Cols,
+			})
+			return false
+		}
+		if _, dup := seen[[2]int{row, col}]; dup {
+			err = apperrors.New(apperrors.CodeSparseDuplicateEntry, map[string]interface{}{
+				"entry": k, "row": row, "col": col,
+			})
+			return false
+		}
+		seen[[2]int{row, col}] = struct{}{}
+		return true
+	})
+	return err
+}
+
+// CheckSparseSize verifica que la matriz se pueda densificar para calcular QR: la Q
+// completa es de rows×rows, así que rows·max(rows, cols) no puede superar limit
+// (SPARSE_TOO_LARGE si lo supera)
+func CheckSparseSize(m *models.SparseMatrix, limit int) error {
+	return checkSparseElements(m, m.Rows, max(m.Rows, m.Cols), limit)
+}
+
+// CheckSparseRotation acota la rotación, que no densifica pero trabaja con nnz + rows + cols
+// enteros (en CSR el indptr de la rotada tiene cols+1): su suma no puede superar limit
+// (SPARSE_TOO_LARGE si la supera)
+func CheckSparseRotation(m *models.SparseMatrix, limit int) error {
+	if m.Rows > limit || m.Cols > limit || m.Rows+m.Cols > limit-len(m.Data) {
+		elements := math.MaxInt
+		if m.Rows <= math.MaxInt/2 && m.Cols <= math.MaxInt/2 {
+			elements = m.Rows + m.Cols + len(m.Data)
+		}
+		return sparseTooLarge(m, elements, limit)
+	}
+	return nil
+}
+
+// checkSparseElements error SPARSE_TOO_LARGE si a·b (ambos positivos) supera limit
+func checkSparseElements(m *models.SparseMatrix, a, b, limit int) error {
+	if a > limit/b {
+		elements := math.MaxInt
+		if a <= math.MaxInt/b {
+			elements = a * b
+		}
+		return sparseTooLarge(m, elements, limit)
+	}
+	return nil
+}
+
+func sparseTooLarge(m *models.SparseMatrix, elements, limit int) error {
+	return apperrors.New(apperrors.CodeSparseTooLarge, map[string]interface{}{
+		"rows": m.Rows, "cols": m.Cols, "elements": elements, "max": limit,
+	})
+}
+
+func sparseInvalid(reason string) error {
+	return apperrors.New(apperrors.CodeSparseInvalid, map[string]interface{}{"reason": reason})
+}
+
+// eachEntry recorre los elementos guardados de m (k es su posición en data) hasta que fn
+// retorne false. m debe tener las longitudes de arreglos validadas.
+func eachEntry(m *models.SparseMatrix, fn func(k, row, col int, value float64) bool) {
+	if m.Format == models.SparseFormatCSR {
+		for i := 0; i < m.Rows; i++ {
+			for k := m.IndPtr[i]; k < m.IndPtr[i+1]; k++ {
+				if !fn(k, i, m.Indices[k], m.Data[k]) {
+					return
+				}
+			}
+		}
+		return
+	}
+	for k, value := range m.Data {
+		if !fn(k, m.Row[k], m.Col[k], value) {
+			return
+		}
+	}
+}
+
+// RotateSparse90Clockwise rota una matriz dispersa válida 90° en sentido horario sin
+// densificarla: el elemento en (i, j) pasa a (j, rows-1-i), como en RotateMatrix90Clockwise.
+// Conserva el formato; en CSR las filas de la rotada son las columnas de la original y se
+// arman con un conteo por columna en O(nnz + rows + cols).
+func RotateSparse90Clockwise(m *models.SparseMatrix) *models.SparseMatrix {
+	rotated := &models.SparseMatrix{Format: m.Format, Rows: m.Cols, Cols: m.Rows}
+
+	if m.Format == models.SparseFormatCOO {
+		rotated.Row = make([]int, len(m.Data))
+		rotated.Col = make([]int, len(m.Data))
+		rotated.Data = make([]float64, len(m.Data))
+		copy(rotated.Data, m.Data)
+		for k := range m.Data {
+			rotated.Row[k] = m.Col[k]
+			rotated.Col[k] = m.Rows - 1 - m.Row[k]
+		}
+		return rotated
+	}
+
+	rotated.IndPtr = make([]int, m.Cols+1)
+	for _, col := range m.Indices {
+		rotated.IndPtr[col+1]++
+	}
+	for j := 0; j < m.Cols; j++ {
+		rotated.IndPtr[j+1] += rotated.IndPtr[j]
+	}
+	rotated.Indices = make([]int, len(m.Data))
+	rotated.Data = make([]float64, len(m.Data))
+	next := append([]int(nil), rotated.IndPtr[:m.Cols]...)
+	// Recorrer las filas de abajo hacia arriba deja las columnas de la rotada en orden creciente
+	for i := m.Rows - 1; i >= 0; i-- {
+		for k := m.IndPtr[i]; k < m.IndPtr[i+1]; k++ {
+			j := m.Indices[k]
+			rotated.Indices[next[j]] = m.Rows - 1 - i
+			rotated.Data[next[j]] = m.Data[k]
+			next[j]++
+		}
+	}
+	return rotated
+}
+
+// DenseFromSparse convierte una matriz dispersa válida en densa
+func DenseFromSparse(m *models.SparseMatrix) [][]float64 {
+	data := make([]float64, m.Rows*m.Cols)
+	dense := make([][]float64, m.Rows)
+	for i := range dense {
+		dense[i] = data[i*m.Cols : (i+1)*m.Cols : (i+1)*m.Cols]
+	}
+	eachEntry(m, func(_, row, col int, value float64) bool {
+		dense[row][col] = value
+		return true
+	})
+	return dense
+}
+
+// SparseFromDense convierte una matriz densa al formato disperso indicado, guardando solo
+// los elementos distintos de cero en orden de filas
+func SparseFromDense(matrix [][]float64, format string) *models.SparseMatrix {
+	m := &models.SparseMatrix{Format: format, Rows: len(matrix), Data: []float64{}}
+	if len(matrix) > 0 {
+		m.Cols = len(matrix[0])
+	}
+	if format == models.SparseFormatCSR {
+		m.IndPtr = make([]int, 1, m.Rows+1)
+	}
+	for i, row := range matrix {
+		for j, value := range row {
+			if value == 0 {
+				continue
+			}
+			m.Data = append(m.Data, value)
+			if format == models.SparseFormatCSR {
+				m.Indices = append(m.Indices, j)
+			} else {
+				m.Row = append(m.Row, i)
+				m.Col = append(m.Col, j)
+			}
+		}
+		if format == models.SparseFormatCSR {
+			m.IndPtr = append(m.IndPtr, len(m.Data))
+		}
+	}
+	return m
+}
+
+// sparseIsSmaller indica si la matriz ocupa menos números en formato disperso que en denso
+// (rows·cols): COO guarda tres por elemento no nulo y CSR dos más los rows+1 de indptr
+func sparseIsSmaller(format string, rows, cols, nnz int) bool {
+	if format == models.SparseFormatCSR {
+		return 2*nnz+rows+1 < rows*cols
+	}
+	return 3*nnz < rows*cols
+}
+
+// countNonZero cantidad de elementos distintos de cero
+func countNonZero(matrix [][]float64) int {
+	nnz := 0
+	for _, row := range matrix {
+		for _, value := range row {
+			if value != 0 {
+				nnz++
+			}
+		}
+	}
+	return nnz
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+// sparseFixtures la misma matriz 3x4 en COO (sin orden) y CSR
+func sparseFixtures() (dense [][]float64, coo, csr *models.SparseMatrix) {
+	dense = [][]float64{
+		{1, 0, 0, 2},
+		{0, 0, 3, 0},
+		{0, 4, 0, 5},
+	}
+	coo = &models.SparseMatrix{Format: "coo", Rows: 3, Cols: 4,
+		Row: []int{2, 0, 1, 0, 2}, Col: []int{3, 0, 2, 3, 1}, Data: []float64{5, 1, 3, 2, 4}}
+	csr = &models.SparseMatrix{Format: "csr", Rows: 3, Cols: 4,
+		IndPtr: []int{0, 2, 3, 5}, Indices: []int{0, 3, 2, 1, 3}, Data: []float64{1, 2, 3, 4, 5}}
+	return dense, coo, csr
+}
+
+func TestValidateSparse(t *testing.T) {
+	tests := []struct {
+		name     string
+		matrix   models.SparseMatrix
+		wantCode apperrors.Code
+	}{
+		{name: "COO válida", matrix: models.SparseMatrix{Format: "coo", Rows: 2, Cols: 2, Row: []int{0, 1}, Col: []int{1, 0}, Data: []float64{1, 2}}},
+		{name: "CSR válida con columnas sin orden", matrix: models.SparseMatrix{Format: "csr", Rows: 2, Cols: 3, IndPtr: []int{0, 2, 2}, Indices: []int{2, 0}, Data: []float64{1, 2}}},
+		{name: "sin elementos", matrix: models.SparseMatrix{Format: "coo", Rows: 2, Cols: 2}},
+		{name: "formato desconocido", matrix: models.SparseMatrix{Format: "csc", Rows: 1, Cols: 1}, wantCode: apperrors.CodeSparseInvalid},
+		{name: "dimensiones no positivas", matrix: models.SparseMatrix{Format: "coo", Rows: 0, Cols: 2}, wantCode: apperrors.CodeSparseInvalid},
+		{name: "COO con largos distintos", matrix: models.SparseMatrix{Format: "coo", Rows: 2, Cols: 2, Row: []int{0}, Col: []int{0, 1}, Data: []float64{1}}, wantCode: apperrors.CodeSparseInvalid},
+		{name: "indptr de otro largo", matrix: models.SparseMatrix{Format: "csr", Rows: 2, Cols: 2, IndPtr: []int{0, 1}, Indices: []int{0}, Data: []float64{1}}, wantCode: apperrors.CodeSparseInvalid},
+		{name: "indptr decreciente", matrix: models.SparseMatrix{Format: "csr", Rows: 2, Cols: 2, IndPtr: []int{0, 2, 1}, Indices: []int{0}, Data: []float64{1}}, wantCode: apperrors.CodeSparseInvalid},
+		{name: "valor no finito", matrix: models.SparseMatrix{Format: "coo", Rows: 1, Cols: 1, Row: []int{0}, Col: []int{0}, Data: []float64{math.Inf(1)}}, wantCode: apperrors.CodeSparseInvalid},
+		{name: "fila fuera de rango", matrix: models.SparseMatrix{Format: "coo", Rows: 2, Cols: 2, Row: []int{2}, Col: []int{0}, Data: []float64{1}}, wantCode: apperrors.CodeSparseIndexOutOfRange},
+		{name: "columna negativa", matrix: models.SparseMatrix{Format: "csr", Rows: 1, Cols: 2, IndPtr: []int{0, 1}, Indices: []int{-1}, Data: []float64{1}}, wantCode: apperrors.CodeSparseIndexOutOfRange},
+		{name: "COO con posición repetida", matrix: models.SparseMatrix{Format: "coo", Rows: 2, Cols: 2, Row: []int{1, 0, 1}, Col: []int{1, 0, 1}, Data: []float64{1, 2, 3}}, wantCode: apperrors.CodeSparseDuplicateEntry},
+		{name: "CSR con columna repetida en una fila", matrix: models.SparseMatrix{Format: "csr", Rows: 1, Cols: 3, IndPtr: []int{0, 2}, Indices: []int{1, 1}, Data: []float64{1, 2}}, wantCode: apperrors.CodeSparseDuplicateEntry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSparse(&tt.matrix)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("ValidateSparse() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, apperrors.New(tt.wantCode, nil)) {
+				t.Errorf("ValidateSparse() error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+
+	// El error de duplicado indica el elemento que repite la posición
+	err := ValidateSparse(&tests[11].matrix)
+	if details := apperrors.From(err).Details; details["entry"] != 2 || details["row"] != 1 {
+		t.Errorf("details = %v, want entry 2 en la fila 1", details)
+	}
+}
+
+func TestRotateSparse90Clockwise(t *testing.T) {
+	dense, coo, csr := sparseFixtures()
+	want := RotateMatrix90Clockwise(dense)
+
+	for _, m := range []*models.SparseMatrix{coo, csr} {
+		t.Run(m.Format, func(t *testing.T) {
+			rotated := RotateSparse90Clockwise(m)
+			if rotated.Format != m.Format || rotated.Rows != 4 || rotated.Cols != 3 || len(rotated.Data) != len(m.Data) {
+				t.Fatalf("rotada = %+v, want %s 4x3 con %d elementos", rotated, m.Format, len(m.Data))
+			}
+			if err := ValidateSparse(rotated); err != nil {
+				t.Fatalf("la rotada no es válida: %v", err)
+			}
+			if got := DenseFromSparse(rotated); !reflect.DeepEqual(got, want) {
+				t.Errorf("rotada densificada = %v, want %v", got, want)
+			}
+		})
+	}
+
+	// En CSR las columnas de cada fila de la rotada quedan ordenadas
+	if rotated := RotateSparse90Clockwise(csr); !reflect.DeepEqual(rotated.Indices, []int{2, 0, 1, 0, 2}) {
+		t.Errorf("indices = %v, want [2 0 1 0 2]", rotated.Indices)
+	}
+}
+
+func TestSparseFromDense(t *testing.T) {
+	dense, _, csr := sparseFixtures()
+
+	if got := SparseFromDense(dense, models.SparseFormatCSR); !reflect.DeepEqual(got, csr) {
+		t.Errorf("CSR = %+v, want %+v", got, csr)
+	}
+	coo := SparseFromDense(dense, models.SparseFormatCOO)
+	if !reflect.DeepEqual(coo.Row, []int{0, 0, 1, 2, 2}) || !reflect.DeepEqual(DenseFromSparse(coo), dense) {
+		t.Errorf("COO = %+v no reconstruye %v", coo, dense)
+	}
+}
+
+func TestMatrixProcessor_ProcessSparse(t *testing.T) {
+	// Node.js no disponible: el resultado es parcial pero la rotación y QR se calculan
+	nodeClient := NewNodeClient("http://localhost:9999")
+	nodeClient.MaxRetries = 0
+	processor := NewMatrixProcessor(nodeClient)
+	processor.SparseMaxElements = 100
+
+	t.Run("diagonal: todo queda disperso", func(t *testing.T) {
+		diagonal := &models.SparseMatrix{Format: "csr", Rows: 4, Cols: 4,
+			IndPtr: []int{0, 1, 2, 3, 4}, Indices: []int{0, 1, 2, 3}, Data: []float64{1, 2, 3, 4}}
+		response, err := processor.ProcessSparse(context.Background(), diagonal, ProcessOptions{})
+		if err != nil {
+			t.Fatalf("ProcessSparse() error = %v", err)
+		}
+		if response.RotatedSparse == nil || response.QSparse == nil || response.RSparse == nil {
+			t.Fatalf("respuesta = %+v, want rotated, q y r dispersas", response)
+		}
+		if response.Rotated != nil || response.Q != nil || response.R != nil {
+			t.Errorf("las matrices dispersas no deben ir también densas")
+		}
+		if len(response.RSparse.Data) != 4 || response.RSparse.Format != "csr" {
+			t.Errorf("R = %+v, want CSR con 4 elementos", response.RSparse)
+		}
+		if response.ErrorCode != string(apperrors.CodeNodeStatsUnavailable) {
+			t.Errorf("errorCode = %q, want NODE_STATS_UNAVAILABLE", response.ErrorCode)
+		}
+	})
+
+	t.Run("Q llena vuelve densa", func(t *testing.T) {
+		_, coo, _ := sparseFixtures()
+		square := &models.SparseMatrix{Format: "coo", Rows: 4, Cols: 3,
+			Row: coo.Col, Col: coo.Row, Data: coo.Data}
+		response, err := processor.ProcessSparse(context.Background(), square, ProcessOptions{SkipNodeStats: true})
+		if err != nil {
+			t.Fatalf("ProcessSparse() error = %v", err)
+		}
+		if response.Q == nil || response.QSparse != nil {
+			t.Errorf("Q = %v, QSparse = %+v; want Q densa", response.Q, response.QSparse)
+		}
+	})
+
+	t.Run("supera el límite de densificación: solo se rota", func(t *testing.T) {
+		// 11·11 = 121 > 100 por la Q completa, aunque rows·cols = 22 no lo supera
+		tall := &models.SparseMatrix{Format: "csr", Rows: 11, Cols: 2,
+			IndPtr: []int{0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2}, Indices: []int{0, 1}, Data: []float64{1, 2}}
+		response, err := processor.ProcessSparse(context.Background(), tall, ProcessOptions{})
+		if err != nil {
+			t.Fatalf("ProcessSparse() error = %v", err)
+		}
+		if response.ErrorCode != string(apperrors.CodeSparseTooLarge) || response.Error == "" {
+			t.Errorf("errorCode = %q, want SPARSE_TOO_LARGE", response.ErrorCode)
+		}
+		if response.RotatedSparse == nil || response.RotatedSparse.Rows != 2 || response.Q != nil || response.QSparse != nil || response.NodeStats != nil {
+			t.Errorf("respuesta = %+v, want solo la rotación", response)
+		}
+	})
+
+	t.Run("la rotación supera el límite", func(t *testing.T) {
+		big := &models.SparseMatrix{Format: "coo", Rows: 1_000_000, Cols: 1_000_000}
+		_, err := processor.ProcessSparse(context.Background(), big, ProcessOptions{})
+		if !errors.Is(err, apperrors.New(apperrors.CodeSparseTooLarge, nil)) || apperrors.From(err).Status != http.StatusRequestEntityTooLarge {
+			t.Errorf("error = %v, want SPARSE_TOO_LARGE 413", err)
+		}
+	})
+
+	t.Run("índice fuera de rango", func(t *testing.T) {
+		bad := &models.SparseMatrix{Format: "coo", Rows: 2, Cols: 2, Row: []int{0}, Col: []int{5}, Data: []float64{1}}
+		_, err := processor.ProcessSparse(context.Background(), bad, ProcessOptions{})
+		if !errors.Is(err, apperrors.New(apperrors.CodeSparseIndexOutOfRange, nil)) {
+			t.Errorf("error = %v, want SPARSE_INDEX_OUT_OF_RANGE", err)
+		}
+	})
+}
@@ -1,29 +1,43 @@
 package services
 
 import (
-	"fmt"
+	"go-api/internal/apperrors"
 )
 
 // ValidateMatrix valida que la matriz sea rectangular y contenga solo valores numéricos
 // Retorna error si la matriz está vacía, no es rectangular o contiene valores no numéricos
-func ValidateMatrix(matrix [][]float64) error {
+// Los errores son *apperrors.Error (MATRIX_EMPTY, MATRIX_ROW_EMPTY, MATRIX_NOT_RECTANGULAR)
+// Sirve para cualquier tipo de elemento (float64, models.Complex, ...)
+func ValidateMatrix[T any](matrix [][]T) error {
 	if len(matrix) == 0 {
-		return fmt.Errorf("la matriz no puede estar vacía")
+		return apperrors.New(apperrors.CodeMatrixEmpty, nil)
 	}
 
 	if len(matrix[0]) == 0 {
-		return fmt.Errorf("las filas de la matriz no pueden estar vacías")
+		return apperrors.New(apperrors.CodeMatrixRowEmpty, map[string]interface{}{"row": 0})
 	}
 
 	// Verificar que todas las filas tengan el mismo tamaño (matriz rectangular)
 	expectedCols := len(matrix[0])
 	for i, row := range matrix {
 		if len(row) != expectedCols {
-			return fmt.Errorf("la matriz no es rectangular: la fila %d tiene %d columnas, se esperaban %d", i, len(row), expectedCols)
+			return apperrors.New(apperrors.CodeMatrixNotRectangular, map[string]interface{}{
+				"row":      i,
+				"cols":     len(row),
+				"expected": expectedCols,
+			})
 		}
 	}
 
 	return nil
 }
 
-
+// ValidateQRShape verifica que una matriz de rows×cols tenga al menos tantas filas como
+// columnas, como exige la factorización QR (MATRIX_WIDER_THAN_TALL). Se valida antes de
+// factorizar para responder un error del cliente y no QR_DECOMPOSITION_FAILED.
+func ValidateQRShape(rows, cols int) error {
+	if rows < cols {
+		return apperrors.New(apperrors.CodeMatrixWiderThanTall, map[string]interface{}{"rows": rows, "cols": cols})
+	}
+	return nil
+}
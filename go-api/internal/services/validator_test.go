@@ -1,7 +1,10 @@
 package services
 
 import (
+	"errors"
 	"testing"
+
+	"go-api/internal/apperrors"
 )
 
 func TestValidateMatrix(t *testing.T) {
@@ -10,6 +13,7 @@ func TestValidateMatrix(t *testing.T) {
 		matrix  [][]float64
 		wantErr bool
 		errMsg  string
+		code    apperrors.Code
 	}{
 		{
 			name:    "matriz válida 2x2",
@@ -31,24 +35,28 @@ func TestValidateMatrix(t *testing.T) {
 			matrix:  [][]float64{},
 			wantErr: true,
 			errMsg:  "la matriz no puede estar vacía",
+			code:    apperrors.CodeMatrixEmpty,
 		},
 		{
 			name:    "fila vacía",
 			matrix:  [][]float64{{}},
 			wantErr: true,
 			errMsg:  "las filas de la matriz no pueden estar vacías",
+			code:    apperrors.CodeMatrixRowEmpty,
 		},
 		{
 			name:    "matriz no rectangular - fila 1 más corta",
 			matrix:  [][]float64{{1, 2, 3}, {4, 5}},
 			wantErr: true,
 			errMsg:  "la matriz no es rectangular",
+			code:    apperrors.CodeMatrixNotRectangular,
 		},
 		{
 			name:    "matriz no rectangular - fila 1 más larga",
 			matrix:  [][]float64{{1, 2}, {3, 4, 5}},
 			wantErr: true,
 			errMsg:  "la matriz no es rectangular",
+			code:    apperrors.CodeMatrixNotRectangular,
 		},
 	}
 
@@ -64,7 +72,32 @@ func TestValidateMatrix(t *testing.T) {
 					t.Errorf("ValidateMatrix() expected error message containing '%s', got: %v", tt.errMsg, err)
 				}
 			}
+			if tt.code != "" && !errors.Is(err, apperrors.New(tt.code, nil)) {
+				t.Errorf("ValidateMatrix() error code = %v, want %s", err, tt.code)
+			}
 		})
 	}
 }
 
+func TestValidateQRShape(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows, cols int
+		wantErr    bool
+	}{
+		{name: "cuadrada", rows: 3, cols: 3},
+		{name: "más alta que ancha", rows: 4, cols: 2},
+		{name: "más ancha que alta", rows: 2, cols: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateQRShape(tt.rows, tt.cols)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateQRShape(%d, %d) = %v, wantErr %v", tt.rows, tt.cols, err, tt.wantErr)
+			}
+			if tt.wantErr && (!errors.Is(err, apperrors.New(apperrors.CodeMatrixWiderThanTall, nil)) || apperrors.From(err).Details["cols"] != tt.cols) {
+				t.Errorf("error = %v, want MATRIX_WIDER_THAN_TALL con rows y cols", err)
+			}
+		})
+	}
+}
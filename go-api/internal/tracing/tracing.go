@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exportadores soportados (variable de entorno OTEL_TRACES_EXPORTER)
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+const serviceName = "go-api"
+
+// Tracer es el tracer usado por toda la API.
+// Delega en el TracerProvider global, por lo que puede crearse antes de llamar a Setup.
+var Tracer = otel.Tracer(serviceName)
+
+// Setup configura el TracerProvider global según el exportador indicado
+// ("otlp", "stdout" o "none"/vacío para deshabilitar) y el propagador W3C (traceparent).
+// El endpoint OTLP se configura con las variables estándar OTEL_EXPORTER_OTLP_*.
+// Retorna una función para vaciar y cerrar el exportador al apagar el servidor.
+func Setup(ctx context.Context, exporter, version string) (func(context.Context) error, error) {
+	// El propagador se configura siempre: aunque el tracing esté deshabilitado,
+	// el traceparent entrante se reenvía a Node.js
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("exportador de trazas desconocido: %q (usa otlp, stdout o none)", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al crear exportador de trazas: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error al crear resource de trazas: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start inicia un span hijo del span presente en ctx
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer.Start(ctx, name, opts...)
+}
+
+// RecordError marca el span como fallido y registra el error (no hace nada si err es nil)
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,383 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/metrics"
+	"go-api/internal/models"
+	"go-api/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// Config límites y expiración de los workspaces
+type Config struct {
+	// MaxPerUser cantidad máxima de workspaces abiertos por usuario
+	MaxPerUser int
+	// MaxElements cantidad máxima de elementos de la matriz de un workspace
+	MaxElements int
+	// IdleTTL tiempo sin uso tras el cual un workspace se elimina
+	IdleTTL time.Duration
+	// RefactorEvery cada cuántas operaciones se recalcula la factorización desde la matriz
+	// para descartar el error de redondeo acumulado por las actualizaciones
+	RefactorEvery int
+	// JanitorInterval cada cuánto se eliminan los workspaces expirados
+	JanitorInterval time.Duration
+}
+
+// workspace estado guardado en memoria; mu serializa las operaciones sobre el mismo workspace
+type workspace struct {
+	mu      sync.Mutex
+	id      string
+	ownerID int
+	matrix  [][]float64
+	qr      *services.ThinQR
+	version int
+	// pending operaciones aplicadas desde la última factorización completa
+	pending   int
+	createdAt time.Time
+	updatedAt time.Time
+	deleted   bool
+}
+
+// Manager guarda matrices y su factorización QR en memoria y las actualiza por operaciones
+type Manager struct {
+	cfg Config
+	now func() time.Time
+
+	mu    sync.Mutex
+	items map[string]*workspace
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager crea un manager; el limpiador de workspaces expirados no arranca hasta llamar a Start
+func NewManager(cfg Config) *Manager {
+	if cfg.MaxPerUser < 1 {
+		cfg.MaxPerUser = 1
+	}
+	if cfg.RefactorEvery < 1 {
+		cfg.RefactorEvery = 1
+	}
+	if cfg.JanitorInterval <= 0 {
+		cfg.JanitorInterval = time.Minute
+	}
+	return &Manager{
+		cfg:   cfg,
+		now:   time.Now,
+		items: make(map[string]*workspace),
+		quit:  make(chan struct{}),
+	}
+}
+
+// Start arranca el limpiador de workspaces expirados
+func (m *Manager) Start() {
+	m.wg.Add(1)
+	go m.janitor()
+}
+
+// Stop detiene el limpiador
+func (m *Manager) Stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+// Create valida la matriz, calcula su factorización y guarda un workspace nuevo de ownerID
+func (m *Manager) Create(ctx context.Context, ownerID int, req models.WorkspaceRequest) (*models.Workspace, error) {
+	if err := services.ValidateMatrix(req.Matrix); err != nil {
+		return nil, err
+	}
+	if err := services.ValidateQRShape(len(req.Matrix), len(req.Matrix[0])); err != nil {
+		return nil, err
+	}
+	if err := m.checkSize(len(req.Matrix), len(req.Matrix[0])); err != nil {
+		return nil, err
+	}
+	qr, err := services.NewThinQR(req.Matrix)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeQRFailed, err)
+	}
+
+	now := m.now().UTC()
+	w := &workspace{
+		id:        uuid.NewString(),
+		ownerID:   ownerID,
+		matrix:    copyMatrix(req.Matrix),
+		qr:        qr,
+		version:   1,
+		createdAt: now,
+		updatedAt: now,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	open := 0
+	for _, other := range m.items {
+		if other.ownerID != ownerID {
+			continue
+		}
+		// Uno en uso (mu tomado) cuenta como abierto; los expirados no cuentan aunque
+		// el limpiador todavía no los haya eliminado
+		if other.mu.TryLock() {
+			expired := m.expired(other)
+			other.mu.Unlock()
+			if expired {
+				continue
+			}
+		}
+		open++
+	}
+	if open >= m.cfg.MaxPerUser {
+		return nil, apperrors.New(apperrors.CodeWorkspaceLimitReached, map[string]interface{}{"max": m.cfg.MaxPerUser})
+	}
+	m.items[w.id] = w
+	metrics.WorkspacesActive.Set(float64(len(m.items)))
+	slog.DebugContext(ctx, "workspace created", "workspace_id", w.id, "rows", len(w.matrix), "cols", len(w.matrix[0]))
+	return w.snapshot(m.cfg.IdleTTL, false), nil
+}
+
+// Get retorna el workspace con la matriz, Q y R; cuenta como uso y renueva la expiración
+func (m *Manager) Get(ctx context.Context, ownerID int, id string) (*models.Workspace, error) {
+	w, err := m.lookup(ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer w.mu.Unlock()
+	w.updatedAt = m.now().UTC()
+	return w.snapshot(m.cfg.IdleTTL, true), nil
+}
+
+// Apply aplica las operaciones en orden. Es atómico: si alguna falla el workspace
+// queda sin cambios y el error indica el índice de la operación rechazada.
+func (m *Manager) Apply(ctx context.Context, ownerID int, id string, ops []models.WorkspaceOperation) (*models.Workspace, error) {
+	if len(ops) == 0 {
+		return nil, apperrors.Wrap(apperrors.CodeInvalidBody, errors.New("operations debe contener al menos una operación"))
+	}
+	w, err := m.lookup(ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer w.mu.Unlock()
+
+	matrix, qr := copyMatrix(w.matrix), w.qr.Clone()
+	for i, op := range ops {
+		if matrix, err = m.apply(matrix, qr, op); err != nil {
+			var appErr *apperrors.Error
+			if errors.As(err, &appErr) {
+				return nil, appErr
+			}
+			return nil, apperrors.New(apperrors.CodeWorkspaceInvalidOperation, map[string]interface{}{
+				"index":  i,
+				"type":   op.Type,
+				"reason": err.Error(),
+			})
+		}
+	}
+
+	pending := w.pending + len(ops)
+	if pending >= m.cfg.RefactorEvery {
+		fresh, err := services.NewThinQR(matrix)
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.CodeQRFailed, err)
+		}
+		qr, pending = fresh, 0
+	}
+	for _, op := range ops {
+		metrics.WorkspaceOperationsTotal.WithLabelValues(op.Type).Inc()
+	}
+
+	w.matrix, w.qr, w.pending = matrix, qr, pending
+	w.version++
+	w.updatedAt = m.now().UTC()
+	return w.snapshot(m.cfg.IdleTTL, false), nil
+}
+
+// Delete elimina el workspace
+func (m *Manager) Delete(ctx context.Context, ownerID int, id string) error {
+	w, err := m.lookup(ownerID, id)
+	if err != nil {
+		return err
+	}
+	defer w.mu.Unlock()
+	w.deleted = true
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, id)
+	metrics.WorkspacesActive.Set(float64(len(m.items)))
+	return nil
+}
+
+// lookup retorna el workspace de ownerID con su mu tomado. Los workspaces de otros
+// usuarios se reportan como inexistentes para no revelar sus ids.
+func (m *Manager) lookup(ownerID int, id string) (*workspace, error) {
+	m.mu.Lock()
+	w, ok := m.items[id]
+	m.mu.Unlock()
+	if ok {
+		w.mu.Lock()
+		if !w.deleted && w.ownerID == ownerID && !m.expired(w) {
+			return w, nil
+		}
+		w.mu.Unlock()
+	}
+	return nil, apperrors.New(apperrors.CodeWorkspaceNotFound, map[string]interface{}{"id": id})
+}
+
+// apply aplica una operación a la matriz y a su factorización
+func (m *Manager) apply(matrix [][]float64, qr *services.ThinQR, op models.WorkspaceOperation) ([][]float64, error) {
+	rows, cols := len(matrix), len(matrix[0])
+	index := func(def int) int {
+		if op.Index == nil {
+			return def
+		}
+		return *op.Index
+	}
+
+	switch op.Type {
+	case models.WorkspaceOpInsertRow:
+		if err := m.checkSize(rows+1, cols); err != nil {
+			return nil, err
+		}
+		i := index(rows)
+		if err := qr.InsertRow(i, op.Values); err != nil {
+			return nil, err
+		}
+		matrix = append(matrix, nil)
+		copy(matrix[i+1:], matrix[i:])
+		matrix[i] = append([]float64(nil), op.Values...)
+	case models.WorkspaceOpDeleteRow:
+		if op.Index == nil {
+			return nil, errors.New("index es obligatorio")
+		}
+		i := *op.Index
+		if err := qr.DeleteRow(i); err != nil {
+			return nil, err
+		}
+		matrix = append(matrix[:i], matrix[i+1:]...)
+	case models.WorkspaceOpInsertColumn:
+		if err := m.checkSize(rows, cols+1); err != nil {
+			return nil, err
+		}
+		j := index(cols)
+		if err := qr.InsertColumn(j, op.Values); err != nil {
+			return nil, err
+		}
+		for r := range matrix {
+			matrix[r] = append(matrix[r], 0)
+			copy(matrix[r][j+1:], matrix[r][j:])
+			matrix[r][j] = op.Values[r]
+		}
+	case models.WorkspaceOpDeleteColumn:
+		if op.Index == nil {
+			return nil, errors.New("index es obligatorio")
+		}
+		j := *op.Index
+		if err := qr.DeleteColumn(j); err != nil {
+			return nil, err
+		}
+		for r := range matrix {
+			matrix[r] = append(matrix[r][:j], matrix[r][j+1:]...)
+		}
+	case models.WorkspaceOpRankOne:
+		if err := qr.RankOneUpdate(op.U, op.V); err != nil {
+			return nil, err
+		}
+		for r := range matrix {
+			for c := range matrix[r] {
+				matrix[r][c] += op.U[r] * op.V[c]
+			}
+		}
+	default:
+		return nil, fmt.Errorf("tipo de operación no soportado")
+	}
+	return matrix, nil
+}
+
+// checkSize rechaza matrices con más elementos que MaxElements
+func (m *Manager) checkSize(rows, cols int) error {
+	if m.cfg.MaxElements > 0 && rows*cols > m.cfg.MaxElements {
+		return apperrors.New(apperrors.CodeWorkspaceTooLarge, map[string]interface{}{"elements": rows * cols, "max": m.cfg.MaxElements})
+	}
+	return nil
+}
+
+// expired indica si el workspace superó IdleTTL sin uso
+func (m *Manager) expired(w *workspace) bool {
+	return m.cfg.IdleTTL > 0 && w.updatedAt.Add(m.cfg.IdleTTL).Before(m.now())
+}
+
+// janitor elimina periódicamente los workspaces expirados
+func (m *Manager) janitor() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.cfg.JanitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.quit:
+			return
+		case <-ticker.C:
+			m.deleteExpired()
+		}
+	}
+}
+
+// deleteExpired elimina los workspaces expirados y retorna cuántos eliminó
+func (m *Manager) deleteExpired() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	deleted := 0
+	for id, w := range m.items {
+		// Un workspace en uso (mu tomado) no está expirado: se revisa en la próxima pasada
+		if !w.mu.TryLock() {
+			continue
+		}
+		if m.expired(w) {
+			w.deleted = true
+			delete(m.items, id)
+			deleted++
+		}
+		w.mu.Unlock()
+	}
+	metrics.WorkspacesActive.Set(float64(len(m.items)))
+	if deleted > 0 {
+		slog.Debug("deleted expired workspaces", "count", deleted)
+	}
+	return deleted
+}
+
+// snapshot copia el estado del workspace para la respuesta; full incluye la matriz y Q
+func (w *workspace) snapshot(ttl time.Duration, full bool) *models.Workspace {
+	ws := &models.Workspace{
+		ID:        w.id,
+		OwnerID:   w.ownerID,
+		Rows:      w.qr.Rows(),
+		Cols:      w.qr.Cols(),
+		Version:   w.version,
+		R:         copyMatrix(w.qr.R),
+		CreatedAt: w.createdAt,
+		UpdatedAt: w.updatedAt,
+		ExpiresAt: w.updatedAt.Add(ttl),
+	}
+	if full {
+		ws.Matrix = copyMatrix(w.matrix)
+		ws.Q = copyMatrix(w.qr.Q)
+	}
+	return ws
+}
+
+// copyMatrix copia profunda de una matriz
+func copyMatrix(matrix [][]float64) [][]float64 {
+	out := make([][]float64, len(matrix))
+	for i, row := range matrix {
+		out[i] = append([]float64(nil), row...)
+	}
+	return out
+}
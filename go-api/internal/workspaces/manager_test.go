@@ -0,0 +1,194 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-api/internal/apperrors"
+	"go-api/internal/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// checkFactorization verifica que Q·R reproduzca la matriz guardada
+func checkFactorization(t *testing.T, ws *models.Workspace) {
+	t.Helper()
+	for i := range ws.Matrix {
+		for j := range ws.Matrix[i] {
+			sum := 0.0
+			for k := range ws.R {
+				sum += ws.Q[i][k] * ws.R[k][j]
+			}
+			if math.Abs(sum-ws.Matrix[i][j]) > 1e-9 {
+				t.Fatalf("(Q·R)[%d][%d] = %g, want %g", i, j, sum, ws.Matrix[i][j])
+			}
+		}
+	}
+}
+
+func TestManager_Apply(t *testing.T) {
+	m := NewManager(Config{MaxPerUser: 5, IdleTTL: time.Hour, RefactorEvery: 100})
+	ctx := context.Background()
+	ws, err := m.Create(ctx, 1, models.WorkspaceRequest{Matrix: [][]float64{{1, 2}, {3, 4}, {5, 6}}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if ws.Q != nil || ws.Matrix != nil || len(ws.R) != 2 {
+		t.Errorf("Create debe retornar solo R: %+v", ws)
+	}
+
+	tests := []struct {
+		name       string
+		ops        []models.WorkspaceOperation
+		wantMatrix [][]float64
+		wantCode   apperrors.Code
+	}{
+		{
+			name:       "agregar fila al final",
+			ops:        []models.WorkspaceOperation{{Type: models.WorkspaceOpInsertRow, Values: []float64{7, 8}}},
+			wantMatrix: [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}},
+		},
+		{
+			name: "varias operaciones en orden",
+			ops: []models.WorkspaceOperation{
+				{Type: models.WorkspaceOpDeleteRow, Index: intPtr(0)},
+				{Type: models.WorkspaceOpInsertColumn, Index: intPtr(0), Values: []float64{1, 0, 2}},
+				{Type: models.WorkspaceOpRankOne, U: []float64{1, 0, 0}, V: []float64{0, 1, 1}},
+			},
+			wantMatrix: [][]float64{{1, 4, 5}, {0, 5, 6}, {2, 7, 8}},
+		},
+		{
+			name:       "eliminar columna",
+			ops:        []models.WorkspaceOperation{{Type: models.WorkspaceOpDeleteColumn, Index: intPtr(2)}},
+			wantMatrix: [][]float64{{1, 4}, {0, 5}, {2, 7}},
+		},
+		{
+			name: "una operación inválida no aplica ninguna",
+			ops: []models.WorkspaceOperation{
+				{Type: models.WorkspaceOpInsertRow, Values: []float64{1, 1}},
+				{Type: models.WorkspaceOpDeleteRow, Index: intPtr(9)},
+			},
+			wantMatrix: [][]float64{{1, 4}, {0, 5}, {2, 7}},
+			wantCode:   apperrors.CodeWorkspaceInvalidOperation,
+		},
+		{
+			name:       "tipo desconocido",
+			ops:        []models.WorkspaceOperation{{Type: "transpose"}},
+			wantMatrix: [][]float64{{1, 4}, {0, 5}, {2, 7}},
+			wantCode:   apperrors.CodeWorkspaceInvalidOperation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.Apply(ctx, 1, ws.ID, tt.ops)
+			if tt.wantCode != "" {
+				if !errors.Is(err, apperrors.New(tt.wantCode, nil)) {
+					t.Fatalf("Apply() = %v, want %s", err, tt.wantCode)
+				}
+			} else if err != nil {
+				t.Fatalf("Apply() = %v", err)
+			}
+
+			got, err := m.Get(ctx, 1, ws.ID)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			for i := range tt.wantMatrix {
+				for j := range tt.wantMatrix[i] {
+					if got.Matrix[i][j] != tt.wantMatrix[i][j] {
+						t.Fatalf("matrix = %v, want %v", got.Matrix, tt.wantMatrix)
+					}
+				}
+			}
+			checkFactorization(t, got)
+		})
+	}
+}
+
+func TestManager_RefactorEvery(t *testing.T) {
+	m := NewManager(Config{MaxPerUser: 1, IdleTTL: time.Hour, RefactorEvery: 3})
+	ctx := context.Background()
+	ws, _ := m.Create(ctx, 1, models.WorkspaceRequest{Matrix: [][]float64{{1, 2}, {3, 4}}})
+
+	for i := 0; i < 10; i++ {
+		if _, err := m.Apply(ctx, 1, ws.ID, []models.WorkspaceOperation{{Type: models.WorkspaceOpInsertRow, Values: []float64{float64(i), 1}}}); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+	}
+	got, _ := m.Get(ctx, 1, ws.ID)
+	if got.Version != 11 || got.Rows != 12 {
+		t.Errorf("version = %d, rows = %d; want 11 y 12", got.Version, got.Rows)
+	}
+	if pending := m.items[ws.ID].pending; pending != 1 {
+		t.Errorf("pending = %d, want 1 (refactorizado tras la novena operación)", pending)
+	}
+	checkFactorization(t, got)
+}
+
+func TestManager_Limits(t *testing.T) {
+	m := NewManager(Config{MaxPerUser: 1, MaxElements: 6, IdleTTL: time.Minute})
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := m.Create(ctx, 1, models.WorkspaceRequest{Matrix: [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}}); !errors.Is(err, apperrors.New(apperrors.CodeWorkspaceTooLarge, nil)) {
+		t.Errorf("Create() de 9 elementos = %v, want WORKSPACE_TOO_LARGE", err)
+	}
+	if _, err := m.Create(ctx, 1, models.WorkspaceRequest{Matrix: [][]float64{{1, 2, 3}}}); !errors.Is(err, apperrors.New(apperrors.CodeMatrixWiderThanTall, nil)) || apperrors.From(err).Status != http.StatusBadRequest {
+		t.Errorf("Create() de 1x3 = %v, want MATRIX_WIDER_THAN_TALL 400", err)
+	}
+	ws, err := m.Create(ctx, 1, models.WorkspaceRequest{Matrix: [][]float64{{1, 2}, {3, 4}}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := m.Create(ctx, 1, models.WorkspaceRequest{Matrix: [][]float64{{1}}}); !errors.Is(err, apperrors.New(apperrors.CodeWorkspaceLimitReached, nil)) {
+		t.Errorf("segundo Create() = %v, want WORKSPACE_LIMIT_REACHED", err)
+	}
+	if _, err := m.Create(ctx, 2, models.WorkspaceRequest{Matrix: [][]float64{{1}}}); err != nil {
+		t.Errorf("Create() de otro usuario = %v", err)
+	}
+	if _, err := m.Apply(ctx, 1, ws.ID, []models.WorkspaceOperation{
+		{Type: models.WorkspaceOpInsertRow, Values: []float64{5, 6}},
+		{Type: models.WorkspaceOpInsertRow, Values: []float64{7, 8}},
+	}); !errors.Is(err, apperrors.New(apperrors.CodeWorkspaceTooLarge, nil)) {
+		t.Errorf("Apply() que supera el máximo = %v, want WORKSPACE_TOO_LARGE", err)
+	}
+	if _, err := m.Get(ctx, 2, ws.ID); !errors.Is(err, apperrors.New(apperrors.CodeWorkspaceNotFound, nil)) {
+		t.Errorf("Get() de otro usuario = %v, want WORKSPACE_NOT_FOUND", err)
+	}
+
+	// Tras IdleTTL sin uso expira y deja de contar para el límite
+	now = now.Add(2 * time.Minute)
+	if _, err := m.Get(ctx, 1, ws.ID); !errors.Is(err, apperrors.New(apperrors.CodeWorkspaceNotFound, nil)) {
+		t.Errorf("Get() expirado = %v, want WORKSPACE_NOT_FOUND", err)
+	}
+	if _, err := m.Create(ctx, 1, models.WorkspaceRequest{Matrix: [][]float64{{1}}}); err != nil {
+		t.Errorf("Create() tras expirar = %v", err)
+	}
+	if deleted := m.deleteExpired(); deleted != 2 {
+		t.Errorf("deleteExpired() = %d, want 2", deleted)
+	}
+}
+
+func TestManager_Delete(t *testing.T) {
+	m := NewManager(Config{MaxPerUser: 1, IdleTTL: time.Hour})
+	ctx := context.Background()
+	ws, _ := m.Create(ctx, 1, models.WorkspaceRequest{Matrix: [][]float64{{1}}})
+
+	if err := m.Delete(ctx, 2, ws.ID); !errors.Is(err, apperrors.New(apperrors.CodeWorkspaceNotFound, nil)) {
+		t.Errorf("Delete() de otro usuario = %v, want WORKSPACE_NOT_FOUND", err)
+	}
+	if err := m.Delete(ctx, 1, ws.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := m.Get(ctx, 1, ws.ID); !errors.Is(err, apperrors.New(apperrors.CodeWorkspaceNotFound, nil)) {
+		t.Errorf("Get() eliminado = %v, want WORKSPACE_NOT_FOUND", err)
+	}
+}
@@ -50,8 +50,34 @@ function getMatrixStats(req, res) {
   }
 }
 
+/**
+ * Calcula estadísticas para varios conjuntos de matrices (usado por el batch de Go API)
+ * POST /matrix/stats/batch
+ * Body: { items: [{ q, r, rotated }, ...] }
+ * Respuesta: { results: [stats | { error }, ...] } en el mismo orden que items
+ */
+function getMatrixStatsBatch(req, res) {
+  try {
+    const { items } = req.body;
+
+    if (!Array.isArray(items)) {
+      return res.status(400).json({
+        error: 'Se requiere el array items en el body'
+      });
+    }
+
+    res.status(200).json({ results: statsService.calculateStatsBatch(items) });
+  } catch (error) {
+    console.error('Error al calcular estadísticas del lote:', error);
+    res.status(500).json({
+      error: 'Error interno del servidor: ' + error.message
+    });
+  }
+}
+
 module.exports = {
-  getMatrixStats
+  getMatrixStats,
+  getMatrixStatsBatch
 };
 
 
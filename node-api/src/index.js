@@ -18,7 +18,8 @@ const PORT = process.env.PORT || 3001;
 const startTime = new Date();
 
 // Middlewares
-app.use(express.json());
+// El límite cubre los lotes de /matrix/stats/batch (el default de express es 100kb)
+app.use(express.json({ limit: process.env.JSON_BODY_LIMIT || '10mb' }));
 app.use(express.urlencoded({ extended: true }));
 
 // CORS para permitir requests desde el frontend
@@ -66,6 +67,7 @@ app.get('/', (req, res) => {
     endpoints: {
       health: 'GET /health',
       matrixStats: 'POST /matrix/stats (requiere JWT)',
+      matrixStatsBatch: 'POST /matrix/stats/batch (requiere JWT)',
       info: 'GET /',
       note: 'Login disponible en Go API: POST http://localhost:3000/auth/login'
     },
@@ -9,6 +9,9 @@ const matrixController = require('../controllers/matrixController');
 // POST /matrix/stats - Calcula estadísticas sobre matrices
 router.post('/stats', matrixController.getMatrixStats);
 
+// POST /matrix/stats/batch - Calcula estadísticas para varios conjuntos de matrices
+router.post('/stats/batch', matrixController.getMatrixStatsBatch);
+
 module.exports = router;
 
 
@@ -113,8 +113,28 @@ function isDiagonal(matrix) {
   return true;
 }
 
+/**
+ * Calcula estadísticas para varios conjuntos de matrices en una sola llamada.
+ * Un elemento inválido no hace fallar al resto: su posición contiene { error }.
+ * @param {Array<Object>} items - Lista de objetos con matrices q, r y rotated (opcional)
+ * @returns {Array<Object>} Por cada elemento, sus estadísticas o { error }
+ */
+function calculateStatsBatch(items) {
+  return items.map((item) => {
+    try {
+      if (!item || !Array.isArray(item.q) || !Array.isArray(item.r)) {
+        throw new Error('Se requieren las matrices q y r');
+      }
+      return calculateStats(item);
+    } catch (error) {
+      return { error: error.message };
+    }
+  });
+}
+
 module.exports = {
   calculateStats,
+  calculateStatsBatch,
   isDiagonal
 };
 
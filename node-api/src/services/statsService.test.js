@@ -1,4 +1,4 @@
-const { calculateStats, isDiagonal } = require('./statsService');
+const { calculateStats, calculateStatsBatch, isDiagonal } = require('./statsService');
 
 describe('statsService', () => {
   describe('calculateStats', () => {
@@ -177,5 +177,29 @@ describe('statsService', () => {
       expect(result.anyDiagonal).toBe(false);
     });
   });
-});
 
+  describe('calculateStatsBatch', () => {
+    test('debe calcular estadísticas para cada elemento', () => {
+      const results = calculateStatsBatch([
+        { q: [[1, 0], [0, 1]], r: [[2, 0], [0, 3]] },
+        { q: [[1, 2], [3, 4]], r: [[5, 6], [7, 8]] }
+      ]);
+
+      expect(results).toHaveLength(2);
+      expect(results[0].max).toBe(3);
+      expect(results[1].sum).toBe(36);
+    });
+
+    test('debe reportar errores por elemento sin fallar el lote', () => {
+      const results = calculateStatsBatch([
+        { q: [], r: [] },
+        { r: [[1]] },
+        { q: [[1]], r: [[2]] }
+      ]);
+
+      expect(results[0].error).toContain('No se encontraron valores numéricos válidos');
+      expect(results[1].error).toBe('Se requieren las matrices q y r');
+      expect(results[2].max).toBe(2);
+    });
+  });
+});
@@ -11,7 +11,7 @@ echo ""
 
 # Paso 2: Obtener tokens
 echo "2. Obteniendo tokens JWT..."
-TOKEN_GO=$(curl -X POST http://localhost:3000/auth/login \
+TOKEN_GO=$(curl -X POST http://localhost:3000/v1/auth/login \
   -H "Content-Type: application/json" \
   -d '{"username": "admin", "password": "admin"}' \
   -s | jq -r '.token')
@@ -26,7 +26,7 @@ echo ""
 
 # Paso 3: Procesar matriz
 echo "3. Procesando matriz..."
-RESPONSE=$(curl -X POST http://localhost:3000/matrix/process \
+RESPONSE=$(curl -X POST http://localhost:3000/v1/matrix/process \
   -H "Content-Type: application/json" \
   -H "Authorization: Bearer $TOKEN_GO" \
   -d '{